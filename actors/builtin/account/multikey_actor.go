@@ -0,0 +1,250 @@
+package account
+
+import (
+	"bytes"
+
+	addr "github.com/filecoin-project/go-address"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
+	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
+	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
+	exitcode "github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// The maximum number of keys held by a multi-key account.
+const MaxMultiKeyAccountKeys = 16
+
+// Prefix of the bytes signed to authorize a key rotation.
+const SignatureDomainSeparation_RotateKeys = "fil_rotatekeys:"
+
+// Prefix of the bytes signed to authorize a send from a multi-key account.
+const SignatureDomainSeparation_MultiKeySend = "fil_multikeysend:"
+
+// A multi-key account is an account whose payloads must be signed by a threshold of its public keys.
+// It is created through the init actor, and its keys may be rotated with the approval of a threshold of its
+// current keys, so that the account survives the compromise of fewer keys than the threshold.
+// Funds are sent from the account by messages approved by a threshold of its keys.
+type MultiKeyActor struct{}
+
+func (a MultiKeyActor) Exports() []interface{} {
+	return []interface{}{
+		builtin.MethodConstructor: a.Constructor,
+		2:                         a.RotateKeys,
+		3:                         a.VerifyThresholdSignature,
+		4:                         a.Send,
+	}
+}
+
+var _ abi.Invokee = MultiKeyActor{}
+
+type MultiKeyState struct {
+	// BLS or SECP public key addresses.
+	Keys []addr.Address
+	// The number of distinct keys that must sign a payload.
+	Threshold uint64
+	// Incremented with each key rotation, to prevent replay of rotation approvals.
+	RotationNonce uint64
+	// Incremented with each send, to prevent replay of send approvals.
+	SendNonce uint64
+}
+
+type MultiKeyConstructorParams struct {
+	Keys      []addr.Address
+	Threshold uint64
+}
+
+func (a MultiKeyActor) Constructor(rt vmr.Runtime, params *MultiKeyConstructorParams) *adt.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.InitActorAddr)
+	validateKeys(rt, params.Keys, params.Threshold)
+
+	st := MultiKeyState{Keys: params.Keys, Threshold: params.Threshold, RotationNonce: 0, SendNonce: 0}
+	rt.State().Create(&st)
+	return nil
+}
+
+// A signature by one of an account's keys, identified by its index.
+type KeySignature struct {
+	KeyIndex  uint64
+	Signature crypto.Signature
+}
+
+// Signatures by a threshold of an account's keys, carried as the Data of a single crypto.Signature where a payload
+// holds one signature, such as a payment channel voucher. The Type of that signature is not used.
+type ThresholdSignature struct {
+	Signatures []KeySignature
+}
+
+// A key rotation, approved by a threshold of the account's current keys.
+type KeyRotation struct {
+	Keys      []addr.Address
+	Threshold uint64
+	// Must equal the account's RotationNonce.
+	Nonce uint64
+}
+
+type RotateKeysParams struct {
+	Rotation   KeyRotation
+	Signatures []KeySignature
+}
+
+// RotateKeys replaces the account's keys and threshold.
+// Any caller may submit a rotation, which must be signed by a threshold of the current keys.
+func (a MultiKeyActor) RotateKeys(rt vmr.Runtime, params *RotateKeysParams) *adt.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+	validateKeys(rt, params.Rotation.Keys, params.Rotation.Threshold)
+
+	var st MultiKeyState
+	rt.State().Transaction(&st, func() {
+		if params.Rotation.Nonce != st.RotationNonce {
+			rt.Abortf(exitcode.ErrIllegalArgument, "rotation nonce %d does not match account nonce %d",
+				params.Rotation.Nonce, st.RotationNonce)
+		}
+
+		plaintext, err := params.Rotation.SigningBytes(rt.Message().Receiver())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to serialize key rotation")
+		verifyThresholdSignature(rt, &st, plaintext, params.Signatures)
+
+		st.Keys = params.Rotation.Keys
+		st.Threshold = params.Rotation.Threshold
+		st.RotationNonce++
+	})
+	return nil
+}
+
+// SigningBytes returns the bytes that the keys of the account at address must sign to approve a rotation.
+// The address binds the approval to a single account.
+func (r *KeyRotation) SigningBytes(account addr.Address) ([]byte, error) {
+	buf := bytes.NewBufferString(SignatureDomainSeparation_RotateKeys)
+	buf.Write(account.Bytes())
+	if err := r.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// A message sent from the account, approved by a threshold of the account's keys.
+type KeySend struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+	// Must equal the account's SendNonce.
+	Nonce uint64
+}
+
+type SendParams struct {
+	Send       KeySend
+	Signatures []KeySignature
+}
+
+type SendReturn struct {
+	// The exit code of the sent message.
+	Code exitcode.ExitCode
+	// The return value of the sent message.
+	Ret []byte
+}
+
+// Send sends a message, such as a transfer of funds, from the account.
+// Any caller may submit a message, which must be signed by a threshold of the account's keys.
+// The approval is consumed even if the message fails, so it cannot be replayed.
+func (a MultiKeyActor) Send(rt vmr.Runtime, params *SendParams) *SendReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if params.Send.Value.Sign() < 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "value to send must be non-negative, was %v", params.Send.Value)
+	}
+
+	var st MultiKeyState
+	rt.State().Transaction(&st, func() {
+		if params.Send.Nonce != st.SendNonce {
+			rt.Abortf(exitcode.ErrIllegalArgument, "send nonce %d does not match account nonce %d",
+				params.Send.Nonce, st.SendNonce)
+		}
+
+		plaintext, err := params.Send.SigningBytes(rt.Message().Receiver())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to serialize send")
+		verifyThresholdSignature(rt, &st, plaintext, params.Signatures)
+
+		st.SendNonce++
+	})
+
+	ret, code := rt.Send(params.Send.To, params.Send.Method, vmr.CBORBytes(params.Send.Params), params.Send.Value)
+	var out vmr.CBORBytes
+	err := ret.Into(&out)
+	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to deserialize result")
+	return &SendReturn{Code: code, Ret: out}
+}
+
+// SigningBytes returns the bytes that the keys of the account at address must sign to approve a send.
+// The address binds the approval to a single account.
+func (s *KeySend) SigningBytes(account addr.Address) ([]byte, error) {
+	buf := bytes.NewBufferString(SignatureDomainSeparation_MultiKeySend)
+	buf.Write(account.Bytes())
+	if err := s.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type VerifyThresholdSignatureParams struct {
+	Plaintext  []byte
+	Signatures []KeySignature
+}
+
+// VerifyThresholdSignature checks that a payload is signed by a threshold of the account's keys, aborting if not.
+// Other actors may call this method to validate payloads signed by a multi-key account, where they would verify
+// the signature of a single-key account with the VerifySignature syscall.
+func (a MultiKeyActor) VerifyThresholdSignature(rt vmr.Runtime, params *VerifyThresholdSignatureParams) *adt.EmptyValue {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st MultiKeyState
+	rt.State().Readonly(&st)
+	verifyThresholdSignature(rt, &st, params.Plaintext, params.Signatures)
+	return nil
+}
+
+func validateKeys(rt vmr.Runtime, keys []addr.Address, threshold uint64) {
+	if len(keys) < 1 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "must have at least one key")
+	}
+	if len(keys) > MaxMultiKeyAccountKeys {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot have more than %d keys, got %d", MaxMultiKeyAccountKeys, len(keys))
+	}
+	if threshold < 1 || threshold > uint64(len(keys)) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "threshold %d must be between 1 and the number of keys %d", threshold, len(keys))
+	}
+
+	seen := make(map[addr.Address]struct{}, len(keys))
+	for _, key := range keys {
+		switch key.Protocol() {
+		case addr.SECP256K1, addr.BLS:
+		default:
+			rt.Abortf(exitcode.ErrIllegalArgument, "key %v must use BLS or SECP protocol, got %v", key, key.Protocol())
+		}
+		if _, ok := seen[key]; ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "duplicate key not allowed: %v", key)
+		}
+		seen[key] = struct{}{}
+	}
+}
+
+// Aborts unless the signatures include valid signatures of plaintext by at least a threshold of distinct keys.
+func verifyThresholdSignature(rt vmr.Runtime, st *MultiKeyState, plaintext []byte, signatures []KeySignature) {
+	signed := make(map[uint64]struct{}, len(signatures))
+	for _, ks := range signatures {
+		if ks.KeyIndex >= uint64(len(st.Keys)) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "key index %d out of range for %d keys", ks.KeyIndex, len(st.Keys))
+		}
+		if _, ok := signed[ks.KeyIndex]; ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "duplicate signature by key %d", ks.KeyIndex)
+		}
+		key := st.Keys[ks.KeyIndex]
+		err := rt.Syscalls().VerifySignature(ks.Signature, key, plaintext)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signature by key %v", key)
+		signed[ks.KeyIndex] = struct{}{}
+	}
+	if uint64(len(signed)) < st.Threshold {
+		rt.Abortf(exitcode.ErrForbidden, "payload signed by %d keys, threshold is %d", len(signed), st.Threshold)
+	}
+}
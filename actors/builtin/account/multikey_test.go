@@ -0,0 +1,304 @@
+package account_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
+	account "github.com/filecoin-project/specs-actors/actors/builtin/account"
+	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
+	runtime "github.com/filecoin-project/specs-actors/actors/runtime"
+	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	mock "github.com/filecoin-project/specs-actors/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestMultiKeyExports(t *testing.T) {
+	mock.CheckActorExports(t, account.MultiKeyActor{})
+}
+
+func TestMultiKeyConstructor(t *testing.T) {
+	actor := account.MultiKeyActor{}
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID)
+
+	key1 := tutil.NewBLSAddr(t, 1)
+	key2 := tutil.NewSECP256K1Addr(t, "key2")
+
+	testCases := []struct {
+		desc      string
+		keys      []address.Address
+		threshold uint64
+		exitCode  exitcode.ExitCode
+	}{
+		{"happy path", []address.Address{key1, key2}, 2, exitcode.Ok},
+		{"no keys", nil, 0, exitcode.ErrIllegalArgument},
+		{"zero threshold", []address.Address{key1, key2}, 0, exitcode.ErrIllegalArgument},
+		{"threshold exceeds keys", []address.Address{key1, key2}, 3, exitcode.ErrIllegalArgument},
+		{"duplicate key", []address.Address{key1, key1}, 1, exitcode.ErrIllegalArgument},
+		{"ID address key", []address.Address{key1, tutil.NewIDAddr(t, 1)}, 1, exitcode.ErrIllegalArgument},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rt := builder.Build(t)
+			rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+			params := &account.MultiKeyConstructorParams{Keys: tc.keys, Threshold: tc.threshold}
+			if tc.exitCode.IsSuccess() {
+				rt.Call(actor.Constructor, params)
+
+				var st account.MultiKeyState
+				rt.GetState(&st)
+				assert.Equal(t, tc.keys, st.Keys)
+				assert.Equal(t, tc.threshold, st.Threshold)
+				assert.Equal(t, uint64(0), st.RotationNonce)
+			} else {
+				rt.ExpectAbort(tc.exitCode, func() {
+					rt.Call(actor.Constructor, params)
+				})
+			}
+			rt.Verify()
+		})
+	}
+}
+
+func TestVerifyThresholdSignature(t *testing.T) {
+	h := newMultiKeyHarness(t)
+	plaintext := []byte("payload")
+
+	t.Run("accepts a threshold of signatures", func(t *testing.T) {
+		rt := h.build(t)
+		sigs := h.sign(rt, plaintext, 0, 2)
+		h.verify(rt, plaintext, sigs, exitcode.Ok)
+	})
+
+	t.Run("rejects fewer signatures than the threshold", func(t *testing.T) {
+		rt := h.build(t)
+		sigs := h.sign(rt, plaintext, 1)
+		h.verify(rt, plaintext, sigs, exitcode.ErrForbidden)
+	})
+
+	t.Run("rejects repeated signatures by a key", func(t *testing.T) {
+		rt := h.build(t)
+		sigs := h.sign(rt, plaintext, 1)
+		sigs = append(sigs, sigs[0])
+		h.verify(rt, plaintext, sigs, exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("rejects an invalid signature", func(t *testing.T) {
+		rt := h.build(t)
+		sigs := h.sign(rt, plaintext, 0)
+		bad := account.KeySignature{KeyIndex: 1, Signature: crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("bad")}}
+		rt.ExpectVerifySignature(bad.Signature, h.keys[1], plaintext, exitcode.ErrIllegalArgument.Wrapf("bad signature"))
+		h.verify(rt, plaintext, append(sigs, bad), exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("rejects an unknown key index", func(t *testing.T) {
+		rt := h.build(t)
+		sigs := []account.KeySignature{{KeyIndex: 3, Signature: crypto.Signature{Type: crypto.SigTypeBLS}}}
+		h.verify(rt, plaintext, sigs, exitcode.ErrIllegalArgument)
+	})
+}
+
+func TestRotateKeys(t *testing.T) {
+	h := newMultiKeyHarness(t)
+	newKeys := []address.Address{h.keys[0], tutil.NewBLSAddr(t, 4)}
+
+	t.Run("rotates keys approved by a threshold", func(t *testing.T) {
+		rt := h.build(t)
+		rotation := account.KeyRotation{Keys: newKeys, Threshold: 1, Nonce: 0}
+		h.rotate(rt, &rotation, exitcode.Ok, 1, 2)
+
+		var st account.MultiKeyState
+		rt.GetState(&st)
+		assert.Equal(t, newKeys, st.Keys)
+		assert.Equal(t, uint64(1), st.Threshold)
+		assert.Equal(t, uint64(1), st.RotationNonce)
+
+		// the approval cannot be replayed
+		h.rotate(rt, &rotation, exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("rejects rotation approved by fewer keys than the threshold", func(t *testing.T) {
+		rt := h.build(t)
+		rotation := account.KeyRotation{Keys: newKeys, Threshold: 1, Nonce: 0}
+		h.rotate(rt, &rotation, exitcode.ErrForbidden, 2)
+	})
+
+	t.Run("rejects invalid new keys", func(t *testing.T) {
+		rt := h.build(t)
+		rotation := account.KeyRotation{Keys: newKeys, Threshold: 3, Nonce: 0}
+		h.rotate(rt, &rotation, exitcode.ErrIllegalArgument)
+	})
+}
+
+func TestMultiKeySend(t *testing.T) {
+	h := newMultiKeyHarness(t)
+	to := tutil.NewIDAddr(t, 102)
+	params := runtime.CBORBytes([]byte{1, 2, 3})
+
+	t.Run("sends a message approved by a threshold", func(t *testing.T) {
+		rt := h.build(t)
+		rt.SetBalance(abi.NewTokenAmount(100))
+		send := account.KeySend{To: to, Value: abi.NewTokenAmount(10), Method: 42, Params: params, Nonce: 0}
+		rt.ExpectSend(to, 42, params, abi.NewTokenAmount(10), runtime.CBORBytes([]byte{5}), exitcode.Ok)
+		ret := h.send(rt, &send, exitcode.Ok, 0, 2)
+		assert.Equal(t, &account.SendReturn{Code: exitcode.Ok, Ret: []byte{5}}, ret)
+
+		var st account.MultiKeyState
+		rt.GetState(&st)
+		assert.Equal(t, uint64(1), st.SendNonce)
+
+		// the approval cannot be replayed
+		h.send(rt, &send, exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("consumes the approval when the message fails", func(t *testing.T) {
+		rt := h.build(t)
+		rt.SetBalance(abi.NewTokenAmount(100))
+		send := account.KeySend{To: to, Value: abi.NewTokenAmount(10), Method: 42, Params: params, Nonce: 0}
+		rt.ExpectSend(to, 42, params, abi.NewTokenAmount(10), nil, exitcode.ErrForbidden)
+		ret := h.send(rt, &send, exitcode.Ok, 1, 2)
+		assert.Equal(t, exitcode.ErrForbidden, ret.Code)
+
+		var st account.MultiKeyState
+		rt.GetState(&st)
+		assert.Equal(t, uint64(1), st.SendNonce)
+	})
+
+	t.Run("rejects a send approved by fewer keys than the threshold", func(t *testing.T) {
+		rt := h.build(t)
+		send := account.KeySend{To: to, Value: abi.NewTokenAmount(10), Method: builtin.MethodSend, Nonce: 0}
+		h.send(rt, &send, exitcode.ErrForbidden, 1)
+	})
+
+	t.Run("rejects a negative value", func(t *testing.T) {
+		rt := h.build(t)
+		send := account.KeySend{To: to, Value: abi.NewTokenAmount(-1), Method: builtin.MethodSend, Nonce: 0}
+		h.send(rt, &send, exitcode.ErrIllegalArgument)
+	})
+
+	t.Run("rejects an approval for another account", func(t *testing.T) {
+		rt := h.build(t)
+		send := account.KeySend{To: to, Value: abi.NewTokenAmount(10), Method: builtin.MethodSend, Nonce: 0}
+		plaintext, err := send.SigningBytes(tutil.NewIDAddr(t, 999))
+		require.NoError(t, err)
+		sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: append(h.keys[0].Bytes(), plaintext...)}
+
+		expected, err := send.SigningBytes(h.receiver)
+		require.NoError(t, err)
+		rt.ExpectVerifySignature(sig, h.keys[0], expected, exitcode.ErrIllegalArgument.Wrapf("bad signature"))
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(h.Send, &account.SendParams{Send: send, Signatures: []account.KeySignature{{KeyIndex: 0, Signature: sig}}})
+		})
+		rt.Verify()
+	})
+}
+
+type multiKeyHarness struct {
+	account.MultiKeyActor
+	t        *testing.T
+	receiver address.Address
+	keys     []address.Address
+}
+
+func newMultiKeyHarness(t *testing.T) *multiKeyHarness {
+	return &multiKeyHarness{
+		t:        t,
+		receiver: tutil.NewIDAddr(t, 100),
+		keys:     []address.Address{tutil.NewBLSAddr(t, 1), tutil.NewBLSAddr(t, 2), tutil.NewSECP256K1Addr(t, "key3")},
+	}
+}
+
+// Builds a runtime with a 2-of-3 account.
+func (h *multiKeyHarness) build(t *testing.T) *mock.Runtime {
+	rt := mock.NewBuilder(context.Background(), h.receiver).WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).Build(t)
+	rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+	rt.Call(h.Constructor, &account.MultiKeyConstructorParams{Keys: h.keys, Threshold: 2})
+	rt.Verify()
+	rt.SetCaller(tutil.NewIDAddr(t, 101), builtin.AccountActorCodeID)
+	return rt
+}
+
+// Returns signatures of plaintext by the keys at some indices, expecting their verification.
+func (h *multiKeyHarness) sign(rt *mock.Runtime, plaintext []byte, indices ...uint64) []account.KeySignature {
+	sigs := make([]account.KeySignature, len(indices))
+	for i, idx := range indices {
+		sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: append(h.keys[idx].Bytes(), plaintext...)}
+		sigs[i] = account.KeySignature{KeyIndex: idx, Signature: sig}
+		rt.ExpectVerifySignature(sig, h.keys[idx], plaintext, nil)
+	}
+	return sigs
+}
+
+func (h *multiKeyHarness) verify(rt *mock.Runtime, plaintext []byte, sigs []account.KeySignature, code exitcode.ExitCode) {
+	rt.ExpectValidateCallerAny()
+	params := &account.VerifyThresholdSignatureParams{Plaintext: plaintext, Signatures: sigs}
+	if code.IsSuccess() {
+		rt.Call(h.VerifyThresholdSignature, params)
+	} else {
+		rt.ExpectAbort(code, func() {
+			rt.Call(h.VerifyThresholdSignature, params)
+		})
+	}
+	rt.Verify()
+}
+
+// Submits a send signed by the keys at some indices.
+func (h *multiKeyHarness) send(rt *mock.Runtime, send *account.KeySend, code exitcode.ExitCode, signers ...uint64) *account.SendReturn {
+	plaintext, err := send.SigningBytes(h.receiver)
+	require.NoError(h.t, err)
+
+	sigs := make([]account.KeySignature, len(signers))
+	for i, idx := range signers {
+		sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: append(h.keys[idx].Bytes(), plaintext...)}
+		sigs[i] = account.KeySignature{KeyIndex: idx, Signature: sig}
+		rt.ExpectVerifySignature(sig, h.keys[idx], plaintext, nil)
+	}
+
+	rt.ExpectValidateCallerAny()
+	params := &account.SendParams{Send: *send, Signatures: sigs}
+	var ret *account.SendReturn
+	if code.IsSuccess() {
+		ret = rt.Call(h.Send, params).(*account.SendReturn)
+	} else {
+		rt.ExpectAbort(code, func() {
+			rt.Call(h.Send, params)
+		})
+	}
+	rt.Verify()
+	return ret
+}
+
+// Submits a rotation signed by the current keys at some indices.
+func (h *multiKeyHarness) rotate(rt *mock.Runtime, rotation *account.KeyRotation, code exitcode.ExitCode, signers ...uint64) {
+	plaintext, err := rotation.SigningBytes(h.receiver)
+	require.NoError(h.t, err)
+	var st account.MultiKeyState
+	rt.GetState(&st)
+
+	sigs := make([]account.KeySignature, len(signers))
+	for i, idx := range signers {
+		sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: append(st.Keys[idx].Bytes(), plaintext...)}
+		sigs[i] = account.KeySignature{KeyIndex: idx, Signature: sig}
+		if code != exitcode.ErrIllegalArgument {
+			rt.ExpectVerifySignature(sig, st.Keys[idx], plaintext, nil)
+		}
+	}
+
+	rt.ExpectValidateCallerAny()
+	params := &account.RotateKeysParams{Rotation: *rotation, Signatures: sigs}
+	if code.IsSuccess() {
+		rt.Call(h.RotateKeys, params)
+	} else {
+		rt.ExpectAbort(code, func() {
+			rt.Call(h.RotateKeys, params)
+		})
+	}
+	rt.Verify()
+}
@@ -20,6 +20,7 @@ var (
 	MultisigActorCodeID         cid.Cid
 	RewardActorCodeID           cid.Cid
 	VerifiedRegistryActorCodeID cid.Cid
+	MultiKeyAccountActorCodeID  cid.Cid
 	CallerTypesSignable         []cid.Cid
 )
 
@@ -46,6 +47,7 @@ func init() {
 		&VerifiedRegistryActorCodeID: {name: "fil/1/verifiedregistry"},
 		&AccountActorCodeID:          {name: "fil/1/account", signer: true},
 		&MultisigActorCodeID:         {name: "fil/1/multisig", signer: true},
+		&MultiKeyAccountActorCodeID:  {name: "fil/1/multikeyaccount"},
 	} {
 		c, err := builder.Sum([]byte(info.name))
 		if err != nil {
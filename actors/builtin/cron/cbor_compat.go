@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"fmt"
+	"io"
+
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+)
+
+// Entry is encoded by hand rather than generated, because its tuple has gained trailing fields since it was first
+// recorded in state.
+// It decodes from its original, shorter tuple with the added fields taking their zero values, and an entry whose
+// added fields are all zero encodes as the original tuple, so that state holding only such entries is unchanged.
+
+// Entry: [Receiver, MethodNum, (Period, HasSucceeded, SucceedingSince, ConsecutiveFailures)?]
+func (t *Entry) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	extended := t.Period != 0 || t.HasSucceeded || t.SucceedingSince != 0 || t.ConsecutiveFailures != 0
+	fields := uint64(2)
+	if extended {
+		fields = 6
+	}
+	if err := cbg.CborWriteHeader(w, cbg.MajArray, fields); err != nil {
+		return err
+	}
+	if err := t.Receiver.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(t.MethodNum)); err != nil {
+		return err
+	}
+	if !extended {
+		return nil
+	}
+	if err := writeInt64(w, int64(t.Period)); err != nil {
+		return err
+	}
+	if err := cbg.WriteBool(w, t.HasSucceeded); err != nil {
+		return err
+	}
+	if err := writeInt64(w, int64(t.SucceedingSince)); err != nil {
+		return err
+	}
+	return cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, t.ConsecutiveFailures)
+}
+
+func (t *Entry) UnmarshalCBOR(r io.Reader) error {
+	*t = Entry{}
+
+	br := cbg.GetPeeker(r)
+	maj, fields, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+	if fields != 2 && fields != 6 {
+		return fmt.Errorf("cbor input had wrong number of fields: %d", fields)
+	}
+
+	if err := t.Receiver.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.Receiver: %w", err)
+	}
+	method, err := readUint64(br)
+	if err != nil {
+		return xerrors.Errorf("t.MethodNum: %w", err)
+	}
+	t.MethodNum = abi.MethodNum(method)
+	if fields == 2 {
+		return nil
+	}
+
+	period, err := readInt64(br)
+	if err != nil {
+		return xerrors.Errorf("t.Period: %w", err)
+	}
+	t.Period = abi.ChainEpoch(period)
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.HasSucceeded = false
+	case 21:
+		t.HasSucceeded = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	since, err := readInt64(br)
+	if err != nil {
+		return xerrors.Errorf("t.SucceedingSince: %w", err)
+	}
+	t.SucceedingSince = abi.ChainEpoch(since)
+	if t.ConsecutiveFailures, err = readUint64(br); err != nil {
+		return xerrors.Errorf("t.ConsecutiveFailures: %w", err)
+	}
+	return nil
+}
+
+func writeInt64(w io.Writer, v int64) error {
+	if v >= 0 {
+		return cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(v))
+	}
+	return cbg.WriteMajorTypeHeader(w, cbg.MajNegativeInt, uint64(-v-1))
+}
+
+func readInt64(br io.Reader) (int64, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	v := int64(extra)
+	if v < 0 {
+		return 0, fmt.Errorf("int64 overflow")
+	}
+	switch maj {
+	case cbg.MajUnsignedInt:
+		return v, nil
+	case cbg.MajNegativeInt:
+		return -1 - v, nil
+	default:
+		return 0, fmt.Errorf("wrong type for int64 field: %d", maj)
+	}
+}
+
+func readUint64(br io.Reader) (uint64, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajUnsignedInt {
+		return 0, fmt.Errorf("wrong type for uint64 field")
+	}
+	return extra, nil
+}
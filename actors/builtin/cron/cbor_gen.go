@@ -92,14 +92,93 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
-var lengthBufEntry = []byte{130}
+var lengthBufConstructorParams = []byte{129}
 
-func (t *Entry) MarshalCBOR(w io.Writer) error {
+func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufEntry); err != nil {
+	if _, err := w.Write(lengthBufConstructorParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Entries ([]cron.Entry) (slice)
+	if len(t.Entries) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Entries was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Entries))); err != nil {
+		return err
+	}
+	for _, v := range t.Entries {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ConstructorParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Entries ([]cron.Entry) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Entries: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Entries = make([]Entry, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v Entry
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Entries[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufAddEntryParams = []byte{131}
+
+func (t *AddEntryParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufAddEntryParams); err != nil {
 		return err
 	}
 
@@ -116,11 +195,21 @@ func (t *Entry) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.Period (abi.ChainEpoch) (int64)
+	if t.Period >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Period)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Period-1)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (t *Entry) UnmarshalCBOR(r io.Reader) error {
-	*t = Entry{}
+func (t *AddEntryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AddEntryParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -133,7 +222,7 @@ func (t *Entry) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -160,40 +249,63 @@ func (t *Entry) UnmarshalCBOR(r io.Reader) error {
 		t.MethodNum = abi.MethodNum(extra)
 
 	}
+	// t.Period (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Period = abi.ChainEpoch(extraI)
+	}
 	return nil
 }
 
-var lengthBufConstructorParams = []byte{129}
+var lengthBufRemoveEntryParams = []byte{130}
 
-func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
+func (t *RemoveEntryParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufConstructorParams); err != nil {
+	if _, err := w.Write(lengthBufRemoveEntryParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Entries ([]cron.Entry) (slice)
-	if len(t.Entries) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Entries was too long")
+	// t.Receiver (address.Address) (struct)
+	if err := t.Receiver.MarshalCBOR(w); err != nil {
+		return err
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Entries))); err != nil {
+	// t.MethodNum (abi.MethodNum) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MethodNum)); err != nil {
 		return err
 	}
-	for _, v := range t.Entries {
-		if err := v.MarshalCBOR(w); err != nil {
-			return err
-		}
-	}
+
 	return nil
 }
 
-func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
-	*t = ConstructorParams{}
+func (t *RemoveEntryParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveEntryParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -206,38 +318,32 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 1 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Entries ([]cron.Entry) (slice)
-
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
+	// t.Receiver (address.Address) (struct)
 
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Entries: array too large (%d)", extra)
-	}
+	{
 
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
-	}
+		if err := t.Receiver.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Receiver: %w", err)
+		}
 
-	if extra > 0 {
-		t.Entries = make([]Entry, extra)
 	}
+	// t.MethodNum (abi.MethodNum) (uint64)
 
-	for i := 0; i < int(extra); i++ {
+	{
 
-		var v Entry
-		if err := v.UnmarshalCBOR(br); err != nil {
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
 			return err
 		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.MethodNum = abi.MethodNum(extra)
 
-		t.Entries[i] = v
 	}
-
 	return nil
 }
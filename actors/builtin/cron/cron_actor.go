@@ -1,9 +1,12 @@
 package cron
 
 import (
+	addr "github.com/filecoin-project/go-address"
+
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
 	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
+	exitcode "github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
 )
 
@@ -14,6 +17,8 @@ func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		builtin.MethodConstructor: a.Constructor,
 		2:                         a.EpochTick,
+		3:                         a.AddEntry,
+		4:                         a.RemoveEntry,
 	}
 }
 
@@ -30,15 +35,97 @@ func (a Actor) Constructor(rt vmr.Runtime, params *ConstructorParams) *adt.Empty
 }
 
 // Invoked by the system after all other messages in the epoch have been processed.
+// A failing entry does not prevent other entries being called. Failures are logged and counted in the entry.
+// State is written only if the outcome of a call changes its entry.
 func (a Actor) EpochTick(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
 
 	var st State
 	rt.State().Readonly(&st)
-	for _, entry := range st.Entries {
-		_, _ = rt.Send(entry.Receiver, entry.MethodNum, nil, abi.NewTokenAmount(0))
-		// Any error and return value are ignored.
+	epoch := rt.CurrEpoch()
+	codes := make([]exitcode.ExitCode, len(st.Entries))
+	changed := false
+	for i, entry := range st.Entries {
+		if !entry.IsDue(epoch) {
+			continue
+		}
+		_, codes[i] = rt.Send(entry.Receiver, entry.MethodNum, nil, abi.NewTokenAmount(0))
+		// Any return value is ignored.
+		changed = entry.recordCall(epoch, codes[i]) || changed
+	}
+	if !changed {
+		return nil
 	}
 
+	rt.State().Transaction(&st, func() {
+		for i := range st.Entries {
+			entry := &st.Entries[i]
+			if !entry.IsDue(epoch) {
+				continue
+			}
+			entry.recordCall(epoch, codes[i])
+			if codes[i].IsSuccess() {
+				continue
+			}
+			if entry.HasSucceeded {
+				rt.Log(vmr.WARN, "cron entry %v method %d failed at epoch %d: exitcode %d, %d consecutive failures after succeeding from epoch %d",
+					entry.Receiver, entry.MethodNum, epoch, codes[i], entry.ConsecutiveFailures, entry.SucceedingSince)
+			} else {
+				rt.Log(vmr.WARN, "cron entry %v method %d failed at epoch %d: exitcode %d, %d consecutive failures and never succeeded",
+					entry.Receiver, entry.MethodNum, epoch, codes[i], entry.ConsecutiveFailures)
+			}
+		}
+	})
+	return nil
+}
+
+type AddEntryParams struct {
+	Receiver  addr.Address
+	MethodNum abi.MethodNum
+	Period    abi.ChainEpoch
+}
+
+// Adds an entry to be called at the end of each epoch that is a multiple of its period.
+func (a Actor) AddEntry(rt vmr.Runtime, params *AddEntryParams) *adt.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
+
+	if params.Receiver.Protocol() != addr.ID {
+		rt.Abortf(exitcode.ErrIllegalArgument, "receiver %v must be an ID address", params.Receiver)
+	}
+	if params.Period < 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "period %d must not be negative", params.Period)
+	}
+
+	var st State
+	rt.State().Transaction(&st, func() {
+		if st.findEntry(params.Receiver, params.MethodNum) >= 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "entry for %v method %d already exists", params.Receiver, params.MethodNum)
+		}
+		st.Entries = append(st.Entries, Entry{
+			Receiver:  params.Receiver,
+			MethodNum: params.MethodNum,
+			Period:    params.Period,
+		})
+	})
+	return nil
+}
+
+type RemoveEntryParams struct {
+	Receiver  addr.Address
+	MethodNum abi.MethodNum
+}
+
+// Removes the entry calling a method of a receiver.
+func (a Actor) RemoveEntry(rt vmr.Runtime, params *RemoveEntryParams) *adt.EmptyValue {
+	rt.ValidateImmediateCallerIs(builtin.SystemActorAddr)
+
+	var st State
+	rt.State().Transaction(&st, func() {
+		i := st.findEntry(params.Receiver, params.MethodNum)
+		if i < 0 {
+			rt.Abortf(exitcode.ErrNotFound, "no entry for %v method %d", params.Receiver, params.MethodNum)
+		}
+		st.Entries = append(st.Entries[:i], st.Entries[i+1:]...)
+	})
 	return nil
 }
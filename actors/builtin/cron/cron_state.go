@@ -5,6 +5,7 @@ import (
 
 	"github.com/filecoin-project/specs-actors/actors/abi"
 	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 )
 
 type State struct {
@@ -14,12 +15,52 @@ type State struct {
 type Entry struct {
 	Receiver  addr.Address  // The actor to call (must be an ID-address)
 	MethodNum abi.MethodNum // The method number to call (must accept empty parameters)
+	// The entry is called at epochs that are a multiple of its period. Zero calls it every epoch.
+	Period abi.ChainEpoch
+	// Whether a call has ever succeeded.
+	HasSucceeded bool
+	// The epoch at which the latest run of successful calls began, if HasSucceeded.
+	// Calls continuing a run of successes are not recorded, so that cron state is unchanged while its entries
+	// succeed: while ConsecutiveFailures is zero, the call has succeeded at every epoch it was due since.
+	SucceedingSince abi.ChainEpoch
+	// The number of calls that have failed since the last success.
+	ConsecutiveFailures uint64
 }
 
 func ConstructState(entries []Entry) *State {
 	return &State{Entries: entries}
 }
 
+// Whether the entry is to be called at an epoch.
+func (e *Entry) IsDue(epoch abi.ChainEpoch) bool {
+	return e.Period <= 1 || epoch%e.Period == 0
+}
+
+// Records the outcome of a call to the entry at an epoch, returning whether the entry changed.
+func (e *Entry) recordCall(epoch abi.ChainEpoch, code exitcode.ExitCode) bool {
+	if !code.IsSuccess() {
+		e.ConsecutiveFailures++
+		return true
+	}
+	if e.HasSucceeded && e.ConsecutiveFailures == 0 {
+		return false
+	}
+	e.HasSucceeded = true
+	e.SucceedingSince = epoch
+	e.ConsecutiveFailures = 0
+	return true
+}
+
+// Returns the index of the entry calling a method of a receiver, or -1 if there is none.
+func (st *State) findEntry(receiver addr.Address, method abi.MethodNum) int {
+	for i, entry := range st.Entries {
+		if entry.Receiver == receiver && entry.MethodNum == method {
+			return i
+		}
+	}
+	return -1
+}
+
 // The default entries to install in the cron actor's state at genesis.
 func BuiltInEntries() []Entry {
 	return []Entry{
@@ -1,10 +1,13 @@
 package cron_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/hex"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
@@ -82,6 +85,92 @@ func TestEpochTick(t *testing.T) {
 		rt.ExpectSend(entry3.Receiver, entry3.MethodNum, nil, big.Zero(), nil, exitcode.ErrInsufficientFunds)
 		rt.ExpectSend(entry4.Receiver, entry4.MethodNum, nil, big.Zero(), nil, exitcode.ErrForbidden)
 		actor.epochTickAndVerify(rt)
+		rt.ExpectLogsContain("cron entry " + entry2.Receiver.String())
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, uint64(0), st.Entries[0].ConsecutiveFailures)
+		for _, entry := range st.Entries[1:] {
+			assert.Equal(t, uint64(1), entry.ConsecutiveFailures)
+		}
+	})
+
+	t.Run("records successes and consecutive failures", func(t *testing.T) {
+		rt := builder.Build(t)
+
+		entry := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}
+		actor.constructAndVerify(rt, entry)
+
+		rt.SetEpoch(4)
+		rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.ErrIllegalState)
+		actor.epochTickAndVerify(rt)
+		rt.ExpectLogsContain("1 consecutive failures and never succeeded")
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.False(t, st.Entries[0].HasSucceeded)
+		assert.Equal(t, uint64(1), st.Entries[0].ConsecutiveFailures)
+
+		rt.SetEpoch(5)
+		rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+		actor.epochTickAndVerify(rt)
+		for epoch := abi.ChainEpoch(6); epoch < 8; epoch++ {
+			rt.SetEpoch(epoch)
+			rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.ErrIllegalState)
+			actor.epochTickAndVerify(rt)
+		}
+		rt.ExpectLogsContain("2 consecutive failures after succeeding from epoch 5")
+
+		rt.GetState(&st)
+		assert.True(t, st.Entries[0].HasSucceeded)
+		assert.Equal(t, abi.ChainEpoch(5), st.Entries[0].SucceedingSince)
+		assert.Equal(t, uint64(2), st.Entries[0].ConsecutiveFailures)
+
+		rt.SetEpoch(8)
+		rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+		actor.epochTickAndVerify(rt)
+		rt.GetState(&st)
+		assert.True(t, st.Entries[0].HasSucceeded)
+		assert.Equal(t, abi.ChainEpoch(8), st.Entries[0].SucceedingSince)
+		assert.Equal(t, uint64(0), st.Entries[0].ConsecutiveFailures)
+	})
+
+	t.Run("repeated successes leave state unchanged", func(t *testing.T) {
+		rt := builder.Build(t)
+
+		entry := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}
+		actor.constructAndVerify(rt, entry)
+
+		rt.SetEpoch(1)
+		rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+		actor.epochTickAndVerify(rt)
+		root := rt.StateRoot()
+
+		for epoch := abi.ChainEpoch(2); epoch < 5; epoch++ {
+			rt.SetEpoch(epoch)
+			rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+			actor.epochTickAndVerify(rt)
+			assert.Equal(t, root, rt.StateRoot())
+		}
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, abi.ChainEpoch(1), st.Entries[0].SucceedingSince)
+	})
+
+	t.Run("calls periodic entries at multiples of their period", func(t *testing.T) {
+		rt := builder.Build(t)
+
+		entry := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001), Period: 3}
+		actor.constructAndVerify(rt, entry)
+
+		for epoch := abi.ChainEpoch(1); epoch <= 6; epoch++ {
+			rt.SetEpoch(epoch)
+			if epoch%3 == 0 {
+				rt.ExpectSend(entry.Receiver, entry.MethodNum, nil, big.Zero(), nil, exitcode.Ok)
+			}
+			actor.epochTickAndVerify(rt)
+		}
 	})
 
 	t.Run("built-in entries", func(t *testing.T) {
@@ -91,6 +180,71 @@ func TestEpochTick(t *testing.T) {
 
 }
 
+func TestAddRemoveEntry(t *testing.T) {
+	actor := cronHarness{cron.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+	existing := cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}
+	added := &cron.AddEntryParams{Receiver: tutil.NewIDAddr(t, 1002), MethodNum: abi.MethodNum(1002), Period: 10}
+
+	t.Run("adds and removes entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, existing)
+
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.Call(actor.AddEntry, added)
+		rt.Verify()
+
+		var st cron.State
+		rt.GetState(&st)
+		assert.Equal(t, []cron.Entry{existing, {Receiver: added.Receiver, MethodNum: added.MethodNum, Period: added.Period}}, st.Entries)
+
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.Call(actor.RemoveEntry, &cron.RemoveEntryParams{Receiver: existing.Receiver, MethodNum: existing.MethodNum})
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, []cron.Entry{{Receiver: added.Receiver, MethodNum: added.MethodNum, Period: added.Period}}, st.Entries)
+	})
+
+	t.Run("rejects invalid entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, existing)
+
+		for _, params := range []*cron.AddEntryParams{
+			{Receiver: existing.Receiver, MethodNum: existing.MethodNum},
+			{Receiver: tutil.NewActorAddr(t, "robust"), MethodNum: 1},
+			{Receiver: tutil.NewIDAddr(t, 1003), MethodNum: 1, Period: -1},
+		} {
+			rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.AddEntry, params)
+			})
+		}
+
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.RemoveEntry, &cron.RemoveEntryParams{Receiver: added.Receiver, MethodNum: added.MethodNum})
+		})
+	})
+
+	t.Run("only the system actor edits entries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, existing)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 1000), builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.AddEntry, added)
+		})
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.RemoveEntry, &cron.RemoveEntryParams{Receiver: existing.Receiver, MethodNum: existing.MethodNum})
+		})
+	})
+}
+
 type cronHarness struct {
 	cron.Actor
 	t testing.TB
@@ -110,3 +264,36 @@ func (h *cronHarness) epochTickAndVerify(rt *mock.Runtime) {
 	assert.Nil(h.t, ret)
 	rt.Verify()
 }
+
+func TestEntryEncoding(t *testing.T) {
+	// An entry as encoded before it gained a period and call outcomes: [Receiver, MethodNum].
+	legacy, err := hex.DecodeString("824300e9071903e9")
+	require.NoError(t, err)
+
+	t.Run("decodes legacy entries", func(t *testing.T) {
+		var entry cron.Entry
+		require.NoError(t, entry.UnmarshalCBOR(bytes.NewReader(legacy)))
+		assert.Equal(t, cron.Entry{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: abi.MethodNum(1001)}, entry)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, entry.MarshalCBOR(buf))
+		assert.Equal(t, legacy, buf.Bytes())
+	})
+
+	t.Run("round trips extended entries", func(t *testing.T) {
+		for _, entry := range []cron.Entry{
+			{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: 1001, Period: 3},
+			{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: 1001, ConsecutiveFailures: 2},
+			{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: 1001, HasSucceeded: true, SucceedingSince: 0},
+			{Receiver: tutil.NewIDAddr(t, 1001), MethodNum: 1001, Period: 1, HasSucceeded: true, SucceedingSince: 7, ConsecutiveFailures: 1},
+		} {
+			buf := new(bytes.Buffer)
+			require.NoError(t, entry.MarshalCBOR(buf))
+			assert.NotEqual(t, legacy, buf.Bytes())
+
+			var decoded cron.Entry
+			require.NoError(t, decoded.UnmarshalCBOR(buf))
+			assert.Equal(t, entry, decoded)
+		}
+	})
+}
@@ -41,6 +41,10 @@ func BuiltinActors() []BuiltinActor {
 			actor: account.Actor{},
 			code:  builtin.AccountActorCodeID,
 		},
+		{
+			actor: account.MultiKeyActor{},
+			code:  builtin.MultiKeyAccountActorCodeID,
+		},
 		{
 			actor: cron.Actor{},
 			code:  builtin.CronActorCodeID,
@@ -7,13 +7,14 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	cid "github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{131}
+var lengthBufState = []byte{134}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -49,6 +50,23 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
 		return err
 	}
+
+	// t.RobustAddresses (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.RobustAddresses); err != nil {
+		return xerrors.Errorf("failed to write cid field t.RobustAddresses: %w", err)
+	}
+
+	// t.ExecPermissions (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.ExecPermissions); err != nil {
+		return xerrors.Errorf("failed to write cid field t.ExecPermissions: %w", err)
+	}
+
+	// t.Governor (address.Address) (struct)
+	if err := t.Governor.MarshalCBOR(w); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -66,6 +84,135 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
+	if extra != 6 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.AddressMap (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.AddressMap: %w", err)
+		}
+
+		t.AddressMap = c
+
+	}
+	// t.NextID (abi.ActorID) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.NextID = abi.ActorID(extra)
+
+	}
+	// t.NetworkName (string) (string)
+
+	{
+		sval, err := cbg.ReadStringBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+
+		t.NetworkName = string(sval)
+	}
+	// t.RobustAddresses (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.RobustAddresses: %w", err)
+		}
+
+		t.RobustAddresses = c
+
+	}
+	// t.ExecPermissions (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.ExecPermissions: %w", err)
+		}
+
+		t.ExecPermissions = c
+
+	}
+	// t.Governor (address.Address) (struct)
+
+	{
+
+		if err := t.Governor.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Governor: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufLegacyState = []byte{131}
+
+func (t *LegacyState) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLegacyState); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.AddressMap (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.AddressMap); err != nil {
+		return xerrors.Errorf("failed to write cid field t.AddressMap: %w", err)
+	}
+
+	// t.NextID (abi.ActorID) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NextID)); err != nil {
+		return err
+	}
+
+	// t.NetworkName (string) (string)
+	if len(t.NetworkName) > cbg.MaxLength {
+		return xerrors.Errorf("Value in field t.NetworkName was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajTextString, uint64(len(t.NetworkName))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, string(t.NetworkName)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *LegacyState) UnmarshalCBOR(r io.Reader) error {
+	*t = LegacyState{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
 	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
@@ -109,6 +256,106 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufExecPermission = []byte{130}
+
+func (t *ExecPermission) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExecPermission); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.AnyCaller (bool) (bool)
+	if err := cbg.WriteBool(w, t.AnyCaller); err != nil {
+		return err
+	}
+
+	// t.Callers ([]cid.Cid) (slice)
+	if len(t.Callers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Callers was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Callers))); err != nil {
+		return err
+	}
+	for _, v := range t.Callers {
+		if err := cbg.WriteCidBuf(scratch, w, v); err != nil {
+			return xerrors.Errorf("failed writing cid field t.Callers: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *ExecPermission) UnmarshalCBOR(r io.Reader) error {
+	*t = ExecPermission{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.AnyCaller (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.AnyCaller = false
+	case 21:
+		t.AnyCaller = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	// t.Callers ([]cid.Cid) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Callers: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Callers = make([]cid.Cid, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("reading cid field t.Callers failed: %w", err)
+		}
+		t.Callers[i] = c
+	}
+
+	return nil
+}
+
 var lengthBufConstructorParams = []byte{129}
 
 func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
@@ -255,6 +502,298 @@ func (t *ExecParams) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufExec2Params = []byte{131}
+
+func (t *Exec2Params) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufExec2Params); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CodeCID: %w", err)
+	}
+
+	// t.ConstructorParams ([]uint8) (slice)
+	if len(t.ConstructorParams) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.ConstructorParams was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.ConstructorParams))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.ConstructorParams[:]); err != nil {
+		return err
+	}
+
+	// t.Salt ([]uint8) (slice)
+	if len(t.Salt) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Salt was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Salt))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Salt[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *Exec2Params) UnmarshalCBOR(r io.Reader) error {
+	*t = Exec2Params{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.CodeCID: %w", err)
+		}
+
+		t.CodeCID = c
+
+	}
+	// t.ConstructorParams ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.ConstructorParams: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.ConstructorParams = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.ConstructorParams[:]); err != nil {
+		return err
+	}
+	// t.Salt ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.Salt: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.Salt = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Salt[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+var lengthBufSetExecCallerParams = []byte{131}
+
+func (t *SetExecCallerParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSetExecCallerParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CodeCID: %w", err)
+	}
+
+	// t.CallerCodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CallerCodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CallerCodeCID: %w", err)
+	}
+
+	// t.Allowed (bool) (bool)
+	if err := cbg.WriteBool(w, t.Allowed); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SetExecCallerParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SetExecCallerParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.CodeCID: %w", err)
+		}
+
+		t.CodeCID = c
+
+	}
+	// t.CallerCodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.CallerCodeCID: %w", err)
+		}
+
+		t.CallerCodeCID = c
+
+	}
+	// t.Allowed (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Allowed = false
+	case 21:
+		t.Allowed = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
+var lengthBufSetExecAnyCallerParams = []byte{130}
+
+func (t *SetExecAnyCallerParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufSetExecAnyCallerParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.CodeCID); err != nil {
+		return xerrors.Errorf("failed to write cid field t.CodeCID: %w", err)
+	}
+
+	// t.AnyCaller (bool) (bool)
+	if err := cbg.WriteBool(w, t.AnyCaller); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *SetExecAnyCallerParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SetExecAnyCallerParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CodeCID (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.CodeCID: %w", err)
+		}
+
+		t.CodeCID = c
+
+	}
+	// t.AnyCaller (bool) (bool)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.AnyCaller = false
+	case 21:
+		t.AnyCaller = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
+	}
+	return nil
+}
+
 var lengthBufExecReturn = []byte{130}
 
 func (t *ExecReturn) MarshalCBOR(w io.Writer) error {
@@ -3,6 +3,7 @@ package init
 import (
 	addr "github.com/filecoin-project/go-address"
 	cid "github.com/ipfs/go-cid"
+	xerrors "golang.org/x/xerrors"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
@@ -20,6 +21,11 @@ func (a Actor) Exports() []interface{} {
 	return []interface{}{
 		builtin.MethodConstructor: a.Constructor,
 		2:                         a.Exec,
+		3:                         a.Exec2,
+		4:                         a.ResolveIDToRobust,
+		5:                         a.SetExecCaller,
+		6:                         a.SetExecAnyCaller,
+		7:                         a.SetGovernor,
 	}
 }
 
@@ -34,7 +40,10 @@ func (a Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *adt.E
 	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to construct state")
 
-	st := ConstructState(emptyMap, params.NetworkName)
+	execPermissions, err := ConstructExecPermissions(adt.AsStore(rt), DefaultExecPermissions())
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to construct state")
+
+	st := ConstructState(emptyMap, emptyMap, execPermissions, params.NetworkName)
 	rt.State().Create(st)
 	return nil
 }
@@ -51,11 +60,7 @@ type ExecReturn struct {
 
 func (a Actor) Exec(rt runtime.Runtime, params *ExecParams) *ExecReturn {
 	rt.ValidateImmediateCallerAcceptAny()
-	callerCodeCID, ok := rt.GetActorCodeCID(rt.Message().Caller())
-	autil.AssertMsg(ok, "no code for actor at %s", rt.Message().Caller())
-	if !canExec(callerCodeCID, params.CodeCID) {
-		rt.Abortf(exitcode.ErrForbidden, "caller type %v cannot exec actor type %v", callerCodeCID, params.CodeCID)
-	}
+	validateCanExec(rt, params.CodeCID)
 
 	// Compute a re-org-stable address.
 	// This address exists for use by messages coming from outside the system, in order to
@@ -63,36 +68,168 @@ func (a Actor) Exec(rt runtime.Runtime, params *ExecParams) *ExecReturn {
 	// a different ID.
 	uniqueAddress := rt.NewActorAddress()
 
+	return execActor(rt, params.CodeCID, params.ConstructorParams, uniqueAddress)
+}
+
+type Exec2Params struct {
+	CodeCID           cid.Cid `checked:"true"` // invalid CIDs won't get committed to the state tree
+	ConstructorParams []byte
+	Salt              []byte
+}
+
+// Maximum length of an Exec2 salt.
+const MaxExec2SaltLength = 32
+
+// Exec2 creates an actor as for Exec, but with a robust address derived from the caller, a salt, the code CID
+// and the constructor parameters, rather than from the message that creates it.
+// The address can thus be computed with Exec2Address before the actor is created, for example to fund it.
+// Each combination of these inputs can create only one actor.
+func (a Actor) Exec2(rt runtime.Runtime, params *Exec2Params) *ExecReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	validateCanExec(rt, params.CodeCID)
+
+	if len(params.Salt) > MaxExec2SaltLength {
+		rt.Abortf(exitcode.ErrIllegalArgument, "salt length %d exceeds maximum %d", len(params.Salt), MaxExec2SaltLength)
+	}
+
+	paramsHash := rt.Syscalls().HashBlake2b(params.ConstructorParams)
+	robustAddress, err := Exec2Address(rt.Message().Caller(), params.Salt, params.CodeCID, paramsHash)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to compute actor address")
+
+	// The address cannot be re-mapped to a new actor.
+	var st State
+	rt.State().Readonly(&st)
+	_, found, err := st.ResolveAddress(adt.AsStore(rt), robustAddress)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve address %v", robustAddress)
+	if found {
+		rt.Abortf(exitcode.ErrForbidden, "actor address %v already in use", robustAddress)
+	}
+
+	return execActor(rt, params.CodeCID, params.ConstructorParams, robustAddress)
+}
+
+// Domain separation prefix for the Exec2 address preimage.
+const exec2AddressPrefix = "fil_exec2:"
+
+// Exec2Address computes the robust address of an actor created by Exec2 from the ID address of the creator,
+// the salt, the code CID, and the Blake2b-256 hash of the constructor parameters.
+func Exec2Address(creator addr.Address, salt []byte, code cid.Cid, constructorParamsHash [32]byte) (addr.Address, error) {
+	if creator.Protocol() != addr.ID {
+		return addr.Undef, xerrors.Errorf("creator %v must be an ID address", creator)
+	}
+	// Address and CID encodings are self-delimiting and the hash is of fixed length, so the salt may come last
+	// without ambiguity.
+	var preimage []byte
+	preimage = append(preimage, exec2AddressPrefix...)
+	preimage = append(preimage, creator.Bytes()...)
+	preimage = append(preimage, code.Bytes()...)
+	preimage = append(preimage, constructorParamsHash[:]...)
+	preimage = append(preimage, salt...)
+	return addr.NewActorAddress(preimage)
+}
+
+// ResolveIDToRobust returns the robust address from which an ID address was allocated.
+// Aborts with ErrNotFound if there is none, as for singleton actors.
+func (a Actor) ResolveIDToRobust(rt runtime.Runtime, idAddr *addr.Address) *addr.Address {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	id, err := addr.IDFromAddress(*idAddr)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "address %v is not an ID address", *idAddr)
+
+	var st State
+	rt.State().Readonly(&st)
+	robust, found, err := st.ResolveIDToRobust(adt.AsStore(rt), abi.ActorID(id))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve %v", *idAddr)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no robust address for %v", *idAddr)
+	}
+	return &robust
+}
+
+func validateCanExec(rt runtime.Runtime, execCodeID cid.Cid) {
+	callerCodeCID, ok := rt.GetActorCodeCID(rt.Message().Caller())
+	autil.AssertMsg(ok, "no code for actor at %s", rt.Message().Caller())
+
+	var st State
+	rt.State().Readonly(&st)
+	can, err := st.CanExec(adt.AsStore(rt), callerCodeCID, execCodeID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check exec permission")
+	if !can {
+		rt.Abortf(exitcode.ErrForbidden, "caller type %v cannot exec actor type %v", callerCodeCID, execCodeID)
+	}
+}
+
+// Creates an actor with a robust address and invokes its constructor.
+func execActor(rt runtime.Runtime, codeCID cid.Cid, constructorParams []byte, robustAddress addr.Address) *ExecReturn {
 	// Allocate an ID for this actor.
 	// Store mapping of pubkey or actor address to actor ID
 	var st State
 	var idAddr addr.Address
 	rt.State().Transaction(&st, func() {
 		var err error
-		idAddr, err = st.MapAddressToNewID(adt.AsStore(rt), uniqueAddress)
+		idAddr, err = st.MapAddressToNewID(adt.AsStore(rt), robustAddress)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to allocate ID address")
 	})
 
 	// Create an empty actor.
-	rt.CreateActor(params.CodeCID, idAddr)
+	rt.CreateActor(codeCID, idAddr)
 
 	// Invoke constructor.
-	_, code := rt.Send(idAddr, builtin.MethodConstructor, runtime.CBORBytes(params.ConstructorParams), rt.Message().ValueReceived())
+	_, code := rt.Send(idAddr, builtin.MethodConstructor, runtime.CBORBytes(constructorParams), rt.Message().ValueReceived())
 	builtin.RequireSuccess(rt, code, "constructor failed")
 
-	return &ExecReturn{idAddr, uniqueAddress}
+	return &ExecReturn{idAddr, robustAddress}
+}
+
+type SetExecCallerParams struct {
+	CodeCID       cid.Cid `checked:"true"`
+	CallerCodeCID cid.Cid `checked:"true"`
+	Allowed       bool
+}
+
+// SetExecCaller sets whether actors with some code may create actors with another code.
+// Only the system actor or the governor may set exec permissions.
+func (a Actor) SetExecCaller(rt runtime.Runtime, params *SetExecCallerParams) *adt.EmptyValue {
+	var st State
+	rt.State().Transaction(&st, func() {
+		rt.ValidateImmediateCallerIs(builtin.SystemActorAddr, st.Governor)
+
+		err := st.SetExecCaller(adt.AsStore(rt), params.CodeCID, params.CallerCodeCID, params.Allowed)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set exec permission")
+	})
+	return nil
+}
+
+type SetExecAnyCallerParams struct {
+	CodeCID   cid.Cid `checked:"true"`
+	AnyCaller bool
+}
+
+// SetExecAnyCaller sets whether any actor may create actors with some code.
+// Only the system actor or the governor may set exec permissions.
+func (a Actor) SetExecAnyCaller(rt runtime.Runtime, params *SetExecAnyCallerParams) *adt.EmptyValue {
+	var st State
+	rt.State().Transaction(&st, func() {
+		rt.ValidateImmediateCallerIs(builtin.SystemActorAddr, st.Governor)
+
+		err := st.SetExecAnyCaller(adt.AsStore(rt), params.CodeCID, params.AnyCaller)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set exec permission")
+	})
+	return nil
 }
 
-func canExec(callerCodeID cid.Cid, execCodeID cid.Cid) bool {
-	switch execCodeID {
-	case builtin.StorageMinerActorCodeID:
-		if callerCodeID == builtin.StoragePowerActorCodeID {
-			return true
+// SetGovernor changes the address that may set exec permissions in addition to the system actor.
+// Only the system actor or the current governor may change it.
+func (a Actor) SetGovernor(rt runtime.Runtime, governor *addr.Address) *adt.EmptyValue {
+	var st State
+	rt.State().Transaction(&st, func() {
+		rt.ValidateImmediateCallerIs(builtin.SystemActorAddr, st.Governor)
+
+		resolved, ok := rt.ResolveAddress(*governor)
+		if !ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve governor address %v", *governor)
 		}
-		return false
-	case builtin.PaymentChannelActorCodeID, builtin.MultisigActorCodeID:
-		return true
-	default:
-		return false
-	}
+		st.Governor = resolved
+	})
+	return nil
 }
@@ -18,16 +18,131 @@ type State struct {
 	AddressMap  cid.Cid // HAMT[addr.Address]abi.ActorID
 	NextID      abi.ActorID
 	NetworkName string
+	// The reverse of AddressMap.
+	RobustAddresses cid.Cid // HAMT[abi.ActorID]addr.Address
+	// The code of actors that may be created by Exec, and by which callers.
+	ExecPermissions cid.Cid // HAMT[cid.Cid]ExecPermission
+	// Address that may edit ExecPermissions, in addition to the system actor.
+	Governor addr.Address
 }
 
-func ConstructState(addressMapRoot cid.Cid, networkName string) *State {
+// Specifies which callers may create actors with some code.
+type ExecPermission struct {
+	// Whether any caller may create the actor.
+	AnyCaller bool
+	// The code of callers that may create the actor, if not any caller.
+	Callers []cid.Cid
+}
+
+func ConstructState(addressMapRoot, robustAddressesRoot, execPermissionsRoot cid.Cid, networkName string) *State {
 	return &State{
-		AddressMap:  addressMapRoot,
-		NextID:      abi.ActorID(builtin.FirstNonSingletonActorId),
-		NetworkName: networkName,
+		AddressMap:      addressMapRoot,
+		NextID:          abi.ActorID(builtin.FirstNonSingletonActorId),
+		NetworkName:     networkName,
+		RobustAddresses: robustAddressesRoot,
+		ExecPermissions: execPermissionsRoot,
+		Governor:        builtin.SystemActorAddr,
 	}
 }
 
+// The default exec permissions: only the power actor may create miners, and anyone may create payment channels,
+// multisigs and multi-key accounts.
+func DefaultExecPermissions() map[cid.Cid]ExecPermission {
+	return map[cid.Cid]ExecPermission{
+		builtin.StorageMinerActorCodeID:    {Callers: []cid.Cid{builtin.StoragePowerActorCodeID}},
+		builtin.PaymentChannelActorCodeID:  {AnyCaller: true},
+		builtin.MultisigActorCodeID:        {AnyCaller: true},
+		builtin.MultiKeyAccountActorCodeID: {AnyCaller: true},
+	}
+}
+
+// ConstructExecPermissions stores a table of exec permissions and returns its root.
+func ConstructExecPermissions(store adt.Store, permissions map[cid.Cid]ExecPermission) (cid.Cid, error) {
+	m := adt.MakeEmptyMap(store)
+	for code, perm := range permissions { //nolint:nomaprange
+		perm := perm
+		if err := m.Put(adt.CidKey(code), &perm); err != nil {
+			return cid.Undef, xerrors.Errorf("failed to store exec permission for %v: %w", code, err)
+		}
+	}
+	return m.Root()
+}
+
+// CanExec returns whether an actor with code callerCode may create an actor with code execCode.
+func (s *State) CanExec(store adt.Store, callerCode, execCode cid.Cid) (bool, error) {
+	m, err := adt.AsMap(store, s.ExecPermissions)
+	if err != nil {
+		return false, xerrors.Errorf("failed to load exec permissions: %w", err)
+	}
+	var perm ExecPermission
+	found, err := m.Get(adt.CidKey(execCode), &perm)
+	if err != nil {
+		return false, xerrors.Errorf("failed to get exec permission for %v: %w", execCode, err)
+	}
+	if !found {
+		return false, nil
+	}
+	if perm.AnyCaller {
+		return true, nil
+	}
+	for _, c := range perm.Callers {
+		if c.Equals(callerCode) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Sets whether an actor with code callerCode may create actors with code execCode.
+func (s *State) SetExecCaller(store adt.Store, execCode, callerCode cid.Cid, allowed bool) error {
+	return s.updateExecPermission(store, execCode, func(perm *ExecPermission) {
+		callers := make([]cid.Cid, 0, len(perm.Callers)+1)
+		for _, c := range perm.Callers {
+			if !c.Equals(callerCode) {
+				callers = append(callers, c)
+			}
+		}
+		if allowed {
+			callers = append(callers, callerCode)
+		}
+		perm.Callers = callers
+	})
+}
+
+// Sets whether any caller may create actors with code execCode.
+func (s *State) SetExecAnyCaller(store adt.Store, execCode cid.Cid, anyCaller bool) error {
+	return s.updateExecPermission(store, execCode, func(perm *ExecPermission) {
+		perm.AnyCaller = anyCaller
+	})
+}
+
+// Applies an update to the exec permission for actors with some code. A permission allowing no callers is removed.
+func (s *State) updateExecPermission(store adt.Store, execCode cid.Cid, update func(perm *ExecPermission)) error {
+	m, err := adt.AsMap(store, s.ExecPermissions)
+	if err != nil {
+		return xerrors.Errorf("failed to load exec permissions: %w", err)
+	}
+	var perm ExecPermission
+	found, err := m.Get(adt.CidKey(execCode), &perm)
+	if err != nil {
+		return xerrors.Errorf("failed to get exec permission for %v: %w", execCode, err)
+	}
+
+	update(&perm)
+	if perm.AnyCaller || len(perm.Callers) > 0 {
+		err = m.Put(adt.CidKey(execCode), &perm)
+	} else if found {
+		err = m.Delete(adt.CidKey(execCode))
+	}
+	if err != nil {
+		return xerrors.Errorf("failed to set exec permission for %v: %w", execCode, err)
+	}
+	if s.ExecPermissions, err = m.Root(); err != nil {
+		return xerrors.Errorf("failed to flush exec permissions: %w", err)
+	}
+	return nil
+}
+
 // ResolveAddress resolves an address to an ID-address, if possible.
 // If the provided address is an ID address, it is returned as-is.
 // This means that mapped ID-addresses (which should only appear as values, not keys) and
@@ -63,12 +178,36 @@ func (s *State) ResolveAddress(store adt.Store, address addr.Address) (addr.Addr
 	}
 }
 
-// Allocates a new ID address and stores a mapping of the argument address to it.
+// ResolveIDToRobust returns the address that was mapped to an actor ID, if any.
+// Returns an undefined address and `false` if no address was mapped to the ID, which is the case for singleton
+// actors.
+func (s *State) ResolveIDToRobust(store adt.Store, id abi.ActorID) (addr.Address, bool, error) {
+	m, err := adt.AsMap(store, s.RobustAddresses)
+	if err != nil {
+		return addr.Undef, false, xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+
+	var robust addr.Address
+	found, err := m.Get(adt.UIntKey(uint64(id)), &robust)
+	if err != nil {
+		return addr.Undef, false, xerrors.Errorf("failed to get from robust address map: %w", err)
+	}
+	if !found {
+		return addr.Undef, false, nil
+	}
+	return robust, true, nil
+}
+
+// Allocates a new ID address and stores a mapping of the argument address to it, and its reverse.
 // Returns the newly-allocated address.
 func (s *State) MapAddressToNewID(store adt.Store, address addr.Address) (addr.Address, error) {
 	actorID := cbg.CborInt(s.NextID)
 	s.NextID++
 
+	if err := s.putRobustAddress(store, abi.ActorID(actorID), address); err != nil {
+		return addr.Undef, err
+	}
+
 	m, err := adt.AsMap(store, s.AddressMap)
 	if err != nil {
 		return addr.Undef, xerrors.Errorf("failed to load address map: %w", err)
@@ -87,3 +226,18 @@ func (s *State) MapAddressToNewID(store adt.Store, address addr.Address) (addr.A
 	autil.Assert(err == nil)
 	return idAddr, nil
 }
+
+// Stores the reverse mapping of an actor ID to its robust address.
+func (s *State) putRobustAddress(store adt.Store, id abi.ActorID, address addr.Address) error {
+	m, err := adt.AsMap(store, s.RobustAddresses)
+	if err != nil {
+		return xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+	if err = m.Put(adt.UIntKey(uint64(id)), &address); err != nil {
+		return xerrors.Errorf("robust address map failed to store entry: %w", err)
+	}
+	if s.RobustAddresses, err = m.Root(); err != nil {
+		return xerrors.Errorf("failed to get robust address map root: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package init
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	xerrors "golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// The state of the init actor before it maintained a reverse mapping of IDs to robust addresses and a table of
+// exec permissions.
+type LegacyState struct {
+	AddressMap  cid.Cid // HAMT[addr.Address]abi.ActorID
+	NextID      abi.ActorID
+	NetworkName string
+}
+
+// MigrateLegacyState converts a legacy init actor state to the current layout, populating the robust address map
+// from the address map and the exec permissions with the defaults.
+func MigrateLegacyState(store adt.Store, old *LegacyState) (*State, error) {
+	addresses, err := adt.AsMap(store, old.AddressMap)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load address map: %w", err)
+	}
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create robust address map: %w", err)
+	}
+
+	execPermissions, err := ConstructExecPermissions(store, DefaultExecPermissions())
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create exec permissions: %w", err)
+	}
+
+	st := ConstructState(old.AddressMap, emptyMap, execPermissions, old.NetworkName)
+	st.NextID = old.NextID
+
+	robust, err := adt.AsMap(store, st.RobustAddresses)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load robust address map: %w", err)
+	}
+	var actorID cbg.CborInt
+	err = addresses.ForEach(&actorID, func(key string) error {
+		address, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return xerrors.Errorf("invalid address key: %w", err)
+		}
+		return robust.Put(adt.UIntKey(uint64(actorID)), &address)
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to populate robust address map: %w", err)
+	}
+	if st.RobustAddresses, err = robust.Root(); err != nil {
+		return nil, xerrors.Errorf("failed to flush robust address map: %w", err)
+	}
+	return st, nil
+}
+
+// MigrateLegacyStateRoot loads a legacy init actor state from store, migrates it, and returns the root of the
+// migrated state.
+func MigrateLegacyStateRoot(store adt.Store, legacyRoot cid.Cid) (cid.Cid, error) {
+	var old LegacyState
+	if err := store.Get(store.Context(), legacyRoot, &old); err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load legacy state %v: %w", legacyRoot, err)
+	}
+	st, err := MigrateLegacyState(store, &old)
+	if err != nil {
+		return cid.Undef, err
+	}
+	root, err := store.Put(store.Context(), st)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to store migrated state: %w", err)
+	}
+	return root, nil
+}
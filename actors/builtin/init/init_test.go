@@ -5,7 +5,10 @@ import (
 	"testing"
 
 	cid "github.com/ipfs/go-cid"
+	"github.com/minio/blake2b-simd"
 	assert "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	addr "github.com/filecoin-project/go-address"
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
@@ -15,6 +18,7 @@ import (
 	runtime "github.com/filecoin-project/specs-actors/actors/runtime"
 	exitcode "github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+	ipld "github.com/filecoin-project/specs-actors/support/ipld"
 	mock "github.com/filecoin-project/specs-actors/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/support/testing"
 )
@@ -209,6 +213,276 @@ func TestExec(t *testing.T) {
 
 }
 
+func TestExec2(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	bob := tutil.NewIDAddr(t, 1002)
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	fakeParams := runtime.CBORBytes([]byte{'D', 'E', 'A', 'D', 'B', 'E', 'E', 'F'})
+	salt := []byte("salt")
+
+	precompute := func(creator addr.Address, salt []byte, code cid.Cid, params []byte) addr.Address {
+		a, err := init_.Exec2Address(creator, salt, code, blake2b.Sum256(params))
+		require.NoError(t, err)
+		return a
+	}
+
+	t.Run("creates actor at precomputed address", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		expectedRobust := precompute(anne, salt, builtin.MultisigActorCodeID, fakeParams)
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.ExpectCreateActor(builtin.MultisigActorCodeID, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		ret := actor.exec2AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, salt)
+		assert.Equal(t, expectedRobust, ret.RobustAddress)
+		assert.Equal(t, expectedIdAddr, ret.IDAddress)
+
+		var st init_.State
+		rt.GetState(&st)
+		resolved, found, err := st.ResolveAddress(adt.AsStore(rt), expectedRobust)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, expectedIdAddr, resolved)
+
+		// the same inputs cannot create another actor
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec2AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, salt)
+		})
+
+		// but any other salt, creator or parameters can
+		rt.ExpectCreateActor(builtin.MultisigActorCodeID, tutil.NewIDAddr(t, 101))
+		rt.ExpectSend(tutil.NewIDAddr(t, 101), builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		ret = actor.exec2AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, []byte("pepper"))
+		assert.Equal(t, precompute(anne, []byte("pepper"), builtin.MultisigActorCodeID, fakeParams), ret.RobustAddress)
+
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectCreateActor(builtin.MultisigActorCodeID, tutil.NewIDAddr(t, 102))
+		rt.ExpectSend(tutil.NewIDAddr(t, 102), builtin.MethodConstructor, fakeParams, big.Zero(), nil, exitcode.Ok)
+		ret = actor.exec2AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, salt)
+		assert.Equal(t, precompute(bob, salt, builtin.MultisigActorCodeID, fakeParams), ret.RobustAddress)
+		assert.NotEqual(t, expectedRobust, ret.RobustAddress)
+	})
+
+	t.Run("address depends on every input", func(t *testing.T) {
+		base := precompute(anne, salt, builtin.MultisigActorCodeID, fakeParams)
+		assert.Equal(t, addr.Actor, base.Protocol())
+		assert.NotEqual(t, base, precompute(bob, salt, builtin.MultisigActorCodeID, fakeParams))
+		assert.NotEqual(t, base, precompute(anne, []byte("pepper"), builtin.MultisigActorCodeID, fakeParams))
+		assert.NotEqual(t, base, precompute(anne, salt, builtin.PaymentChannelActorCodeID, fakeParams))
+		assert.NotEqual(t, base, precompute(anne, salt, builtin.MultisigActorCodeID, []byte{}))
+
+		_, err := init_.Exec2Address(tutil.NewActorAddr(t, "robust"), salt, builtin.MultisigActorCodeID, [32]byte{})
+		assert.Error(t, err)
+	})
+
+	t.Run("aborts for oversized salt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.exec2AndVerify(rt, builtin.MultisigActorCodeID, fakeParams, make([]byte, init_.MaxExec2SaltLength+1))
+		})
+	})
+
+	t.Run("aborts actors that cannot call exec", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.exec2AndVerify(rt, builtin.StorageMinerActorCodeID, fakeParams, salt)
+		})
+	})
+}
+
+func TestResolveIDToRobust(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	rt := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID).Build(t)
+	actor.constructAndVerify(rt)
+
+	rt.SetCaller(anne, builtin.AccountActorCodeID)
+	uniqueAddr := tutil.NewActorAddr(t, "paych")
+	rt.SetNewActorAddress(uniqueAddr)
+	expectedIdAddr := tutil.NewIDAddr(t, 100)
+	rt.ExpectCreateActor(builtin.PaymentChannelActorCodeID, expectedIdAddr)
+	rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, runtime.CBORBytes(nil), big.Zero(), nil, exitcode.Ok)
+	actor.execAndVerify(rt, builtin.PaymentChannelActorCodeID, nil)
+
+	t.Run("resolves allocated ID", func(t *testing.T) {
+		var st init_.State
+		rt.GetState(&st)
+		robust, found, err := st.ResolveIDToRobust(adt.AsStore(rt), 100)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, uniqueAddr, robust)
+
+		rt.ExpectValidateCallerAny()
+		ret := rt.Call(actor.ResolveIDToRobust, &expectedIdAddr).(*addr.Address)
+		rt.Verify()
+		assert.Equal(t, uniqueAddr, *ret)
+	})
+
+	t.Run("singleton actors have no robust address", func(t *testing.T) {
+		var st init_.State
+		rt.GetState(&st)
+		_, found, err := st.ResolveIDToRobust(adt.AsStore(rt), 4)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.ResolveIDToRobust, &builtin.StoragePowerActorAddr)
+		})
+	})
+
+	t.Run("rejects non-ID address", func(t *testing.T) {
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.ResolveIDToRobust, &uniqueAddr)
+		})
+	})
+}
+
+func TestExecPermissions(t *testing.T) {
+	actor := initHarness{init_.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 1000)
+	anne := tutil.NewIDAddr(t, 1001)
+	governor := tutil.NewIDAddr(t, 1002)
+	builder := mock.NewBuilder(context.Background(), receiver).WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	t.Run("system actor grants and revokes permission to a caller type", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		actor.setExecCaller(rt, builtin.StorageMinerActorCodeID, builtin.AccountActorCodeID, true)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		expectedIdAddr := tutil.NewIDAddr(t, 100)
+		rt.SetNewActorAddress(tutil.NewActorAddr(t, "miner"))
+		rt.ExpectCreateActor(builtin.StorageMinerActorCodeID, expectedIdAddr)
+		rt.ExpectSend(expectedIdAddr, builtin.MethodConstructor, runtime.CBORBytes(nil), big.Zero(), nil, exitcode.Ok)
+		actor.execAndVerify(rt, builtin.StorageMinerActorCodeID, nil)
+
+		// revoking one caller type leaves the others
+		rt.SetCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+		actor.setExecCaller(rt, builtin.StorageMinerActorCodeID, builtin.AccountActorCodeID, false)
+		var st init_.State
+		rt.GetState(&st)
+		can, err := st.CanExec(adt.AsStore(rt), builtin.StoragePowerActorCodeID, builtin.StorageMinerActorCodeID)
+		require.NoError(t, err)
+		assert.True(t, can)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.execAndVerify(rt, builtin.StorageMinerActorCodeID, nil)
+		})
+	})
+
+	t.Run("system actor revokes permission for any caller", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, builtin.SystemActorAddr)
+		rt.Call(actor.SetExecAnyCaller, &init_.SetExecAnyCallerParams{CodeCID: builtin.PaymentChannelActorCodeID, AnyCaller: false})
+		rt.Verify()
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.execAndVerify(rt, builtin.PaymentChannelActorCodeID, nil)
+		})
+	})
+
+	t.Run("governor edits permissions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, builtin.SystemActorAddr)
+		rt.Call(actor.SetGovernor, &governor)
+		rt.Verify()
+		var st init_.State
+		rt.GetState(&st)
+		assert.Equal(t, governor, st.Governor)
+
+		rt.SetCaller(governor, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, governor)
+		rt.Call(actor.SetExecCaller, &init_.SetExecCallerParams{
+			CodeCID:       builtin.StoragePowerActorCodeID,
+			CallerCodeCID: builtin.MultisigActorCodeID,
+			Allowed:       true,
+		})
+		rt.Verify()
+
+		rt.GetState(&st)
+		can, err := st.CanExec(adt.AsStore(rt), builtin.MultisigActorCodeID, builtin.StoragePowerActorCodeID)
+		require.NoError(t, err)
+		assert.True(t, can)
+	})
+
+	t.Run("others cannot edit permissions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.SetExecCaller, &init_.SetExecCallerParams{
+				CodeCID:       builtin.StorageMinerActorCodeID,
+				CallerCodeCID: builtin.AccountActorCodeID,
+				Allowed:       true,
+			})
+		})
+		rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, builtin.SystemActorAddr)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.SetGovernor, &anne)
+		})
+	})
+}
+
+func TestMigrateLegacyState(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+
+	addresses := adt.MakeEmptyMap(store)
+	robust1 := tutil.NewActorAddr(t, "one")
+	robust2 := tutil.NewBLSAddr(t, 2)
+	id1 := cbg.CborInt(100)
+	id2 := cbg.CborInt(101)
+	require.NoError(t, addresses.Put(adt.AddrKey(robust1), &id1))
+	require.NoError(t, addresses.Put(adt.AddrKey(robust2), &id2))
+
+	legacy := &init_.LegacyState{AddressMap: tutil.MustRoot(t, addresses), NextID: 102, NetworkName: "legacy"}
+	legacyRoot, err := store.Put(context.Background(), legacy)
+	require.NoError(t, err)
+
+	root, err := init_.MigrateLegacyStateRoot(store, legacyRoot)
+	require.NoError(t, err)
+	var st init_.State
+	require.NoError(t, store.Get(context.Background(), root, &st))
+
+	assert.Equal(t, legacy.AddressMap, st.AddressMap)
+	assert.Equal(t, legacy.NextID, st.NextID)
+	assert.Equal(t, legacy.NetworkName, st.NetworkName)
+
+	for id, expected := range map[abi.ActorID]addr.Address{100: robust1, 101: robust2} { //nolint:nomaprange
+		robust, found, err := st.ResolveIDToRobust(store, id)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, expected, robust)
+	}
+
+	can, err := st.CanExec(store, builtin.AccountActorCodeID, builtin.PaymentChannelActorCodeID)
+	require.NoError(t, err)
+	assert.True(t, can)
+	assert.Equal(t, builtin.SystemActorAddr, st.Governor)
+}
+
 type initHarness struct {
 	init_.Actor
 	t testing.TB
@@ -225,8 +499,16 @@ func (h *initHarness) constructAndVerify(rt *mock.Runtime) {
 	emptyMap, err := adt.AsMap(adt.AsStore(rt), st.AddressMap)
 	assert.NoError(h.t, err)
 	assert.Equal(h.t, tutil.MustRoot(h.t, emptyMap), st.AddressMap)
+	assert.Equal(h.t, tutil.MustRoot(h.t, emptyMap), st.RobustAddresses)
 	assert.Equal(h.t, abi.ActorID(builtin.FirstNonSingletonActorId), st.NextID)
 	assert.Equal(h.t, "mock", st.NetworkName)
+	assert.Equal(h.t, builtin.SystemActorAddr, st.Governor)
+}
+
+func (h *initHarness) setExecCaller(rt *mock.Runtime, code, callerCode cid.Cid, allowed bool) {
+	rt.ExpectValidateCallerAddr(builtin.SystemActorAddr, builtin.SystemActorAddr)
+	rt.Call(h.SetExecCaller, &init_.SetExecCallerParams{CodeCID: code, CallerCodeCID: callerCode, Allowed: allowed})
+	rt.Verify()
 }
 
 func (h *initHarness) execAndVerify(rt *mock.Runtime, codeID cid.Cid, constructorParams []byte) *init_.ExecReturn {
@@ -238,3 +520,14 @@ func (h *initHarness) execAndVerify(rt *mock.Runtime, codeID cid.Cid, constructo
 	rt.Verify()
 	return ret
 }
+
+func (h *initHarness) exec2AndVerify(rt *mock.Runtime, codeID cid.Cid, constructorParams []byte, salt []byte) *init_.ExecReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.Exec2, &init_.Exec2Params{
+		CodeCID:           codeID,
+		ConstructorParams: constructorParams,
+		Salt:              salt,
+	}).(*init_.ExecReturn)
+	rt.Verify()
+	return ret
+}
@@ -0,0 +1,146 @@
+package market
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// A change to a deal proposal between two market states.
+// From is nil for an added proposal, and To is nil for a removed one.
+type DealProposalChange struct {
+	ID   abi.DealID
+	From *DealProposal
+	To   *DealProposal
+}
+
+// A change to a deal state between two market states.
+// From is nil for an added state, and To is nil for a removed one.
+type DealStateChange struct {
+	ID   abi.DealID
+	From *DealState
+	To   *DealState
+}
+
+// A change to an address's balance in an escrow or locked table between two market states.
+// An absent balance is zero.
+type BalanceChange struct {
+	Address addr.Address
+	From    abi.TokenAmount
+	To      abi.TokenAmount
+}
+
+// DiffDealProposals returns the deal proposals that changed between two market states, in order of deal ID.
+func DiffDealProposals(store adt.Store, pre, cur *State) ([]DealProposalChange, error) {
+	changes, err := adt.DiffArray(store, pre.Proposals, cur.Proposals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff deal proposals: %w", err)
+	}
+	preProposals, err := AsDealProposalArray(store, pre.Proposals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal proposals: %w", err)
+	}
+	curProposals, err := AsDealProposalArray(store, cur.Proposals)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal proposals: %w", err)
+	}
+
+	var result []DealProposalChange
+	for _, i := range changes.Indices() {
+		change := DealProposalChange{ID: abi.DealID(i)}
+		from, found, err := preProposals.Get(change.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal proposal %d: %w", i, err)
+		} else if found {
+			change.From = from
+		}
+		to, found, err := curProposals.Get(change.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal proposal %d: %w", i, err)
+		} else if found {
+			change.To = to
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// DiffDealStates returns the deal states that changed between two market states, in order of deal ID.
+func DiffDealStates(store adt.Store, pre, cur *State) ([]DealStateChange, error) {
+	changes, err := adt.DiffArray(store, pre.States, cur.States)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff deal states: %w", err)
+	}
+	preStates, err := AsDealStateArray(store, pre.States)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal states: %w", err)
+	}
+	curStates, err := AsDealStateArray(store, cur.States)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load deal states: %w", err)
+	}
+
+	var result []DealStateChange
+	for _, i := range changes.Indices() {
+		change := DealStateChange{ID: abi.DealID(i)}
+		from, found, err := preStates.Get(change.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal state %d: %w", i, err)
+		} else if found {
+			change.From = from
+		}
+		to, found, err := curStates.Get(change.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deal state %d: %w", i, err)
+		} else if found {
+			change.To = to
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// DiffEscrowTable returns the escrow balances that changed between two market states.
+func DiffEscrowTable(store adt.Store, pre, cur *State) ([]BalanceChange, error) {
+	return diffBalanceTables(store, pre.EscrowTable, cur.EscrowTable)
+}
+
+// DiffLockedTable returns the locked balances that changed between two market states.
+func DiffLockedTable(store adt.Store, pre, cur *State) ([]BalanceChange, error) {
+	return diffBalanceTables(store, pre.LockedTable, cur.LockedTable)
+}
+
+func diffBalanceTables(store adt.Store, preRoot, curRoot cid.Cid) ([]BalanceChange, error) {
+	changes, err := adt.DiffMap(store, preRoot, curRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff balance tables: %w", err)
+	}
+	preTable, err := adt.AsBalanceTable(store, preRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load balance table: %w", err)
+	}
+	curTable, err := adt.AsBalanceTable(store, curRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load balance table: %w", err)
+	}
+
+	var result []BalanceChange
+	for _, key := range changes.Keys() {
+		a, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse balance table key: %w", err)
+		}
+		change := BalanceChange{Address: a}
+		if change.From, err = preTable.Get(a); err != nil {
+			return nil, xerrors.Errorf("failed to load balance of %v: %w", a, err)
+		}
+		if change.To, err = curTable.Get(a); err != nil {
+			return nil, xerrors.Errorf("failed to load balance of %v: %w", a, err)
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
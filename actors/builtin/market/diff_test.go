@@ -0,0 +1,94 @@
+package market_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestDiffState(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	emptyArray, err := adt.MakeEmptyArray(store).Root()
+	require.NoError(t, err)
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	pre := market.ConstructState(emptyArray, emptyMap, emptyMap)
+
+	client := tutil.NewIDAddr(t, 100)
+	provider := tutil.NewIDAddr(t, 101)
+	proposal := market.DealProposal{
+		PieceCID:             tutil.MakeCID("piece", &market.PieceCIDPrefix),
+		PieceSize:            abi.PaddedPieceSize(128),
+		Client:               client,
+		Provider:             provider,
+		StartEpoch:           10,
+		EndEpoch:             20,
+		StoragePricePerEpoch: abi.NewTokenAmount(1),
+		ProviderCollateral:   abi.NewTokenAmount(2),
+		ClientCollateral:     abi.NewTokenAmount(3),
+	}
+
+	cur := *pre
+	proposals, err := market.AsDealProposalArray(store, cur.Proposals)
+	require.NoError(t, err)
+	require.NoError(t, proposals.Set(7, &proposal))
+	cur.Proposals, err = proposals.Root()
+	require.NoError(t, err)
+
+	states, err := market.AsDealStateArray(store, cur.States)
+	require.NoError(t, err)
+	require.NoError(t, states.Set(7, &market.DealState{SectorStartEpoch: 11, LastUpdatedEpoch: -1, SlashEpoch: -1}))
+	cur.States, err = states.Root()
+	require.NoError(t, err)
+
+	escrow, err := adt.AsBalanceTable(store, cur.EscrowTable)
+	require.NoError(t, err)
+	require.NoError(t, escrow.Add(client, abi.NewTokenAmount(50)))
+	require.NoError(t, escrow.Add(provider, abi.NewTokenAmount(60)))
+	cur.EscrowTable, err = escrow.Root()
+	require.NoError(t, err)
+
+	locked, err := adt.AsBalanceTable(store, cur.LockedTable)
+	require.NoError(t, err)
+	require.NoError(t, locked.Add(client, abi.NewTokenAmount(13)))
+	cur.LockedTable, err = locked.Root()
+	require.NoError(t, err)
+
+	proposalChanges, err := market.DiffDealProposals(store, pre, &cur)
+	require.NoError(t, err)
+	require.Len(t, proposalChanges, 1)
+	assert.Equal(t, abi.DealID(7), proposalChanges[0].ID)
+	assert.Nil(t, proposalChanges[0].From)
+	assert.Equal(t, proposal, *proposalChanges[0].To)
+
+	stateChanges, err := market.DiffDealStates(store, &cur, pre)
+	require.NoError(t, err)
+	require.Len(t, stateChanges, 1)
+	assert.Equal(t, abi.ChainEpoch(11), stateChanges[0].From.SectorStartEpoch)
+	assert.Nil(t, stateChanges[0].To)
+
+	escrowChanges, err := market.DiffEscrowTable(store, pre, &cur)
+	require.NoError(t, err)
+	require.Len(t, escrowChanges, 2)
+	for _, c := range escrowChanges {
+		assert.True(t, c.From.IsZero())
+		if c.Address == client {
+			assert.Equal(t, abi.NewTokenAmount(50), c.To)
+		} else {
+			assert.Equal(t, provider, c.Address)
+			assert.Equal(t, abi.NewTokenAmount(60), c.To)
+		}
+	}
+
+	lockedChanges, err := market.DiffLockedTable(store, pre, &cur)
+	require.NoError(t, err)
+	assert.Equal(t, []market.BalanceChange{{Address: client, From: abi.NewTokenAmount(0), To: abi.NewTokenAmount(13)}}, lockedChanges)
+}
@@ -77,6 +77,37 @@ func ConstructState(emptyArrayCid, emptyMapCid, emptyMSetCid cid.Cid) *State {
 	}
 }
 
+// Iterates at most limit deal proposals with IDs at or after cursor, in increasing order.
+// Returns the deal ID from which to continue iteration, and whether any proposals may remain after it.
+func (s *State) PageDealProposals(store adt.Store, cursor abi.DealID, limit uint64, f func(abi.DealID, *DealProposal) error) (abi.DealID, bool, error) {
+	proposals, err := AsDealProposalArray(store, s.Proposals)
+	if err != nil {
+		return cursor, false, xerrors.Errorf("failed to load deal proposals: %w", err)
+	}
+	var proposal DealProposal
+	next, more, err := proposals.Page(uint64(cursor), limit, &proposal, func(idx int64) error {
+		return f(abi.DealID(idx), &proposal)
+	})
+	return abi.DealID(next), more, err
+}
+
+// Iterates at most limit pending deal proposals after cursor, starting from the first with a nil cursor.
+// Returns the cursor from which to continue iteration, and whether any proposals may remain after it.
+func (s *State) PagePendingProposals(store adt.Store, cursor adt.MapCursor, limit uint64, f func(cid.Cid, *DealProposal) error) (adt.MapCursor, bool, error) {
+	pending, err := adt.AsMap(store, s.PendingProposals)
+	if err != nil {
+		return cursor, false, xerrors.Errorf("failed to load pending proposals: %w", err)
+	}
+	var proposal DealProposal
+	return pending.Page(cursor, limit, &proposal, func(key string) error {
+		c, err := cid.Cast([]byte(key))
+		if err != nil {
+			return xerrors.Errorf("failed to parse pending proposal key: %w", err)
+		}
+		return f(c, &proposal)
+	})
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // Deal state operations
 ////////////////////////////////////////////////////////////////////////////////
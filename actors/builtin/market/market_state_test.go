@@ -0,0 +1,131 @@
+package market_test
+
+import (
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestPageDeals(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	emptyArray, err := adt.MakeEmptyArray(store).Root()
+	require.NoError(t, err)
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	st := market.ConstructState(emptyArray, emptyMap, emptyMap)
+
+	proposals, err := market.AsDealProposalArray(store, st.Proposals)
+	require.NoError(t, err)
+	pending, err := adt.AsMap(store, st.PendingProposals)
+	require.NoError(t, err)
+	expectedPending := map[cid.Cid]abi.DealID{}
+	for id := abi.DealID(0); id < 25; id++ {
+		proposal := market.DealProposal{
+			PieceCID:             tutil.MakeCID("piece", &market.PieceCIDPrefix),
+			PieceSize:            abi.PaddedPieceSize(128),
+			Client:               tutil.NewIDAddr(t, 100),
+			Provider:             tutil.NewIDAddr(t, 101),
+			StartEpoch:           abi.ChainEpoch(id) + 10,
+			EndEpoch:             abi.ChainEpoch(id) + 20,
+			StoragePricePerEpoch: abi.NewTokenAmount(1),
+			ProviderCollateral:   abi.NewTokenAmount(2),
+			ClientCollateral:     abi.NewTokenAmount(3),
+		}
+		require.NoError(t, proposals.Set(id, &proposal))
+		pcid, err := proposal.Cid()
+		require.NoError(t, err)
+		require.NoError(t, pending.Put(adt.CidKey(pcid), &proposal))
+		expectedPending[pcid] = id
+	}
+	st.Proposals, err = proposals.Root()
+	require.NoError(t, err)
+	st.PendingProposals, err = pending.Root()
+	require.NoError(t, err)
+
+	t.Run("proposals", func(t *testing.T) {
+		var ids []abi.DealID
+		cursor, more := abi.DealID(0), true
+		for more {
+			cursor, more, err = st.PageDealProposals(store, cursor, 10, func(id abi.DealID, proposal *market.DealProposal) error {
+				assert.Equal(t, abi.ChainEpoch(id)+10, proposal.StartEpoch)
+				ids = append(ids, id)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+		require.Len(t, ids, 25)
+		assert.Equal(t, abi.DealID(24), ids[24])
+	})
+
+	t.Run("pending proposals", func(t *testing.T) {
+		visited := map[cid.Cid]abi.DealID{}
+		var cursor adt.MapCursor
+		more := true
+		for more {
+			cursor, more, err = st.PagePendingProposals(store, cursor, 10, func(c cid.Cid, proposal *market.DealProposal) error {
+				assert.NotContains(t, visited, c)
+				visited[c] = abi.DealID(proposal.StartEpoch - 10)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+		assert.Equal(t, expectedPending, visited)
+	})
+}
+
+func TestJournaledBalances(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	emptyArray, err := adt.MakeEmptyArray(store).Root()
+	require.NoError(t, err)
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	st := market.ConstructState(emptyArray, emptyMap, emptyMap)
+	client := tutil.NewIDAddr(t, 100)
+	provider := tutil.NewIDAddr(t, 101)
+
+	locked, err := market.AsJournaledBalances(store, st.LockedTable, emptyMap, 1)
+	require.NoError(t, err)
+	require.NoError(t, locked.Add(client, abi.NewTokenAmount(30), market.ClientCollateral))
+	require.NoError(t, locked.Add(client, abi.NewTokenAmount(20), market.ClientStorageFee))
+	require.NoError(t, locked.Add(provider, abi.NewTokenAmount(40), market.ProviderCollateral))
+	sub, err := locked.SubtractWithMinimum(client, abi.NewTokenAmount(15), abi.NewTokenAmount(40), market.ClientStorageFee)
+	require.NoError(t, err)
+	assert.Equal(t, abi.NewTokenAmount(10), sub)
+	require.NoError(t, locked.MustSubtract(provider, abi.NewTokenAmount(40), market.ProviderCollateral))
+	assert.Error(t, locked.MustSubtract(provider, abi.NewTokenAmount(1), market.ProviderCollateral))
+
+	var journalRoot cid.Cid
+	st.LockedTable, journalRoot, err = locked.Root()
+	require.NoError(t, err)
+
+	lockedTable, err := adt.AsBalanceTable(store, st.LockedTable)
+	require.NoError(t, err)
+	balance, err := lockedTable.Get(client)
+	require.NoError(t, err)
+	assert.Equal(t, abi.NewTokenAmount(40), balance)
+
+	journal, err := adt.AsBalanceJournal(store, journalRoot)
+	require.NoError(t, err)
+	var reasons []market.BalanceLockingReason
+	require.NoError(t, journal.ForEach(client, func(entry *adt.BalanceJournalEntry) error {
+		reasons = append(reasons, market.BalanceLockingReason(entry.Reason))
+		return nil
+	}))
+	assert.Equal(t, []market.BalanceLockingReason{market.ClientCollateral, market.ClientStorageFee, market.ClientStorageFee}, reasons)
+
+	reasons = nil
+	require.NoError(t, journal.ForEach(provider, func(entry *adt.BalanceJournalEntry) error {
+		reasons = append(reasons, market.BalanceLockingReason(entry.Reason))
+		return nil
+	}))
+	assert.Equal(t, []market.BalanceLockingReason{market.ProviderCollateral, market.ProviderCollateral}, reasons)
+}
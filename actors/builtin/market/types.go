@@ -1,6 +1,8 @@
 package market
 
 import (
+	addr "github.com/filecoin-project/go-address"
+
 	"github.com/filecoin-project/specs-actors/actors/abi"
 	. "github.com/filecoin-project/specs-actors/actors/util/adt"
 
@@ -87,3 +89,36 @@ func (t *DealMetaArray) Set(k abi.DealID, value *DealState) error {
 func (t *DealMetaArray) Delete(id abi.DealID) error {
 	return t.Array.Delete(uint64(id))
 }
+
+// A specialization of a journaled balance table to market balances, recording the BalanceLockingReason for
+// each change as its journal entry reason.
+type JournaledBalances struct {
+	*JournaledBalanceTable
+}
+
+// Interprets a store as a balance table with root `tableRoot` and journal with root `journalRoot`,
+// journaling changes at epoch `epoch`.
+func AsJournaledBalances(s Store, tableRoot, journalRoot cid.Cid, epoch abi.ChainEpoch) (*JournaledBalances, error) {
+	t, err := AsJournaledBalanceTable(s, tableRoot, journalRoot, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaledBalances{t}, nil
+}
+
+// Adds an amount to a balance, journaling the change with a reason.
+func (t *JournaledBalances) Add(key addr.Address, value abi.TokenAmount, reason BalanceLockingReason) error {
+	return t.JournaledBalanceTable.Add(key, value, int64(reason))
+}
+
+// Subtracts up to the specified amount from a balance, without reducing it below some minimum,
+// journaling the change with a reason. Returns the amount subtracted.
+func (t *JournaledBalances) SubtractWithMinimum(key addr.Address, req abi.TokenAmount, floor abi.TokenAmount, reason BalanceLockingReason) (abi.TokenAmount, error) {
+	return t.JournaledBalanceTable.SubtractWithMinimum(key, req, floor, int64(reason))
+}
+
+// Subtracts an amount from a balance, journaling the change with a reason.
+// Returns an error if the balance is insufficient.
+func (t *JournaledBalances) MustSubtract(key addr.Address, req abi.TokenAmount, reason BalanceLockingReason) error {
+	return t.JournaledBalanceTable.MustSubtract(key, req, int64(reason))
+}
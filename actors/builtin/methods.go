@@ -14,15 +14,29 @@ var MethodsAccount = struct {
 	PubkeyAddress abi.MethodNum
 }{MethodConstructor, 2}
 
+var MethodsMultiKeyAccount = struct {
+	Constructor              abi.MethodNum
+	RotateKeys               abi.MethodNum
+	VerifyThresholdSignature abi.MethodNum
+	Send                     abi.MethodNum
+}{MethodConstructor, 2, 3, 4}
+
 var MethodsInit = struct {
-	Constructor abi.MethodNum
-	Exec        abi.MethodNum
-}{MethodConstructor, 2}
+	Constructor       abi.MethodNum
+	Exec              abi.MethodNum
+	Exec2             abi.MethodNum
+	ResolveIDToRobust abi.MethodNum
+	SetExecCaller     abi.MethodNum
+	SetExecAnyCaller  abi.MethodNum
+	SetGovernor       abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7}
 
 var MethodsCron = struct {
 	Constructor abi.MethodNum
 	EpochTick   abi.MethodNum
-}{MethodConstructor, 2}
+	AddEntry    abi.MethodNum
+	RemoveEntry abi.MethodNum
+}{MethodConstructor, 2, 3, 4}
 
 var MethodsReward = struct {
 	Constructor      abi.MethodNum
@@ -40,14 +54,19 @@ var MethodsMultisig = struct {
 	RemoveSigner                abi.MethodNum
 	SwapSigner                  abi.MethodNum
 	ChangeNumApprovalsThreshold abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8}
+	PruneExpired                abi.MethodNum
+	LockBalance                 abi.MethodNum
+	ApproveMany                 abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
 
 var MethodsPaych = struct {
 	Constructor        abi.MethodNum
 	UpdateChannelState abi.MethodNum
 	Settle             abi.MethodNum
 	Collect            abi.MethodNum
-}{MethodConstructor, 2, 3, 4}
+	RegisterHTLC       abi.MethodNum
+	WithdrawRedeemed   abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6}
 
 var MethodsMarket = struct {
 	Constructor              abi.MethodNum
@@ -97,10 +116,11 @@ var MethodsMiner = struct {
 }{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
 
 var MethodsVerifiedRegistry = struct {
-	Constructor       abi.MethodNum
-	AddVerifier       abi.MethodNum
-	RemoveVerifier    abi.MethodNum
-	AddVerifiedClient abi.MethodNum
-	UseBytes          abi.MethodNum
-	RestoreBytes      abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6}
+	Constructor                 abi.MethodNum
+	AddVerifier                 abi.MethodNum
+	RemoveVerifier              abi.MethodNum
+	AddVerifiedClient           abi.MethodNum
+	UseBytes                    abi.MethodNum
+	RestoreBytes                abi.MethodNum
+	RemoveVerifiedClientDataCap abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7}
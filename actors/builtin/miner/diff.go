@@ -0,0 +1,183 @@
+package miner
+
+import (
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// A change to a sector's on-chain info between two miner states.
+// From is nil for an added sector, and To is nil for a removed one.
+type SectorChange struct {
+	Number abi.SectorNumber
+	From   *SectorOnChainInfo
+	To     *SectorOnChainInfo
+}
+
+// A change to a pre-committed sector between two miner states.
+// From is nil for a new pre-commitment, and To is nil for one that was proven or expired.
+type PreCommitChange struct {
+	Number abi.SectorNumber
+	From   *SectorPreCommitOnChainInfo
+	To     *SectorPreCommitOnChainInfo
+}
+
+// A change to a partition between two miner states.
+// From is nil for an added partition, and To is nil for a removed one.
+type PartitionChange struct {
+	Deadline  uint64
+	Partition uint64
+	From      *Partition
+	To        *Partition
+}
+
+// DiffSectors returns the sectors that changed between two miner states, in order of sector number.
+func DiffSectors(store adt.Store, pre, cur *State) ([]SectorChange, error) {
+	changes, err := adt.DiffArray(store, pre.Sectors, cur.Sectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff sectors: %w", err)
+	}
+	preSectors, err := adt.AsArray(store, pre.Sectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sectors: %w", err)
+	}
+	curSectors, err := adt.AsArray(store, cur.Sectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sectors: %w", err)
+	}
+
+	var result []SectorChange
+	for _, i := range changes.Indices() {
+		change := SectorChange{Number: abi.SectorNumber(i)}
+		var from, to SectorOnChainInfo
+		if change.From, err = getSectorIf(preSectors, i, &from); err != nil {
+			return nil, err
+		}
+		if change.To, err = getSectorIf(curSectors, i, &to); err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// DiffPreCommits returns the pre-committed sectors that changed between two miner states.
+func DiffPreCommits(store adt.Store, pre, cur *State) ([]PreCommitChange, error) {
+	changes, err := adt.DiffMap(store, pre.PreCommittedSectors, cur.PreCommittedSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff precommitted sectors: %w", err)
+	}
+	prePreCommits, err := adt.AsMap(store, pre.PreCommittedSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load precommitted sectors: %w", err)
+	}
+	curPreCommits, err := adt.AsMap(store, cur.PreCommittedSectors)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load precommitted sectors: %w", err)
+	}
+
+	var result []PreCommitChange
+	for _, key := range changes.Keys() {
+		sectorNo, err := adt.ParseUIntKey(key)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse precommitted sector key: %w", err)
+		}
+		change := PreCommitChange{Number: abi.SectorNumber(sectorNo)}
+		var from, to SectorPreCommitOnChainInfo
+		if found, err := prePreCommits.Get(SectorKey(change.Number), &from); err != nil {
+			return nil, xerrors.Errorf("failed to load precommitted sector %d: %w", sectorNo, err)
+		} else if found {
+			change.From = &from
+		}
+		if found, err := curPreCommits.Get(SectorKey(change.Number), &to); err != nil {
+			return nil, xerrors.Errorf("failed to load precommitted sector %d: %w", sectorNo, err)
+		} else if found {
+			change.To = &to
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
+
+// DiffPartitions returns the partitions that changed between two miner states, in order of deadline and
+// partition index. Unchanged deadlines are not loaded.
+func DiffPartitions(store adt.Store, pre, cur *State) ([]PartitionChange, error) {
+	if pre.Deadlines.Equals(cur.Deadlines) {
+		return nil, nil
+	}
+	preDeadlines, err := pre.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+	curDeadlines, err := cur.LoadDeadlines(store)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []PartitionChange
+	for dlIdx := range preDeadlines.Due {
+		if preDeadlines.Due[dlIdx].Equals(curDeadlines.Due[dlIdx]) {
+			continue
+		}
+		preDeadline, err := preDeadlines.LoadDeadline(store, uint64(dlIdx))
+		if err != nil {
+			return nil, err
+		}
+		curDeadline, err := curDeadlines.LoadDeadline(store, uint64(dlIdx))
+		if err != nil {
+			return nil, err
+		}
+		changes, err := adt.DiffArray(store, preDeadline.Partitions, curDeadline.Partitions)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to diff partitions of deadline %d: %w", dlIdx, err)
+		}
+		if len(changes.Indices()) == 0 {
+			continue
+		}
+		prePartitions, err := preDeadline.PartitionsArray(store)
+		if err != nil {
+			return nil, err
+		}
+		curPartitions, err := curDeadline.PartitionsArray(store)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range changes.Indices() {
+			change := PartitionChange{Deadline: uint64(dlIdx), Partition: i}
+			var from, to Partition
+			if change.From, err = getPartitionIf(prePartitions, i, &from); err != nil {
+				return nil, err
+			}
+			if change.To, err = getPartitionIf(curPartitions, i, &to); err != nil {
+				return nil, err
+			}
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+// Loads a sector into out, returning out if found and nil otherwise.
+func getSectorIf(arr *adt.Array, i uint64, out *SectorOnChainInfo) (*SectorOnChainInfo, error) {
+	found, err := arr.Get(i, out)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load sector %d: %w", i, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// Loads a partition into out, returning out if found and nil otherwise.
+func getPartitionIf(arr *adt.Array, i uint64, out *Partition) (*Partition, error) {
+	found, err := arr.Get(i, out)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load partition %d: %w", i, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package miner_test
+
+import (
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	tutils "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestDiffState(t *testing.T) {
+	sealed := func(s string) cid.Cid { return tutils.MakeCID(s, &miner.SealedCIDPrefix) }
+
+	h := constructStateHarness(t, abi.ChainEpoch(0))
+	h.putSector(newSectorOnChainInfo(1, sealed("1"), big.NewInt(1), 1))
+	h.putSector(newSectorOnChainInfo(2, sealed("2"), big.NewInt(1), 1))
+	h.putPreCommit(newSectorPreCommitOnChainInfo(3, sealed("3"), abi.NewTokenAmount(1), 1))
+	pre := *h.s
+
+	h.deleteSectors(1)
+	h.putSector(newSectorOnChainInfo(2, sealed("2"), big.NewInt(2), 1))
+	h.putSector(newSectorOnChainInfo(3, sealed("3"), big.NewInt(1), 2))
+	h.deletePreCommit(3)
+	h.putPreCommit(newSectorPreCommitOnChainInfo(4, sealed("4"), abi.NewTokenAmount(1), 2))
+	partition := addPartition(t, h, 5)
+	cur := *h.s
+
+	t.Run("sectors", func(t *testing.T) {
+		changes, err := miner.DiffSectors(h.store, &pre, &cur)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+
+		assert.Equal(t, abi.SectorNumber(1), changes[0].Number)
+		assert.NotNil(t, changes[0].From)
+		assert.Nil(t, changes[0].To)
+
+		assert.Equal(t, abi.SectorNumber(2), changes[1].Number)
+		assert.Equal(t, big.NewInt(1), changes[1].From.DealWeight)
+		assert.Equal(t, big.NewInt(2), changes[1].To.DealWeight)
+
+		assert.Equal(t, abi.SectorNumber(3), changes[2].Number)
+		assert.Nil(t, changes[2].From)
+		assert.Equal(t, abi.ChainEpoch(2), changes[2].To.Activation)
+	})
+
+	t.Run("pre-commits", func(t *testing.T) {
+		changes, err := miner.DiffPreCommits(h.store, &pre, &cur)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+
+		byNumber := map[abi.SectorNumber]miner.PreCommitChange{}
+		for _, c := range changes {
+			byNumber[c.Number] = c
+		}
+		assert.Nil(t, byNumber[3].To)
+		assert.Equal(t, abi.SectorNumber(3), byNumber[3].From.Info.SectorNumber)
+		assert.Nil(t, byNumber[4].From)
+		assert.Equal(t, abi.SectorNumber(4), byNumber[4].To.Info.SectorNumber)
+	})
+
+	t.Run("partitions", func(t *testing.T) {
+		changes, err := miner.DiffPartitions(h.store, &pre, &cur)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, uint64(5), changes[0].Deadline)
+		assert.Equal(t, uint64(0), changes[0].Partition)
+		assert.Nil(t, changes[0].From)
+		assert.Equal(t, partition.Sectors, changes[0].To.Sectors)
+
+		changes, err = miner.DiffPartitions(h.store, &cur, &cur)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+// Adds an empty partition to a deadline, returning it.
+func addPartition(t *testing.T, h *stateHarness, dlIdx uint64) *miner.Partition {
+	emptyArray, err := adt.MakeEmptyArray(h.store).Root()
+	require.NoError(t, err)
+	partition := miner.ConstructPartition(emptyArray)
+
+	deadlines, err := h.s.LoadDeadlines(h.store)
+	require.NoError(t, err)
+	deadline, err := deadlines.LoadDeadline(h.store, dlIdx)
+	require.NoError(t, err)
+	partitions, err := deadline.PartitionsArray(h.store)
+	require.NoError(t, err)
+	require.NoError(t, partitions.Set(0, partition))
+	deadline.Partitions, err = partitions.Root()
+	require.NoError(t, err)
+	require.NoError(t, deadlines.UpdateDeadline(h.store, dlIdx, deadline))
+	require.NoError(t, h.s.SaveDeadlines(h.store, deadlines))
+	return partition
+}
@@ -487,11 +487,7 @@ func (q ExpirationQueue) PopUntil(until abi.ChainEpoch) (*ExpirationSet, error)
 
 	var poppedKeys []uint64
 	var thisValue ExpirationSet
-	stopErr := fmt.Errorf("stop")
-	if err := q.Array.ForEach(&thisValue, func(i int64) error {
-		if abi.ChainEpoch(i) > until {
-			return stopErr
-		}
+	if err := q.forEachUntil(until, &thisValue, func(i int64) error {
 		poppedKeys = append(poppedKeys, uint64(i))
 		onTimeSectors = append(onTimeSectors, thisValue.OnTimeSectors)
 		earlySectors = append(earlySectors, thisValue.EarlySectors)
@@ -499,7 +495,7 @@ func (q ExpirationQueue) PopUntil(until abi.ChainEpoch) (*ExpirationSet, error)
 		faultyPower = faultyPower.Add(thisValue.FaultyPower)
 		onTimePledge = big.Add(onTimePledge, thisValue.OnTimePledge)
 		return nil
-	}); err != nil && err != stopErr {
+	}); err != nil {
 		return nil, err
 	}
 
@@ -570,6 +566,14 @@ func (q ExpirationQueue) removeActiveSectors(sectors []*SectorOnChainInfo, ssize
 	return removedSnos, removedPower, removedPledge, nil
 }
 
+// Iterates the entries of the queue up to and including some epoch.
+func (q ExpirationQueue) forEachUntil(until abi.ChainEpoch, es *ExpirationSet, f func(epoch int64) error) error {
+	if until < 0 {
+		return nil
+	}
+	return q.Array.ForEachRange(es, 0, uint64(until)+1, f)
+}
+
 // Traverses the entire queue with a callback function that may mutate entries.
 // Iff the function returns that it changed an entry, the new entry will be re-written in the queue. Any changed
 // entries that become empty are removed after iteration completes.
@@ -405,7 +405,7 @@ func (st *State) DeleteSectors(store adt.Store, sectorNos bitfield.BitField) err
 	return err
 }
 
-// Iterates sectors.
+// Iterates sectors in increasing order of sector number.
 // The pointer provided to the callback is not safe for re-use. Copy the pointed-to value in full to hold a reference.
 func (st *State) ForEachSector(store adt.Store, f func(*SectorOnChainInfo)) error {
 	sectors, err := LoadSectors(store, st.Sectors)
@@ -419,6 +419,21 @@ func (st *State) ForEachSector(store adt.Store, f func(*SectorOnChainInfo)) erro
 	})
 }
 
+// Iterates at most limit sectors with numbers at or after cursor, in increasing order.
+// Returns the sector number from which to continue iteration, and whether any sectors may remain after it.
+// This serves callers reading state outside message execution, such as APIs, that must bound each read.
+func (st *State) PageSectors(store adt.Store, cursor abi.SectorNumber, limit uint64, f func(*SectorOnChainInfo) error) (abi.SectorNumber, bool, error) {
+	sectors, err := LoadSectors(store, st.Sectors)
+	if err != nil {
+		return cursor, false, err
+	}
+	var sector SectorOnChainInfo
+	next, more, err := sectors.Page(uint64(cursor), limit, &sector, func(idx int64) error {
+		return f(&sector)
+	})
+	return abi.SectorNumber(next), more, err
+}
+
 func (st *State) FindSector(store adt.Store, sno abi.SectorNumber) (uint64, uint64, error) {
 	deadlines, err := st.LoadDeadlines(store)
 	if err != nil {
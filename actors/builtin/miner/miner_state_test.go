@@ -117,6 +117,28 @@ func TestSectorsStore(t *testing.T) {
 			assert.False(t, harness.hasSectorNo(abi.SectorNumber(s)))
 		}
 	})
+
+	t.Run("Page through sectors", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		for s := uint64(100); s <= 1000; s += 100 {
+			harness.putSector(newSectorOnChainInfo(abi.SectorNumber(s), tutils.MakeCID(fmt.Sprintf("%d", s), &miner.SealedCIDPrefix), big.NewInt(1), abi.ChainEpoch(1)))
+		}
+
+		var pages [][]abi.SectorNumber
+		cursor, more := abi.SectorNumber(150), true
+		for more {
+			var page []abi.SectorNumber
+			var err error
+			cursor, more, err = harness.s.PageSectors(harness.store, cursor, 4, func(si *miner.SectorOnChainInfo) error {
+				page = append(page, si.SectorNumber)
+				return nil
+			})
+			require.NoError(t, err)
+			pages = append(pages, page)
+		}
+		assert.Equal(t, [][]abi.SectorNumber{{200, 300, 400, 500}, {600, 700, 800, 900}, {1000}}, pages)
+		assert.Equal(t, abi.SectorNumber(1001), cursor)
+	})
 }
 
 // TODO minerstate: move to partition
@@ -0,0 +1,481 @@
+package multisig
+
+import (
+	"fmt"
+	"io"
+
+	address "github.com/filecoin-project/go-address"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+)
+
+// The types in this file are encoded by hand rather than generated, because their tuples have gained trailing
+// fields since they were first recorded in state or constructed by clients.
+// Each decodes from its original, shorter tuple with the added fields taking their zero values.
+// A value whose added fields are all zero encodes as the original tuple, so its encoding, CID and (for proposal
+// hash data) hash are unchanged.
+
+// State: [Signers, NumApprovalsThreshold, NextTxnID, InitialBalance, StartEpoch, UnlockDuration, PendingTxns,
+// LockedTranches?]
+// Signers of DefaultSignerWeight are encoded as bare addresses (see Signer).
+func (t *State) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	fields := uint64(7)
+	if len(t.LockedTranches) > 0 {
+		fields++
+	}
+	if err := cbg.CborWriteHeader(w, cbg.MajArray, fields); err != nil {
+		return err
+	}
+	if len(t.Signers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Signers was too long")
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, uint64(len(t.Signers))); err != nil {
+		return err
+	}
+	for _, v := range t.Signers {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, t.NumApprovalsThreshold); err != nil {
+		return err
+	}
+	if err := writeInt64(w, int64(t.NextTxnID)); err != nil {
+		return err
+	}
+	if err := t.InitialBalance.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := writeInt64(w, int64(t.StartEpoch)); err != nil {
+		return err
+	}
+	if err := writeInt64(w, int64(t.UnlockDuration)); err != nil {
+		return err
+	}
+	if err := cbg.WriteCid(w, t.PendingTxns); err != nil {
+		return xerrors.Errorf("failed to write cid field t.PendingTxns: %w", err)
+	}
+	if len(t.LockedTranches) > 0 {
+		if len(t.LockedTranches) > cbg.MaxLength {
+			return xerrors.Errorf("Slice value in field t.LockedTranches was too long")
+		}
+		if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, uint64(len(t.LockedTranches))); err != nil {
+			return err
+		}
+		for _, v := range t.LockedTranches {
+			if err := v.MarshalCBOR(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *State) UnmarshalCBOR(r io.Reader) error {
+	*t = State{}
+
+	br := cbg.GetPeeker(r)
+	fields, err := readTupleHeader(br, 7, 8)
+	if err != nil {
+		return err
+	}
+
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Signers: array too large (%d)", extra)
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+	if extra > 0 {
+		t.Signers = make([]Signer, extra)
+	}
+	for i := range t.Signers {
+		if err := t.Signers[i].UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Signers: %w", err)
+		}
+	}
+
+	if t.NumApprovalsThreshold, err = readUint64(br); err != nil {
+		return xerrors.Errorf("t.NumApprovalsThreshold: %w", err)
+	}
+	nextTxnID, err := readInt64(br)
+	if err != nil {
+		return xerrors.Errorf("t.NextTxnID: %w", err)
+	}
+	t.NextTxnID = TxnID(nextTxnID)
+	if err := t.InitialBalance.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.InitialBalance: %w", err)
+	}
+	startEpoch, err := readInt64(br)
+	if err != nil {
+		return xerrors.Errorf("t.StartEpoch: %w", err)
+	}
+	t.StartEpoch = abi.ChainEpoch(startEpoch)
+	unlockDuration, err := readInt64(br)
+	if err != nil {
+		return xerrors.Errorf("t.UnlockDuration: %w", err)
+	}
+	t.UnlockDuration = abi.ChainEpoch(unlockDuration)
+	if t.PendingTxns, err = cbg.ReadCid(br); err != nil {
+		return xerrors.Errorf("failed to read cid field t.PendingTxns: %w", err)
+	}
+
+	if fields > 7 {
+		maj, extra, err := cbg.CborReadHeader(br)
+		if err != nil {
+			return err
+		}
+		if extra > cbg.MaxLength {
+			return fmt.Errorf("t.LockedTranches: array too large (%d)", extra)
+		}
+		if maj != cbg.MajArray {
+			return fmt.Errorf("expected cbor array")
+		}
+		if extra > 0 {
+			t.LockedTranches = make([]VestingTranche, extra)
+		}
+		for i := range t.LockedTranches {
+			if err := t.LockedTranches[i].UnmarshalCBOR(br); err != nil {
+				return xerrors.Errorf("unmarshaling t.LockedTranches: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Transaction: [To, Value, Method, Params, Approved, Expiration?]
+func (t *Transaction) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	fields := uint64(5)
+	if t.Expiration != NoExpiration {
+		fields++
+	}
+	if err := cbg.CborWriteHeader(w, cbg.MajArray, fields); err != nil {
+		return err
+	}
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+	if err := writeByteArray(w, t.Params); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if err := writeAddresses(w, t.Approved); err != nil {
+		return xerrors.Errorf("t.Approved: %w", err)
+	}
+	if t.Expiration != NoExpiration {
+		return writeInt64(w, int64(t.Expiration))
+	}
+	return nil
+}
+
+func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
+	*t = Transaction{}
+
+	br := cbg.GetPeeker(r)
+	fields, err := readTupleHeader(br, 5, 6)
+	if err != nil {
+		return err
+	}
+	if err := t.To.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.To: %w", err)
+	}
+	if err := t.Value.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.Value: %w", err)
+	}
+	method, err := readUint64(br)
+	if err != nil {
+		return xerrors.Errorf("t.Method: %w", err)
+	}
+	t.Method = abi.MethodNum(method)
+	if t.Params, err = readByteArray(br); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if t.Approved, err = readAddresses(br); err != nil {
+		return xerrors.Errorf("t.Approved: %w", err)
+	}
+	if fields > 5 {
+		expiration, err := readInt64(br)
+		if err != nil {
+			return xerrors.Errorf("t.Expiration: %w", err)
+		}
+		t.Expiration = abi.ChainEpoch(expiration)
+	}
+	return nil
+}
+
+// ProposalHashData: [Requester, To, Value, Method, Params, Expiration?]
+func (t *ProposalHashData) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	fields := uint64(5)
+	if t.Expiration != NoExpiration {
+		fields++
+	}
+	if err := cbg.CborWriteHeader(w, cbg.MajArray, fields); err != nil {
+		return err
+	}
+	if err := t.Requester.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+	if err := writeByteArray(w, t.Params); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if t.Expiration != NoExpiration {
+		return writeInt64(w, int64(t.Expiration))
+	}
+	return nil
+}
+
+func (t *ProposalHashData) UnmarshalCBOR(r io.Reader) error {
+	*t = ProposalHashData{}
+
+	br := cbg.GetPeeker(r)
+	fields, err := readTupleHeader(br, 5, 6)
+	if err != nil {
+		return err
+	}
+	if err := t.Requester.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.Requester: %w", err)
+	}
+	if err := t.To.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.To: %w", err)
+	}
+	if err := t.Value.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.Value: %w", err)
+	}
+	method, err := readUint64(br)
+	if err != nil {
+		return xerrors.Errorf("t.Method: %w", err)
+	}
+	t.Method = abi.MethodNum(method)
+	if t.Params, err = readByteArray(br); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if fields > 5 {
+		expiration, err := readInt64(br)
+		if err != nil {
+			return xerrors.Errorf("t.Expiration: %w", err)
+		}
+		t.Expiration = abi.ChainEpoch(expiration)
+	}
+	return nil
+}
+
+// ProposeParams: [To, Value, Method, Params, Expiration?]
+func (t *ProposeParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	fields := uint64(4)
+	if t.Expiration != NoExpiration {
+		fields++
+	}
+	if err := cbg.CborWriteHeader(w, cbg.MajArray, fields); err != nil {
+		return err
+	}
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := t.Value.MarshalCBOR(w); err != nil {
+		return err
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+		return err
+	}
+	if err := writeByteArray(w, t.Params); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if t.Expiration != NoExpiration {
+		return writeInt64(w, int64(t.Expiration))
+	}
+	return nil
+}
+
+func (t *ProposeParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ProposeParams{}
+
+	br := cbg.GetPeeker(r)
+	fields, err := readTupleHeader(br, 4, 5)
+	if err != nil {
+		return err
+	}
+	if err := t.To.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.To: %w", err)
+	}
+	if err := t.Value.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling t.Value: %w", err)
+	}
+	method, err := readUint64(br)
+	if err != nil {
+		return xerrors.Errorf("t.Method: %w", err)
+	}
+	t.Method = abi.MethodNum(method)
+	if t.Params, err = readByteArray(br); err != nil {
+		return xerrors.Errorf("t.Params: %w", err)
+	}
+	if fields > 4 {
+		expiration, err := readInt64(br)
+		if err != nil {
+			return xerrors.Errorf("t.Expiration: %w", err)
+		}
+		t.Expiration = abi.ChainEpoch(expiration)
+	}
+	return nil
+}
+
+//
+// Encoding helpers
+//
+
+// Reads the header of a tuple with between min and max fields, returning the number of fields.
+func readTupleHeader(br io.Reader, min, max uint64) (uint64, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajArray {
+		return 0, fmt.Errorf("cbor input should be of type array")
+	}
+	if extra < min || extra > max {
+		return 0, fmt.Errorf("cbor input had wrong number of fields: %d", extra)
+	}
+	return extra, nil
+}
+
+func writeInt64(w io.Writer, v int64) error {
+	if v >= 0 {
+		return cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, uint64(v))
+	}
+	return cbg.WriteMajorTypeHeader(w, cbg.MajNegativeInt, uint64(-v-1))
+}
+
+func readInt64(br io.Reader) (int64, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	v := int64(extra)
+	if v < 0 {
+		return 0, fmt.Errorf("int64 overflow")
+	}
+	switch maj {
+	case cbg.MajUnsignedInt:
+		return v, nil
+	case cbg.MajNegativeInt:
+		return -1 - v, nil
+	default:
+		return 0, fmt.Errorf("wrong type for int64 field: %d", maj)
+	}
+}
+
+func readUint64(br io.Reader) (uint64, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return 0, err
+	}
+	if maj != cbg.MajUnsignedInt {
+		return 0, fmt.Errorf("wrong type for uint64 field")
+	}
+	return extra, nil
+}
+
+func writeByteArray(w io.Writer, b []byte) error {
+	if len(b) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("byte array was too long")
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajByteString, uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readByteArray(br io.Reader) ([]byte, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if extra > cbg.ByteArrayMaxLen {
+		return nil, fmt.Errorf("byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return nil, fmt.Errorf("expected byte array")
+	}
+	if extra == 0 {
+		return nil, nil
+	}
+	b := make([]byte, extra)
+	if _, err := io.ReadFull(br, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func writeAddresses(w io.Writer, addrs []address.Address) error {
+	if len(addrs) > cbg.MaxLength {
+		return xerrors.Errorf("slice value was too long")
+	}
+	if err := cbg.WriteMajorTypeHeader(w, cbg.MajArray, uint64(len(addrs))); err != nil {
+		return err
+	}
+	for _, a := range addrs {
+		if err := a.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readAddresses(br io.Reader) ([]address.Address, error) {
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	if extra > cbg.MaxLength {
+		return nil, fmt.Errorf("array too large (%d)", extra)
+	}
+	if maj != cbg.MajArray {
+		return nil, fmt.Errorf("expected cbor array")
+	}
+	if extra == 0 {
+		return nil, nil
+	}
+	addrs := make([]address.Address, extra)
+	for i := range addrs {
+		if err := addrs[i].UnmarshalCBOR(br); err != nil {
+			return nil, err
+		}
+	}
+	return addrs, nil
+}
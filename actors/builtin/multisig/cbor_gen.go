@@ -15,52 +15,21 @@ import (
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{135}
+var lengthBufVestingTranche = []byte{131}
 
-func (t *State) MarshalCBOR(w io.Writer) error {
+func (t *VestingTranche) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufState); err != nil {
+	if _, err := w.Write(lengthBufVestingTranche); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Signers ([]address.Address) (slice)
-	if len(t.Signers) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Signers was too long")
-	}
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Signers))); err != nil {
-		return err
-	}
-	for _, v := range t.Signers {
-		if err := v.MarshalCBOR(w); err != nil {
-			return err
-		}
-	}
-
-	// t.NumApprovalsThreshold (uint64) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NumApprovalsThreshold)); err != nil {
-		return err
-	}
-
-	// t.NextTxnID (multisig.TxnID) (int64)
-	if t.NextTxnID >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NextTxnID)); err != nil {
-			return err
-		}
-	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.NextTxnID-1)); err != nil {
-			return err
-		}
-	}
-
-	// t.InitialBalance (big.Int) (struct)
-	if err := t.InitialBalance.MarshalCBOR(w); err != nil {
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
 		return err
 	}
 
@@ -85,18 +54,11 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 			return err
 		}
 	}
-
-	// t.PendingTxns (cid.Cid) (struct)
-
-	if err := cbg.WriteCidBuf(scratch, w, t.PendingTxns); err != nil {
-		return xerrors.Errorf("failed to write cid field t.PendingTxns: %w", err)
-	}
-
 	return nil
 }
 
-func (t *State) UnmarshalCBOR(r io.Reader) error {
-	*t = State{}
+func (t *VestingTranche) UnmarshalCBOR(r io.Reader) error {
+	*t = VestingTranche{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -109,84 +71,16 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 7 {
+	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Signers ([]address.Address) (slice)
-
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
-
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Signers: array too large (%d)", extra)
-	}
-
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
-	}
-
-	if extra > 0 {
-		t.Signers = make([]address.Address, extra)
-	}
-
-	for i := 0; i < int(extra); i++ {
-
-		var v address.Address
-		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
-		}
-
-		t.Signers[i] = v
-	}
-
-	// t.NumApprovalsThreshold (uint64) (uint64)
-
-	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
-		}
-		t.NumApprovalsThreshold = uint64(extra)
-
-	}
-	// t.NextTxnID (multisig.TxnID) (int64)
-	{
-		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
-		var extraI int64
-		if err != nil {
-			return err
-		}
-		switch maj {
-		case cbg.MajUnsignedInt:
-			extraI = int64(extra)
-			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
-			}
-		case cbg.MajNegativeInt:
-			extraI = int64(extra)
-			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
-			}
-			extraI = -1 - extraI
-		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
-		}
-
-		t.NextTxnID = TxnID(extraI)
-	}
-	// t.InitialBalance (big.Int) (struct)
+	// t.Amount (big.Int) (struct)
 
 	{
 
-		if err := t.InitialBalance.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.InitialBalance: %w", err)
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
 		}
 
 	}
@@ -240,81 +134,71 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		t.UnlockDuration = abi.ChainEpoch(extraI)
 	}
-	// t.PendingTxns (cid.Cid) (struct)
-
-	{
-
-		c, err := cbg.ReadCid(br)
-		if err != nil {
-			return xerrors.Errorf("failed to read cid field t.PendingTxns: %w", err)
-		}
-
-		t.PendingTxns = c
-
-	}
 	return nil
 }
 
-var lengthBufTransaction = []byte{133}
+var lengthBufConstructorParams = []byte{132}
 
-func (t *Transaction) MarshalCBOR(w io.Writer) error {
+func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufTransaction); err != nil {
+	if _, err := w.Write(lengthBufConstructorParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.To (address.Address) (struct)
-	if err := t.To.MarshalCBOR(w); err != nil {
-		return err
+	// t.Signers ([]address.Address) (slice)
+	if len(t.Signers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Signers was too long")
 	}
 
-	// t.Value (big.Int) (struct)
-	if err := t.Value.MarshalCBOR(w); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Signers))); err != nil {
 		return err
 	}
-
-	// t.Method (abi.MethodNum) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
-		return err
+	for _, v := range t.Signers {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
 	}
 
-	// t.Params ([]uint8) (slice)
-	if len(t.Params) > cbg.ByteArrayMaxLen {
-		return xerrors.Errorf("Byte array in field t.Params was too long")
+	// t.Weights ([]uint64) (slice)
+	if len(t.Weights) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Weights was too long")
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Weights))); err != nil {
 		return err
 	}
-
-	if _, err := w.Write(t.Params[:]); err != nil {
-		return err
+	for _, v := range t.Weights {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
 	}
 
-	// t.Approved ([]address.Address) (slice)
-	if len(t.Approved) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Approved was too long")
-	}
+	// t.NumApprovalsThreshold (uint64) (uint64)
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Approved))); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NumApprovalsThreshold)); err != nil {
 		return err
 	}
-	for _, v := range t.Approved {
-		if err := v.MarshalCBOR(w); err != nil {
+
+	// t.UnlockDuration (abi.ChainEpoch) (int64)
+	if t.UnlockDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.UnlockDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.UnlockDuration-1)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
-	*t = Transaction{}
+func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ConstructorParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -327,64 +211,40 @@ func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 5 {
+	if extra != 4 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.To (address.Address) (struct)
-
-	{
-
-		if err := t.To.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.To: %w", err)
-		}
-
-	}
-	// t.Value (big.Int) (struct)
-
-	{
-
-		if err := t.Value.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Value: %w", err)
-		}
-
-	}
-	// t.Method (abi.MethodNum) (uint64)
-
-	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
-		}
-		t.Method = abi.MethodNum(extra)
-
-	}
-	// t.Params ([]uint8) (slice)
+	// t.Signers ([]address.Address) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
 		return err
 	}
 
-	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.Params: byte array too large (%d)", extra)
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Signers: array too large (%d)", extra)
 	}
-	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
 	}
 
 	if extra > 0 {
-		t.Params = make([]uint8, extra)
+		t.Signers = make([]address.Address, extra)
 	}
 
-	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
-		return err
+	for i := 0; i < int(extra); i++ {
+
+		var v address.Address
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Signers[i] = v
 	}
-	// t.Approved ([]address.Address) (slice)
+
+	// t.Weights ([]uint64) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
@@ -392,7 +252,7 @@ func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Approved: array too large (%d)", extra)
+		return fmt.Errorf("t.Weights: array too large (%d)", extra)
 	}
 
 	if maj != cbg.MajArray {
@@ -400,73 +260,98 @@ func (t *Transaction) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if extra > 0 {
-		t.Approved = make([]address.Address, extra)
+		t.Weights = make([]uint64, extra)
 	}
 
 	for i := 0; i < int(extra); i++ {
 
-		var v address.Address
-		if err := v.UnmarshalCBOR(br); err != nil {
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.Weights slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.Weights was not a uint, instead got %d", maj)
+		}
+
+		t.Weights[i] = uint64(val)
+	}
+
+	// t.NumApprovalsThreshold (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
 			return err
 		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.NumApprovalsThreshold = uint64(extra)
 
-		t.Approved[i] = v
 	}
+	// t.UnlockDuration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
 
+		t.UnlockDuration = abi.ChainEpoch(extraI)
+	}
 	return nil
 }
 
-var lengthBufProposalHashData = []byte{133}
+var lengthBufAddSignerParams = []byte{131}
 
-func (t *ProposalHashData) MarshalCBOR(w io.Writer) error {
+func (t *AddSignerParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufProposalHashData); err != nil {
+	if _, err := w.Write(lengthBufAddSignerParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.Requester (address.Address) (struct)
-	if err := t.Requester.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.To (address.Address) (struct)
-	if err := t.To.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.Value (big.Int) (struct)
-	if err := t.Value.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.Method (abi.MethodNum) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
+	// t.Signer (address.Address) (struct)
+	if err := t.Signer.MarshalCBOR(w); err != nil {
 		return err
 	}
 
-	// t.Params ([]uint8) (slice)
-	if len(t.Params) > cbg.ByteArrayMaxLen {
-		return xerrors.Errorf("Byte array in field t.Params was too long")
-	}
+	// t.Weight (uint64) (uint64)
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Weight)); err != nil {
 		return err
 	}
 
-	if _, err := w.Write(t.Params[:]); err != nil {
+	// t.Increase (bool) (bool)
+	if err := cbg.WriteBool(w, t.Increase); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *ProposalHashData) UnmarshalCBOR(r io.Reader) error {
-	*t = ProposalHashData{}
+func (t *AddSignerParams) UnmarshalCBOR(r io.Reader) error {
+	*t = AddSignerParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -479,38 +364,20 @@ func (t *ProposalHashData) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 5 {
+	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Requester (address.Address) (struct)
+	// t.Signer (address.Address) (struct)
 
 	{
 
-		if err := t.Requester.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Requester: %w", err)
-		}
-
-	}
-	// t.To (address.Address) (struct)
-
-	{
-
-		if err := t.To.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.To: %w", err)
-		}
-
-	}
-	// t.Value (big.Int) (struct)
-
-	{
-
-		if err := t.Value.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Value: %w", err)
+		if err := t.Signer.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Signer: %w", err)
 		}
 
 	}
-	// t.Method (abi.MethodNum) (uint64)
+	// t.Weight (uint64) (uint64)
 
 	{
 
@@ -521,81 +388,54 @@ func (t *ProposalHashData) UnmarshalCBOR(r io.Reader) error {
 		if maj != cbg.MajUnsignedInt {
 			return fmt.Errorf("wrong type for uint64 field")
 		}
-		t.Method = abi.MethodNum(extra)
+		t.Weight = uint64(extra)
 
 	}
-	// t.Params ([]uint8) (slice)
+	// t.Increase (bool) (bool)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
 		return err
 	}
-
-	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.Params: byte array too large (%d)", extra)
-	}
-	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
-	}
-
-	if extra > 0 {
-		t.Params = make([]uint8, extra)
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
 	}
-
-	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
-		return err
+	switch extra {
+	case 20:
+		t.Increase = false
+	case 21:
+		t.Increase = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
 	}
 	return nil
 }
 
-var lengthBufConstructorParams = []byte{131}
+var lengthBufRemoveSignerParams = []byte{130}
 
-func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
+func (t *RemoveSignerParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufConstructorParams); err != nil {
+	if _, err := w.Write(lengthBufRemoveSignerParams); err != nil {
 		return err
 	}
 
-	scratch := make([]byte, 9)
-
-	// t.Signers ([]address.Address) (slice)
-	if len(t.Signers) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.Signers was too long")
-	}
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Signers))); err != nil {
+	// t.Signer (address.Address) (struct)
+	if err := t.Signer.MarshalCBOR(w); err != nil {
 		return err
 	}
-	for _, v := range t.Signers {
-		if err := v.MarshalCBOR(w); err != nil {
-			return err
-		}
-	}
-
-	// t.NumApprovalsThreshold (uint64) (uint64)
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NumApprovalsThreshold)); err != nil {
+	// t.Decrease (bool) (bool)
+	if err := cbg.WriteBool(w, t.Decrease); err != nil {
 		return err
 	}
-
-	// t.UnlockDuration (abi.ChainEpoch) (int64)
-	if t.UnlockDuration >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.UnlockDuration)); err != nil {
-			return err
-		}
-	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.UnlockDuration-1)); err != nil {
-			return err
-		}
-	}
 	return nil
 }
 
-func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
-	*t = ConstructorParams{}
+func (t *RemoveSignerParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RemoveSignerParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -608,127 +448,80 @@ func (t *ConstructorParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 3 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Signers ([]address.Address) (slice)
-
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
-
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.Signers: array too large (%d)", extra)
-	}
-
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
-	}
-
-	if extra > 0 {
-		t.Signers = make([]address.Address, extra)
-	}
-
-	for i := 0; i < int(extra); i++ {
-
-		var v address.Address
-		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
-		}
-
-		t.Signers[i] = v
-	}
-
-	// t.NumApprovalsThreshold (uint64) (uint64)
+	// t.Signer (address.Address) (struct)
 
 	{
 
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+		if err := t.Signer.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Signer: %w", err)
 		}
-		t.NumApprovalsThreshold = uint64(extra)
 
 	}
-	// t.UnlockDuration (abi.ChainEpoch) (int64)
-	{
-		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
-		var extraI int64
-		if err != nil {
-			return err
-		}
-		switch maj {
-		case cbg.MajUnsignedInt:
-			extraI = int64(extra)
-			if extraI < 0 {
-				return fmt.Errorf("int64 positive overflow")
-			}
-		case cbg.MajNegativeInt:
-			extraI = int64(extra)
-			if extraI < 0 {
-				return fmt.Errorf("int64 negative oveflow")
-			}
-			extraI = -1 - extraI
-		default:
-			return fmt.Errorf("wrong type for int64 field: %d", maj)
-		}
+	// t.Decrease (bool) (bool)
 
-		t.UnlockDuration = abi.ChainEpoch(extraI)
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajOther {
+		return fmt.Errorf("booleans must be major type 7")
+	}
+	switch extra {
+	case 20:
+		t.Decrease = false
+	case 21:
+		t.Decrease = true
+	default:
+		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
 	}
 	return nil
 }
 
-var lengthBufProposeParams = []byte{132}
+var lengthBufTxnIDParams = []byte{130}
 
-func (t *ProposeParams) MarshalCBOR(w io.Writer) error {
+func (t *TxnIDParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufProposeParams); err != nil {
+	if _, err := w.Write(lengthBufTxnIDParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.To (address.Address) (struct)
-	if err := t.To.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.Value (big.Int) (struct)
-	if err := t.Value.MarshalCBOR(w); err != nil {
-		return err
-	}
-
-	// t.Method (abi.MethodNum) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Method)); err != nil {
-		return err
+	// t.ID (multisig.TxnID) (int64)
+	if t.ID >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ID)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.ID-1)); err != nil {
+			return err
+		}
 	}
 
-	// t.Params ([]uint8) (slice)
-	if len(t.Params) > cbg.ByteArrayMaxLen {
-		return xerrors.Errorf("Byte array in field t.Params was too long")
+	// t.ProposalHash ([]uint8) (slice)
+	if len(t.ProposalHash) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.ProposalHash was too long")
 	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Params))); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.ProposalHash))); err != nil {
 		return err
 	}
 
-	if _, err := w.Write(t.Params[:]); err != nil {
+	if _, err := w.Write(t.ProposalHash[:]); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *ProposeParams) UnmarshalCBOR(r io.Reader) error {
-	*t = ProposeParams{}
+func (t *TxnIDParams) UnmarshalCBOR(r io.Reader) error {
+	*t = TxnIDParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -741,43 +534,36 @@ func (t *ProposeParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 4 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.To (address.Address) (struct)
-
-	{
-
-		if err := t.To.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.To: %w", err)
-		}
-
-	}
-	// t.Value (big.Int) (struct)
-
-	{
-
-		if err := t.Value.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Value: %w", err)
-		}
-
-	}
-	// t.Method (abi.MethodNum) (uint64)
-
+	// t.ID (multisig.TxnID) (int64)
 	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
 		if err != nil {
 			return err
 		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
 		}
-		t.Method = abi.MethodNum(extra)
 
+		t.ID = TxnID(extraI)
 	}
-	// t.Params ([]uint8) (slice)
+	// t.ProposalHash ([]uint8) (slice)
 
 	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
 	if err != nil {
@@ -785,47 +571,46 @@ func (t *ProposeParams) UnmarshalCBOR(r io.Reader) error {
 	}
 
 	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.Params: byte array too large (%d)", extra)
+		return fmt.Errorf("t.ProposalHash: byte array too large (%d)", extra)
 	}
 	if maj != cbg.MajByteString {
 		return fmt.Errorf("expected byte array")
 	}
 
 	if extra > 0 {
-		t.Params = make([]uint8, extra)
+		t.ProposalHash = make([]uint8, extra)
 	}
 
-	if _, err := io.ReadFull(br, t.Params[:]); err != nil {
+	if _, err := io.ReadFull(br, t.ProposalHash[:]); err != nil {
 		return err
 	}
 	return nil
 }
 
-var lengthBufAddSignerParams = []byte{130}
+var lengthBufChangeNumApprovalsThresholdParams = []byte{129}
 
-func (t *AddSignerParams) MarshalCBOR(w io.Writer) error {
+func (t *ChangeNumApprovalsThresholdParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufAddSignerParams); err != nil {
+	if _, err := w.Write(lengthBufChangeNumApprovalsThresholdParams); err != nil {
 		return err
 	}
 
-	// t.Signer (address.Address) (struct)
-	if err := t.Signer.MarshalCBOR(w); err != nil {
-		return err
-	}
+	scratch := make([]byte, 9)
 
-	// t.Increase (bool) (bool)
-	if err := cbg.WriteBool(w, t.Increase); err != nil {
+	// t.NewThreshold (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewThreshold)); err != nil {
 		return err
 	}
+
 	return nil
 }
 
-func (t *AddSignerParams) UnmarshalCBOR(r io.Reader) error {
-	*t = AddSignerParams{}
+func (t *ChangeNumApprovalsThresholdParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ChangeNumApprovalsThresholdParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -838,64 +623,61 @@ func (t *AddSignerParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 1 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.Signer (address.Address) (struct)
+	// t.NewThreshold (uint64) (uint64)
 
 	{
 
-		if err := t.Signer.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Signer: %w", err)
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
 		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.NewThreshold = uint64(extra)
 
 	}
-	// t.Increase (bool) (bool)
-
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
-	if maj != cbg.MajOther {
-		return fmt.Errorf("booleans must be major type 7")
-	}
-	switch extra {
-	case 20:
-		t.Increase = false
-	case 21:
-		t.Increase = true
-	default:
-		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
-	}
 	return nil
 }
 
-var lengthBufRemoveSignerParams = []byte{130}
+var lengthBufSwapSignerParams = []byte{131}
 
-func (t *RemoveSignerParams) MarshalCBOR(w io.Writer) error {
+func (t *SwapSignerParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufRemoveSignerParams); err != nil {
+	if _, err := w.Write(lengthBufSwapSignerParams); err != nil {
 		return err
 	}
 
-	// t.Signer (address.Address) (struct)
-	if err := t.Signer.MarshalCBOR(w); err != nil {
+	scratch := make([]byte, 9)
+
+	// t.From (address.Address) (struct)
+	if err := t.From.MarshalCBOR(w); err != nil {
 		return err
 	}
 
-	// t.Decrease (bool) (bool)
-	if err := cbg.WriteBool(w, t.Decrease); err != nil {
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.Weight (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Weight)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (t *RemoveSignerParams) UnmarshalCBOR(r io.Reader) error {
-	*t = RemoveSignerParams{}
+func (t *SwapSignerParams) UnmarshalCBOR(r io.Reader) error {
+	*t = SwapSignerParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -908,80 +690,89 @@ func (t *RemoveSignerParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.From (address.Address) (struct)
+
+	{
+
+		if err := t.From.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.From: %w", err)
+		}
+
 	}
-
-	// t.Signer (address.Address) (struct)
+	// t.To (address.Address) (struct)
 
 	{
 
-		if err := t.Signer.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.Signer: %w", err)
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.To: %w", err)
 		}
 
 	}
-	// t.Decrease (bool) (bool)
+	// t.Weight (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Weight = uint64(extra)
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
-	if maj != cbg.MajOther {
-		return fmt.Errorf("booleans must be major type 7")
-	}
-	switch extra {
-	case 20:
-		t.Decrease = false
-	case 21:
-		t.Decrease = true
-	default:
-		return fmt.Errorf("booleans are either major type 7, value 20 or 21 (got %d)", extra)
 	}
 	return nil
 }
 
-var lengthBufTxnIDParams = []byte{130}
+var lengthBufLockBalanceParams = []byte{131}
 
-func (t *TxnIDParams) MarshalCBOR(w io.Writer) error {
+func (t *LockBalanceParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufTxnIDParams); err != nil {
+	if _, err := w.Write(lengthBufLockBalanceParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.ID (multisig.TxnID) (int64)
-	if t.ID >= 0 {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ID)); err != nil {
+	// t.StartEpoch (abi.ChainEpoch) (int64)
+	if t.StartEpoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.StartEpoch)); err != nil {
 			return err
 		}
 	} else {
-		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.ID-1)); err != nil {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.StartEpoch-1)); err != nil {
 			return err
 		}
 	}
 
-	// t.ProposalHash ([]uint8) (slice)
-	if len(t.ProposalHash) > cbg.ByteArrayMaxLen {
-		return xerrors.Errorf("Byte array in field t.ProposalHash was too long")
-	}
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.ProposalHash))); err != nil {
-		return err
+	// t.UnlockDuration (abi.ChainEpoch) (int64)
+	if t.UnlockDuration >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.UnlockDuration)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.UnlockDuration-1)); err != nil {
+			return err
+		}
 	}
 
-	if _, err := w.Write(t.ProposalHash[:]); err != nil {
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (t *TxnIDParams) UnmarshalCBOR(r io.Reader) error {
-	*t = TxnIDParams{}
+func (t *LockBalanceParams) UnmarshalCBOR(r io.Reader) error {
+	*t = LockBalanceParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -994,11 +785,11 @@ func (t *TxnIDParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 2 {
+	if extra != 3 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.ID (multisig.TxnID) (int64)
+	// t.StartEpoch (abi.ChainEpoch) (int64)
 	{
 		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
 		var extraI int64
@@ -1021,56 +812,76 @@ func (t *TxnIDParams) UnmarshalCBOR(r io.Reader) error {
 			return fmt.Errorf("wrong type for int64 field: %d", maj)
 		}
 
-		t.ID = TxnID(extraI)
+		t.StartEpoch = abi.ChainEpoch(extraI)
 	}
-	// t.ProposalHash ([]uint8) (slice)
+	// t.UnlockDuration (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
 
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
+		t.UnlockDuration = abi.ChainEpoch(extraI)
 	}
+	// t.Amount (big.Int) (struct)
 
-	if extra > cbg.ByteArrayMaxLen {
-		return fmt.Errorf("t.ProposalHash: byte array too large (%d)", extra)
-	}
-	if maj != cbg.MajByteString {
-		return fmt.Errorf("expected byte array")
-	}
+	{
 
-	if extra > 0 {
-		t.ProposalHash = make([]uint8, extra)
-	}
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
+		}
 
-	if _, err := io.ReadFull(br, t.ProposalHash[:]); err != nil {
-		return err
 	}
 	return nil
 }
 
-var lengthBufChangeNumApprovalsThresholdParams = []byte{129}
+var lengthBufApproveManyParams = []byte{129}
 
-func (t *ChangeNumApprovalsThresholdParams) MarshalCBOR(w io.Writer) error {
+func (t *ApproveManyParams) MarshalCBOR(w io.Writer) error {
 	if t == nil {
 		_, err := w.Write(cbg.CborNull)
 		return err
 	}
-	if _, err := w.Write(lengthBufChangeNumApprovalsThresholdParams); err != nil {
+	if _, err := w.Write(lengthBufApproveManyParams); err != nil {
 		return err
 	}
 
 	scratch := make([]byte, 9)
 
-	// t.NewThreshold (uint64) (uint64)
+	// t.Approvals ([]multisig.TxnIDParams) (slice)
+	if len(t.Approvals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Approvals was too long")
+	}
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.NewThreshold)); err != nil {
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Approvals))); err != nil {
 		return err
 	}
-
+	for _, v := range t.Approvals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (t *ChangeNumApprovalsThresholdParams) UnmarshalCBOR(r io.Reader) error {
-	*t = ChangeNumApprovalsThresholdParams{}
+func (t *ApproveManyParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ApproveManyParams{}
 
 	br := cbg.GetPeeker(r)
 	scratch := make([]byte, 8)
@@ -1087,82 +898,35 @@ func (t *ChangeNumApprovalsThresholdParams) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.NewThreshold (uint64) (uint64)
-
-	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
-		}
-		t.NewThreshold = uint64(extra)
-
-	}
-	return nil
-}
-
-var lengthBufSwapSignerParams = []byte{130}
-
-func (t *SwapSignerParams) MarshalCBOR(w io.Writer) error {
-	if t == nil {
-		_, err := w.Write(cbg.CborNull)
-		return err
-	}
-	if _, err := w.Write(lengthBufSwapSignerParams); err != nil {
-		return err
-	}
+	// t.Approvals ([]multisig.TxnIDParams) (slice)
 
-	// t.From (address.Address) (struct)
-	if err := t.From.MarshalCBOR(w); err != nil {
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
 		return err
 	}
 
-	// t.To (address.Address) (struct)
-	if err := t.To.MarshalCBOR(w); err != nil {
-		return err
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Approvals: array too large (%d)", extra)
 	}
-	return nil
-}
-
-func (t *SwapSignerParams) UnmarshalCBOR(r io.Reader) error {
-	*t = SwapSignerParams{}
 
-	br := cbg.GetPeeker(r)
-	scratch := make([]byte, 8)
-
-	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
 	if maj != cbg.MajArray {
-		return fmt.Errorf("cbor input should be of type array")
+		return fmt.Errorf("expected cbor array")
 	}
 
-	if extra != 2 {
-		return fmt.Errorf("cbor input had wrong number of fields")
+	if extra > 0 {
+		t.Approvals = make([]TxnIDParams, extra)
 	}
 
-	// t.From (address.Address) (struct)
-
-	{
+	for i := 0; i < int(extra); i++ {
 
-		if err := t.From.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.From: %w", err)
+		var v TxnIDParams
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
 		}
 
+		t.Approvals[i] = v
 	}
-	// t.To (address.Address) (struct)
-
-	{
-
-		if err := t.To.UnmarshalCBOR(br); err != nil {
-			return xerrors.Errorf("unmarshaling t.To: %w", err)
-		}
 
-	}
 	return nil
 }
 
@@ -1457,3 +1221,82 @@ func (t *ProposeReturn) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufApproveManyReturn = []byte{129}
+
+func (t *ApproveManyReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufApproveManyReturn); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Results ([]multisig.ApproveReturn) (slice)
+	if len(t.Results) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Results was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Results))); err != nil {
+		return err
+	}
+	for _, v := range t.Results {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *ApproveManyReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = ApproveManyReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Results ([]multisig.ApproveReturn) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Results: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Results = make([]ApproveReturn, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ApproveReturn
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Results[i] = v
+	}
+
+	return nil
+}
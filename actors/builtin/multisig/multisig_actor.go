@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	addr "github.com/filecoin-project/go-address"
+	"golang.org/x/xerrors"
+
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
 	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
@@ -30,6 +32,9 @@ type Transaction struct {
 
 	// This address at index 0 is the transaction proposer, order of this slice must be preserved.
 	Approved []addr.Address
+
+	// The epoch from which the transaction may no longer be approved, or NoExpiration.
+	Expiration abi.ChainEpoch
 }
 
 // Data for a BLAKE2B-256 to be attached to methods referencing proposals via TXIDs.
@@ -39,11 +44,20 @@ type Transaction struct {
 // Requester - The requesting multisig wallet member.
 // All other fields - From the "Transaction" struct.
 type ProposalHashData struct {
-	Requester addr.Address
-	To        addr.Address
-	Value     abi.TokenAmount
-	Method    abi.MethodNum
-	Params    []byte
+	Requester  addr.Address
+	To         addr.Address
+	Value      abi.TokenAmount
+	Method     abi.MethodNum
+	Params     []byte
+	Expiration abi.ChainEpoch
+}
+
+// Expiration value for a transaction that remains approvable until executed or cancelled.
+const NoExpiration = abi.ChainEpoch(0)
+
+// Whether the transaction can no longer be approved at an epoch.
+func (t *Transaction) Expired(epoch abi.ChainEpoch) bool {
+	return t.Expiration != NoExpiration && epoch >= t.Expiration
 }
 
 type Actor struct{}
@@ -58,13 +72,20 @@ func (a Actor) Exports() []interface{} {
 		6:                         a.RemoveSigner,
 		7:                         a.SwapSigner,
 		8:                         a.ChangeNumApprovalsThreshold,
+		9:                         a.PruneExpired,
+		10:                        a.LockBalance,
+		11:                        a.ApproveMany,
 	}
 }
 
 var _ abi.Invokee = Actor{}
 
 type ConstructorParams struct {
-	Signers               []addr.Address
+	Signers []addr.Address
+	// Optional weights for each signer, in the same order as Signers.
+	// If empty, each signer has DefaultSignerWeight.
+	Weights []uint64
+	// The total weight of approvals required to execute a transaction.
 	NumApprovalsThreshold uint64
 	UnlockDuration        abi.ChainEpoch
 }
@@ -76,19 +97,36 @@ func (a Actor) Constructor(rt vmr.Runtime, params *ConstructorParams) *adt.Empty
 		rt.Abortf(exitcode.ErrIllegalArgument, "must have at least one signer")
 	}
 
+	if len(params.Weights) != 0 && len(params.Weights) != len(params.Signers) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "must give a weight for each of %d signers, got %d", len(params.Signers), len(params.Weights))
+	}
+
 	// do not allow duplicate signers
+	signers := make([]Signer, len(params.Signers))
 	resolvedSigners := make(map[addr.Address]struct{}, len(params.Signers))
-	for _, signer := range params.Signers {
+	for i, signer := range params.Signers {
 		resolved := resolve(rt.ResolveAddress, signer)
 		if _, ok := resolvedSigners[resolved]; ok {
 			rt.Abortf(exitcode.ErrIllegalArgument, "duplicate signer not allowed: %s", signer)
 		}
 		resolvedSigners[resolved] = struct{}{}
 
+		weight := DefaultSignerWeight
+		if len(params.Weights) != 0 {
+			weight = params.Weights[i]
+		}
+		if weight == 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "signer %s must have positive weight", signer)
+		}
+		signers[i] = Signer{Address: signer, Weight: weight}
 	}
 
-	if params.NumApprovalsThreshold > uint64(len(params.Signers)) {
-		rt.Abortf(exitcode.ErrIllegalArgument, "must not require more approvals than signers")
+	var st State
+	st.Signers = signers
+	totalWeight, err := st.TotalWeight()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signer weights")
+	if params.NumApprovalsThreshold > totalWeight {
+		rt.Abortf(exitcode.ErrIllegalArgument, "must not require more approval weight than total signer weight")
 	}
 
 	if params.NumApprovalsThreshold < 1 {
@@ -104,8 +142,6 @@ func (a Actor) Constructor(rt vmr.Runtime, params *ConstructorParams) *adt.Empty
 		rt.Abortf(exitcode.ErrIllegalState, "failed to create empty map: %v", err)
 	}
 
-	var st State
-	st.Signers = params.Signers
 	st.NumApprovalsThreshold = params.NumApprovalsThreshold
 	st.PendingTxns = pending
 	st.InitialBalance = abi.NewTokenAmount(0)
@@ -124,6 +160,9 @@ type ProposeParams struct {
 	Value  abi.TokenAmount
 	Method abi.MethodNum
 	Params []byte
+	// Optional epoch from which the transaction may no longer be approved.
+	// A zero value (NoExpiration) means the transaction does not expire.
+	Expiration abi.ChainEpoch
 }
 
 type ProposeReturn struct {
@@ -144,6 +183,9 @@ func (a Actor) Propose(rt vmr.Runtime, params *ProposeParams) *ProposeReturn {
 	if params.Value.Sign() < 0 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "proposed value must be non-negative, was %v", params.Value)
 	}
+	if params.Expiration != NoExpiration && params.Expiration <= rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "proposed expiration %d must be after current epoch %d", params.Expiration, rt.CurrEpoch())
+	}
 
 	var txnID TxnID
 	var st State
@@ -159,11 +201,12 @@ func (a Actor) Propose(rt vmr.Runtime, params *ProposeParams) *ProposeReturn {
 		txnID = st.NextTxnID
 		st.NextTxnID += 1
 		txn = &Transaction{
-			To:       params.To,
-			Value:    params.Value,
-			Method:   params.Method,
-			Params:   params.Params,
-			Approved: []addr.Address{},
+			To:         params.To,
+			Value:      params.Value,
+			Method:     params.Method,
+			Params:     params.Params,
+			Expiration: params.Expiration,
+			Approved:   []addr.Address{},
 		}
 
 		if err := ptx.Put(txnID, txn); err != nil {
@@ -174,7 +217,8 @@ func (a Actor) Propose(rt vmr.Runtime, params *ProposeParams) *ProposeReturn {
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush pending transactions")
 	})
 
-	applied, ret, code := a.approveTransaction(rt, txnID, txn)
+	applied, ret, code, err := a.approveTransaction(rt, txnID, txn)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to approve transaction %v", txnID)
 
 	// Note: this transaction ID may not be stable across chain re-orgs.
 	// The proposal hash may be provided as a stability check when approving.
@@ -196,41 +240,97 @@ type TxnIDParams struct {
 type ApproveReturn struct {
 	// Applied indicates if the transaction was applied as opposed to proposed but not applied due to lack of approvals
 	Applied bool
-	// Code is the exitcode of the transaction, if Applied is false this field should be ignored.
+	// Code is the exitcode of the transaction, if Applied is false this field should be ignored
+	// (except in the results of ApproveMany, where it is the reason the approval was rejected).
 	Code exitcode.ExitCode
 	// Ret is the return vale of the transaction, if Applied is false this field should be ignored.
 	Ret []byte
 }
 
 func (a Actor) Approve(rt vmr.Runtime, params *TxnIDParams) *ApproveReturn {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	ret, err := a.approve(rt, params)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to approve transaction %v", params.ID)
+	return ret
+}
+
+type ApproveManyParams struct {
+	Approvals []TxnIDParams
+}
+
+type ApproveManyReturn struct {
+	// The result of each approval, in the order given.
+	Results []ApproveReturn
+}
+
+// Approves a number of transactions in order, executing each that reaches the approval threshold.
+// An approval that fails does not abort the call or revert the other approvals. Instead its result
+// is not applied and has a non-zero exit code indicating why the approval was rejected.
+func (a Actor) ApproveMany(rt vmr.Runtime, params *ApproveManyParams) *ApproveManyReturn {
 	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
 	callerAddr := rt.Message().Caller()
+
 	var st State
-	var txn *Transaction
-	rt.State().Transaction(&st, func() {
-		if !isSigner(rt.ResolveAddress, &st, callerAddr) {
-			rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", callerAddr)
+	rt.State().Readonly(&st)
+	if !isSigner(rt.ResolveAddress, &st, callerAddr) {
+		rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", callerAddr)
+	}
+
+	results := make([]ApproveReturn, len(params.Approvals))
+	for i := range params.Approvals {
+		// each approval is checked against the state left by those before it, which may have removed the caller
+		// as a signer.
+		ret, err := a.approve(rt, &params.Approvals[i])
+		if err != nil {
+			results[i] = ApproveReturn{Code: exitcode.Unwrap(err, exitcode.ErrIllegalState)}
+			rt.Log(vmr.INFO, "approval of transaction %d rejected: %v", params.Approvals[i].ID, err)
+			continue
 		}
+		results[i] = *ret
+	}
+	return &ApproveManyReturn{Results: results}
+}
 
-		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
+// Approves a transaction on behalf of the caller, executing it if its approvals meet the threshold.
+// Returns an error carrying the exit code with which to reject the approval if it is invalid, in which case
+// no state is changed.
+func (a Actor) approve(rt vmr.Runtime, params *TxnIDParams) (*ApproveReturn, error) {
+	callerAddr := rt.Message().Caller()
+	var st State
+	rt.State().Readonly(&st)
+	if !isSigner(rt.ResolveAddress, &st, callerAddr) {
+		return nil, exitcode.ErrForbidden.Wrapf("%s is not a signer", callerAddr)
+	}
 
-		txn = getTransaction(rt, ptx, params.ID, params.ProposalHash, true)
-	})
+	ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
+
+	txn, err := getTransaction(rt, ptx, params.ID, params.ProposalHash, true)
+	if err != nil {
+		return nil, err
+	}
+	if txn.Expired(rt.CurrEpoch()) {
+		return nil, exitcode.ErrForbidden.Wrapf("transaction %d expired at epoch %d", params.ID, txn.Expiration)
+	}
 
 	// if the transaction already has enough approvers, execute it without "processing" this approval.
-	approved, ret, code := executeTransactionIfApproved(rt, st, params.ID, txn)
-	if !approved {
-		// if the transaction hasn't already been approved, let's "process" this approval
-		// and see if we can execute the transaction
-		approved, ret, code = a.approveTransaction(rt, params.ID, txn)
+	thresholdMet, err := st.thresholdMet(rt.ResolveAddress, txn.Approved)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute approval weight for %v", params.ID)
+	if thresholdMet {
+		if err := st.assertAvailable(rt.CurrentBalance(), txn.Value, rt.CurrEpoch()); err != nil {
+			return nil, exitcode.ErrInsufficientFunds.Wrapf("insufficient funds unlocked: %v", err)
+		}
+		applied, ret, code := executeTransactionIfApproved(rt, st, params.ID, txn)
+		return &ApproveReturn{Applied: applied, Code: code, Ret: ret}, nil
 	}
 
-	return &ApproveReturn{
-		Applied: approved,
-		Code:    code,
-		Ret:     ret,
+	// if the transaction hasn't already been approved, let's "process" this approval
+	// and see if we can execute the transaction
+	applied, ret, code, err := a.approveTransaction(rt, params.ID, txn)
+	if err != nil {
+		return nil, err
 	}
+	return &ApproveReturn{Applied: applied, Code: code, Ret: ret}, nil
 }
 
 func (a Actor) Cancel(rt vmr.Runtime, params *TxnIDParams) *adt.EmptyValue {
@@ -271,8 +371,51 @@ func (a Actor) Cancel(rt vmr.Runtime, params *TxnIDParams) *adt.EmptyValue {
 	return nil
 }
 
+// Removes all pending transactions that have expired.
+// Any signer may prune expired transactions, since they can no longer be approved.
+func (a Actor) PruneExpired(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	callerAddr := rt.Message().Caller()
+
+	var st State
+	rt.State().Transaction(&st, func() {
+		if !isSigner(rt.ResolveAddress, &st, callerAddr) {
+			rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", callerAddr)
+		}
+
+		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
+
+		var expired []TxnID
+		var txn Transaction
+		err = ptx.ForEach(&txn, func(k string) error {
+			if !txn.Expired(rt.CurrEpoch()) {
+				return nil
+			}
+			id, err := adt.ParseIntKey(k)
+			if err != nil {
+				return err
+			}
+			expired = append(expired, TxnID(id))
+			return nil
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate pending transactions")
+
+		for _, id := range expired {
+			err = ptx.Delete(id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete expired transaction %v", id)
+		}
+
+		st.PendingTxns, err = ptx.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush pending transactions")
+	})
+	return nil
+}
+
 type AddSignerParams struct {
-	Signer   addr.Address
+	Signer addr.Address
+	Weight uint64
+	// Whether to increase the threshold by the new signer's weight.
 	Increase bool
 }
 
@@ -285,16 +428,23 @@ func (a Actor) AddSigner(rt vmr.Runtime, params *AddSignerParams) *adt.EmptyValu
 		if isSigner(rt.ResolveAddress, &st, params.Signer) {
 			rt.Abortf(exitcode.ErrIllegalArgument, "%s is already a signer", params.Signer)
 		}
-		st.Signers = append(st.Signers, params.Signer)
+		if params.Weight == 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "signer %s must have positive weight", params.Signer)
+		}
+		st.Signers = append(st.Signers, Signer{Address: params.Signer, Weight: params.Weight})
+		_, err := st.TotalWeight()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid weight for signer %s", params.Signer)
 		if params.Increase {
-			st.NumApprovalsThreshold = st.NumApprovalsThreshold + 1
+			st.NumApprovalsThreshold, err = addWeight(st.NumApprovalsThreshold, params.Weight)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to increase threshold by weight of signer %s", params.Signer)
 		}
 	})
 	return nil
 }
 
 type RemoveSignerParams struct {
-	Signer   addr.Address
+	Signer addr.Address
+	// Whether to decrease the threshold by the removed signer's weight.
 	Decrease bool
 }
 
@@ -304,7 +454,8 @@ func (a Actor) RemoveSigner(rt vmr.Runtime, params *RemoveSignerParams) *adt.Emp
 
 	var st State
 	rt.State().Transaction(&st, func() {
-		if !isSigner(rt.ResolveAddress, &st, params.Signer) {
+		idx := signerIndex(rt.ResolveAddress, &st, params.Signer)
+		if idx < 0 {
 			rt.Abortf(exitcode.ErrNotFound, "%s is not a signer", params.Signer)
 		}
 
@@ -312,23 +463,30 @@ func (a Actor) RemoveSigner(rt vmr.Runtime, params *RemoveSignerParams) *adt.Emp
 			rt.Abortf(exitcode.ErrForbidden, "cannot remove only signer")
 		}
 
-		newSigners := make([]addr.Address, 0, len(st.Signers))
-		for _, s := range st.Signers {
-			if !isAddressEqual(rt.ResolveAddress, s, params.Signer) {
-				newSigners = append(newSigners, s)
-			}
-		}
+		removedWeight := st.Signers[idx].Weight
+		totalWeight, err := st.TotalWeight()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute total signer weight")
+		remainingWeight := totalWeight - removedWeight
 
-		// if the number of signers is below the threshold after removing the given signer,
-		// we should decrease the threshold by 1. This means that decrease should NOT be set to false
+		// if the total weight of signers is below the threshold after removing the given signer,
+		// we should decrease the threshold by its weight. This means that decrease should NOT be set to false
 		// in such a scenario.
-		if !params.Decrease && uint64(len(st.Signers)-1) < st.NumApprovalsThreshold {
-			rt.Abortf(exitcode.ErrIllegalArgument, "can't reduce signers to %d below threshold %d with decrease=false", len(st.Signers)-1, st.NumApprovalsThreshold)
+		if !params.Decrease && remainingWeight < st.NumApprovalsThreshold {
+			rt.Abortf(exitcode.ErrIllegalArgument, "can't reduce signer weight to %d below threshold %d with decrease=false", remainingWeight, st.NumApprovalsThreshold)
 		}
 
+		// The threshold is decreased by the removed signer's weight, to no lower than zero.
 		if params.Decrease {
-			st.NumApprovalsThreshold = st.NumApprovalsThreshold - 1
+			if removedWeight >= st.NumApprovalsThreshold {
+				st.NumApprovalsThreshold = 0
+			} else {
+				st.NumApprovalsThreshold = st.NumApprovalsThreshold - removedWeight
+			}
 		}
+
+		newSigners := make([]Signer, 0, len(st.Signers)-1)
+		newSigners = append(newSigners, st.Signers[:idx]...)
+		newSigners = append(newSigners, st.Signers[idx+1:]...)
 		st.Signers = newSigners
 	})
 
@@ -338,6 +496,8 @@ func (a Actor) RemoveSigner(rt vmr.Runtime, params *RemoveSignerParams) *adt.Emp
 type SwapSignerParams struct {
 	From addr.Address
 	To   addr.Address
+	// The weight of the new signer. If zero, the new signer takes the weight of the signer it replaces.
+	Weight uint64
 }
 
 func (a Actor) SwapSigner(rt vmr.Runtime, params *SwapSignerParams) *adt.EmptyValue {
@@ -346,7 +506,8 @@ func (a Actor) SwapSigner(rt vmr.Runtime, params *SwapSignerParams) *adt.EmptyVa
 
 	var st State
 	rt.State().Transaction(&st, func() {
-		if !isSigner(rt.ResolveAddress, &st, params.From) {
+		idx := signerIndex(rt.ResolveAddress, &st, params.From)
+		if idx < 0 {
 			rt.Abortf(exitcode.ErrNotFound, "%s is not a signer", params.From)
 		}
 
@@ -354,20 +515,29 @@ func (a Actor) SwapSigner(rt vmr.Runtime, params *SwapSignerParams) *adt.EmptyVa
 			rt.Abortf(exitcode.ErrIllegalArgument, "%s already a signer", params.To)
 		}
 
-		newSigners := make([]addr.Address, 0, len(st.Signers))
-		for _, s := range st.Signers {
-			if !isAddressEqual(rt.ResolveAddress, s, params.From) {
-				newSigners = append(newSigners, s)
-			}
+		weight := params.Weight
+		if weight == 0 {
+			weight = st.Signers[idx].Weight
 		}
-		newSigners = append(newSigners, params.To)
+
+		newSigners := make([]Signer, 0, len(st.Signers))
+		newSigners = append(newSigners, st.Signers[:idx]...)
+		newSigners = append(newSigners, st.Signers[idx+1:]...)
+		newSigners = append(newSigners, Signer{Address: params.To, Weight: weight})
 		st.Signers = newSigners
+
+		totalWeight, err := st.TotalWeight()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid weight for signer %s", params.To)
+		if totalWeight < st.NumApprovalsThreshold {
+			rt.Abortf(exitcode.ErrIllegalArgument, "can't reduce signer weight to %d below threshold %d", totalWeight, st.NumApprovalsThreshold)
+		}
 	})
 
 	return nil
 }
 
 type ChangeNumApprovalsThresholdParams struct {
+	// The total weight of approvals required to execute a transaction.
 	NewThreshold uint64
 }
 
@@ -377,7 +547,9 @@ func (a Actor) ChangeNumApprovalsThreshold(rt vmr.Runtime, params *ChangeNumAppr
 
 	var st State
 	rt.State().Transaction(&st, func() {
-		if params.NewThreshold == 0 || params.NewThreshold > uint64(len(st.Signers)) {
+		totalWeight, err := st.TotalWeight()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute total signer weight")
+		if params.NewThreshold == 0 || params.NewThreshold > totalWeight {
 			rt.Abortf(exitcode.ErrIllegalArgument, "New threshold value not supported")
 		}
 
@@ -386,52 +558,102 @@ func (a Actor) ChangeNumApprovalsThreshold(rt vmr.Runtime, params *ChangeNumAppr
 	return nil
 }
 
-func (a Actor) approveTransaction(rt vmr.Runtime, txnID TxnID, txn *Transaction) (bool, []byte, exitcode.ExitCode) {
+type LockBalanceParams struct {
+	StartEpoch     abi.ChainEpoch
+	UnlockDuration abi.ChainEpoch
+	Amount         abi.TokenAmount
+}
+
+// Locks an amount of the wallet's balance, to unlock linearly over UnlockDuration epochs from StartEpoch.
+// If the wallet has no vesting schedule this sets one, otherwise the amount is locked in addition to
+// any existing schedule.
+func (a Actor) LockBalance(rt vmr.Runtime, params *LockBalanceParams) *adt.EmptyValue {
+	// Can only be called by the multisig wallet itself.
+	rt.ValidateImmediateCallerIs(rt.Message().Receiver())
+
+	if params.UnlockDuration <= 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "unlock duration must be positive, was %d", params.UnlockDuration)
+	}
+	if params.Amount.Sign() < 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "amount to lock must be non-negative, was %v", params.Amount)
+	}
+
 	var st State
-	// abort duplicate approval
+	rt.State().Transaction(&st, func() {
+		err := st.addLockedBalance(rt.CurrEpoch(), params.StartEpoch, params.UnlockDuration, params.Amount)
+		builtin.RequireNoErr(rt, err, exitcode.ErrForbidden, "failed to lock balance")
+	})
+	return nil
+}
+
+// Records the caller's approval of a transaction and executes the transaction if its approvals meet the threshold.
+// Returns an error carrying the exit code with which to reject the approval if the caller has already approved
+// the transaction, or if the approval meets the threshold but the transaction can't be executed for lack of funds.
+// No approval is recorded in that case.
+func (a Actor) approveTransaction(rt vmr.Runtime, txnID TxnID, txn *Transaction) (bool, []byte, exitcode.ExitCode, error) {
+	callerAddr := rt.Message().Caller()
+	// reject duplicate approval
 	for _, previousApprover := range txn.Approved {
-		if previousApprover == rt.Message().Caller() {
-			rt.Abortf(exitcode.ErrForbidden, "%s already approved this message", previousApprover)
+		if previousApprover == callerAddr {
+			return false, nil, exitcode.Ok, exitcode.ErrForbidden.Wrapf("%s already approved this message", previousApprover)
 		}
 	}
 
+	var st State
+	var rejected error
 	// add the caller to the list of approvers
 	rt.State().Transaction(&st, func() {
 		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
 
+		approved := make([]addr.Address, 0, len(txn.Approved)+1)
+		approved = append(append(approved, txn.Approved...), callerAddr)
+		thresholdMet, err := st.thresholdMet(rt.ResolveAddress, approved)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute approval weight for %v", txnID)
+		if thresholdMet {
+			if err := st.assertAvailable(rt.CurrentBalance(), txn.Value, rt.CurrEpoch()); err != nil {
+				rejected = exitcode.ErrInsufficientFunds.Wrapf("insufficient funds unlocked: %v", err)
+				return
+			}
+		}
+
 		// update approved on the transaction
-		txn.Approved = append(txn.Approved, rt.Message().Caller())
-		 err = ptx.Put(txnID, txn)
-		 builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put transaction %v for approval", txnID)
+		txn.Approved = approved
+		err = ptx.Put(txnID, txn)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put transaction %v for approval", txnID)
 
 		st.PendingTxns, err = ptx.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush pending transactions")
 	})
+	if rejected != nil {
+		return false, nil, exitcode.Ok, rejected
+	}
 
-	return executeTransactionIfApproved(rt, st, txnID, txn)
+	applied, ret, code := executeTransactionIfApproved(rt, st, txnID, txn)
+	return applied, ret, code, nil
 }
 
-func getTransaction(rt vmr.Runtime, ptx *adt.Map, txnID TxnID, proposalHash []byte, checkHash bool) *Transaction {
-	var txn Transaction
-
+// Loads a pending transaction, checking it matches the proposal hash if given.
+// Returns an error carrying the exit code with which to reject the approval if the transaction is not found or
+// doesn't match.
+func getTransaction(rt vmr.Runtime, ptx *adt.Map, txnID TxnID, proposalHash []byte, checkHash bool) (*Transaction, error) {
 	// get transaction from the state trie
-	var err error
-	txn, err = getPendingTransaction(ptx, txnID)
-	if err != nil {
-		rt.Abortf(exitcode.ErrNotFound, "failed to get transaction for approval: %v", err)
+	txn, err := getPendingTransaction(ptx, txnID)
+	if xerrors.Is(err, exitcode.ErrNotFound) {
+		return nil, err
 	}
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get transaction %v for approval", txnID)
 
 	// confirm the hashes match
 	if checkHash {
 		calculatedHash, err := ComputeProposalHash(&txn, rt.Syscalls().HashBlake2b)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute proposal hash for %v", txnID)
 		if proposalHash != nil && !bytes.Equal(proposalHash, calculatedHash[:]) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "hash does not match proposal params (ensure requester is an ID address)")
+			return nil, exitcode.ErrIllegalArgument.Wrapf("hash does not match proposal params (ensure requester is an ID address)")
 		}
 	}
 
-	return &txn
+	return &txn, nil
 }
 
 func executeTransactionIfApproved(rt vmr.Runtime, st State, txnID TxnID, txn *Transaction) (bool, []byte, exitcode.ExitCode) {
@@ -439,7 +661,8 @@ func executeTransactionIfApproved(rt vmr.Runtime, st State, txnID TxnID, txn *Tr
 	var code exitcode.ExitCode
 	applied := false
 
-	thresholdMet := uint64(len(txn.Approved)) >= st.NumApprovalsThreshold
+	thresholdMet, err := st.thresholdMet(rt.ResolveAddress, txn.Approved)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute approval weight for %v", txnID)
 	if thresholdMet {
 		if err := st.assertAvailable(rt.CurrentBalance(), txn.Value, rt.CurrEpoch()); err != nil {
 			rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds unlocked: %v", err)
@@ -479,21 +702,22 @@ func executeTransactionIfApproved(rt vmr.Runtime, st State, txnID TxnID, txn *Tr
 
 type AddressResolveFunc func(address addr.Address) (resolved addr.Address, found bool)
 
-func isAddressEqual(resolveFunc AddressResolveFunc, addr1, addr2 addr.Address) bool {
-	return resolve(resolveFunc, addr1) == resolve(resolveFunc, addr2)
+func isSigner(resolveFunc AddressResolveFunc, st *State, address addr.Address) bool {
+	return signerIndex(resolveFunc, st, address) >= 0
 }
 
-func isSigner(resolveFunc AddressResolveFunc, st *State, address addr.Address) bool {
+// Returns the index of a signer in the state's signers, or -1 if the address is not a signer.
+func signerIndex(resolveFunc AddressResolveFunc, st *State, address addr.Address) int {
 	candidateResolved := resolve(resolveFunc, address)
 
-	for _, ap := range st.Signers {
-		signerResolved := resolve(resolveFunc, ap)
+	for i, s := range st.Signers {
+		signerResolved := resolve(resolveFunc, s.Address)
 		if signerResolved == candidateResolved {
-			return true
+			return i
 		}
 	}
 
-	return false
+	return -1
 }
 
 func resolve(resolveFunc AddressResolveFunc, address addr.Address) addr.Address {
@@ -511,11 +735,12 @@ func resolve(resolveFunc AddressResolveFunc, address addr.Address) addr.Address
 // associated with an ID, which might change under chain re-orgs.
 func ComputeProposalHash(txn *Transaction, hash func([]byte) [32]byte) ([]byte, error) {
 	hashData := ProposalHashData{
-		Requester: txn.Approved[0],
-		To:        txn.To,
-		Value:     txn.Value,
-		Method:    txn.Method,
-		Params:    txn.Params,
+		Requester:  txn.Approved[0],
+		To:         txn.To,
+		Value:      txn.Value,
+		Method:     txn.Method,
+		Params:     txn.Params,
+		Expiration: txn.Expiration,
 	}
 
 	data, err := hashData.Serialize()
@@ -1,6 +1,8 @@
 package multisig
 
 import (
+	"math"
+
 	address "github.com/filecoin-project/go-address"
 	cid "github.com/ipfs/go-cid"
 	"golang.org/x/xerrors"
@@ -16,7 +18,8 @@ type State struct {
 	// for a public key that has not yet received a message on chain.
 	// If any signer address is a public-key address, it will be resolved to an ID address and persisted
 	// in this state when the address is used.
-	Signers               []address.Address
+	Signers []Signer
+	// The total weight of signer approvals required to execute a transaction.
 	NumApprovalsThreshold uint64
 	NextTxnID             TxnID
 
@@ -26,15 +29,93 @@ type State struct {
 	UnlockDuration abi.ChainEpoch
 
 	PendingTxns cid.Cid
+
+	// Further linear unlock schedules added after construction with LockBalance.
+	LockedTranches []VestingTranche
+}
+
+// The maximum number of unvested tranches a wallet may hold in addition to its initial schedule.
+// This bounds the cost of computing the locked balance and the size of the state.
+const MaxVestingTranches = 64
+
+// A quantity of funds that unlocks linearly over a period, independently of other tranches.
+type VestingTranche struct {
+	Amount         abi.TokenAmount
+	StartEpoch     abi.ChainEpoch
+	UnlockDuration abi.ChainEpoch
+}
+
+func (vt *VestingTranche) amountLocked(currEpoch abi.ChainEpoch) abi.TokenAmount {
+	return linearLocked(vt.Amount, vt.UnlockDuration, currEpoch-vt.StartEpoch)
+}
+
+// Whether the tranche has entirely unlocked by an epoch.
+func (vt *VestingTranche) vested(currEpoch abi.ChainEpoch) bool {
+	return currEpoch-vt.StartEpoch >= vt.UnlockDuration
 }
 
+// The amount of the initial balance still locked, elapsedEpoch epochs after the start epoch.
 func (st *State) AmountLocked(elapsedEpoch abi.ChainEpoch) abi.TokenAmount {
-	if elapsedEpoch >= st.UnlockDuration {
+	return linearLocked(st.InitialBalance, st.UnlockDuration, elapsedEpoch)
+}
+
+// The total amount locked at an epoch, under the initial schedule and all subsequently added tranches.
+func (st *State) LockedBalance(currEpoch abi.ChainEpoch) abi.TokenAmount {
+	locked := st.AmountLocked(currEpoch - st.StartEpoch)
+	for _, tranche := range st.LockedTranches {
+		locked = big.Add(locked, tranche.amountLocked(currEpoch))
+	}
+	return locked
+}
+
+// Adds a linear unlock schedule. The first schedule to be set occupies the initial balance fields,
+// and later schedules are recorded as separate tranches.
+// Tranches that have fully vested by the current epoch are dropped first.
+func (st *State) addLockedBalance(currEpoch, startEpoch, unlockDuration abi.ChainEpoch, amount abi.TokenAmount) error {
+	if st.UnlockDuration == 0 {
+		st.InitialBalance = amount
+		st.StartEpoch = startEpoch
+		st.UnlockDuration = unlockDuration
+		return nil
+	}
+
+	st.pruneVestedTranches(currEpoch)
+	if len(st.LockedTranches) >= MaxVestingTranches {
+		return xerrors.Errorf("cannot add tranche: %d unvested tranches already held", len(st.LockedTranches))
+	}
+	st.LockedTranches = append(st.LockedTranches, VestingTranche{
+		Amount:         amount,
+		StartEpoch:     startEpoch,
+		UnlockDuration: unlockDuration,
+	})
+	return nil
+}
+
+// Removes the tranches that have fully vested by an epoch, preserving the order of the remainder.
+func (st *State) pruneVestedTranches(currEpoch abi.ChainEpoch) {
+	remaining := st.LockedTranches[:0]
+	for _, tranche := range st.LockedTranches {
+		if !tranche.vested(currEpoch) {
+			remaining = append(remaining, tranche)
+		}
+	}
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	st.LockedTranches = remaining
+}
+
+func linearLocked(amount abi.TokenAmount, unlockDuration, elapsedEpoch abi.ChainEpoch) abi.TokenAmount {
+	if elapsedEpoch >= unlockDuration {
 		return abi.NewTokenAmount(0)
 	}
+	// Nothing has unlocked before the schedule starts.
+	if elapsedEpoch < 0 {
+		return amount
+	}
 
-	unitLocked := big.Div(st.InitialBalance, big.NewInt(int64(st.UnlockDuration)))
-	return big.Mul(unitLocked, big.Sub(big.NewInt(int64(st.UnlockDuration)), big.NewInt(int64(elapsedEpoch))))
+	unitLocked := big.Div(amount, big.NewInt(int64(unlockDuration)))
+	return big.Mul(unitLocked, big.Sub(big.NewInt(int64(unlockDuration)), big.NewInt(int64(elapsedEpoch))))
 }
 
 // return nil if MultiSig maintains required locked balance after spending the amount, else return an error.
@@ -47,7 +128,7 @@ func (st *State) assertAvailable(currBalance abi.TokenAmount, amountToSpend abi.
 	}
 
 	remainingBalance := big.Sub(currBalance, amountToSpend)
-	amountLocked := st.AmountLocked(currEpoch - st.StartEpoch)
+	amountLocked := st.LockedBalance(currEpoch)
 	if remainingBalance.LessThan(amountLocked) {
 		return xerrors.Errorf("actor balance if spent %s would be less than required locked amount %s", remainingBalance.String(), amountLocked.String())
 	}
@@ -55,6 +136,63 @@ func (st *State) assertAvailable(currBalance abi.TokenAmount, amountToSpend abi.
 	return nil
 }
 
+// The addresses of all signers, in order.
+func (st *State) SignerAddresses() []address.Address {
+	addrs := make([]address.Address, len(st.Signers))
+	for i, s := range st.Signers {
+		addrs[i] = s.Address
+	}
+	return addrs
+}
+
+// The sum of all signers' weights.
+// Returns an error if the sum overflows.
+func (st *State) TotalWeight() (uint64, error) {
+	total := uint64(0)
+	for _, s := range st.Signers {
+		var err error
+		if total, err = addWeight(total, s.Weight); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// The total weight of a transaction's approvers.
+// An approval from an address that is no longer a signer carries DefaultSignerWeight, as every approval counted
+// before signers were weighted, so that transactions pending when a signer was removed remain executable.
+// Returns an error if the sum overflows.
+func (st *State) approvedWeight(resolveFunc AddressResolveFunc, approved []address.Address) (uint64, error) {
+	total := uint64(0)
+	for _, a := range approved {
+		weight := DefaultSignerWeight
+		if i := signerIndex(resolveFunc, st, a); i >= 0 {
+			weight = st.Signers[i].Weight
+		}
+		var err error
+		if total, err = addWeight(total, weight); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// Whether the weight of a transaction's approvers meets the approval threshold.
+func (st *State) thresholdMet(resolveFunc AddressResolveFunc, approved []address.Address) (bool, error) {
+	weight, err := st.approvedWeight(resolveFunc, approved)
+	if err != nil {
+		return false, err
+	}
+	return weight >= st.NumApprovalsThreshold, nil
+}
+
+func addWeight(a, b uint64) (uint64, error) {
+	if a > math.MaxUint64-b {
+		return 0, xerrors.Errorf("signer weight %d + %d overflows", a, b)
+	}
+	return a + b, nil
+}
+
 func getPendingTransaction(ptx *adt.Map, txnID TxnID) (Transaction, error) {
 	var out Transaction
 	found, err := ptx.Get(txnID, &out)
@@ -3,10 +3,14 @@ package multisig_test
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"math"
 	"testing"
 
 	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
 	"github.com/minio/blake2b-simd"
+	mh "github.com/multiformats/go-multihash"
 	assert "github.com/stretchr/testify/assert"
 	require "github.com/stretchr/testify/require"
 
@@ -54,7 +58,7 @@ func TestConstruction(t *testing.T) {
 
 		var st multisig.State
 		rt.GetState(&st)
-		assert.Equal(t, params.Signers, st.Signers)
+		assert.Equal(t, params.Signers, st.SignerAddresses())
 		assert.Equal(t, params.NumApprovalsThreshold, st.NumApprovalsThreshold)
 		assert.Equal(t, abi.NewTokenAmount(0), st.InitialBalance)
 		assert.Equal(t, abi.ChainEpoch(0), st.UnlockDuration)
@@ -80,7 +84,7 @@ func TestConstruction(t *testing.T) {
 
 		var st multisig.State
 		rt.GetState(&st)
-		assert.Equal(t, params.Signers, st.Signers)
+		assert.Equal(t, params.Signers, st.SignerAddresses())
 		assert.Equal(t, params.NumApprovalsThreshold, st.NumApprovalsThreshold)
 		assert.Equal(t, abi.NewTokenAmount(0), st.InitialBalance)
 		assert.Equal(t, abi.ChainEpoch(100), st.UnlockDuration)
@@ -279,6 +283,155 @@ func TestVesting(t *testing.T) {
 
 }
 
+func TestLockBalance(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	darlene := tutil.NewIDAddr(t, 103)
+
+	const noUnlockDuration = int64(0)
+	var fakeParams = runtime.CBORBytes([]byte{1, 2, 3, 4})
+
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithEpoch(0).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("lock balance after construction", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, anne, bob)
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		actor.lockBalance(rt, 10, 10, abi.NewTokenAmount(100))
+
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, abi.NewTokenAmount(100), st.InitialBalance)
+		assert.Equal(t, abi.ChainEpoch(10), st.StartEpoch)
+		assert.Equal(t, abi.ChainEpoch(10), st.UnlockDuration)
+		assert.Empty(t, st.LockedTranches)
+
+		// all funds are locked before the start epoch
+		rt.SetBalance(abi.NewTokenAmount(100))
+		rt.SetEpoch(5)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			_ = actor.propose(rt, darlene, abi.NewTokenAmount(1), builtin.MethodSend, fakeParams, nil)
+		})
+
+		// half is unlocked half way through the duration
+		rt.SetEpoch(15)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(darlene, builtin.MethodSend, fakeParams, abi.NewTokenAmount(50), nil, exitcode.Ok)
+		actor.proposeOK(rt, darlene, abi.NewTokenAmount(50), builtin.MethodSend, fakeParams, nil)
+	})
+
+	t.Run("add tranche to existing schedule", func(t *testing.T) {
+		rt := builder.WithBalance(abi.NewTokenAmount(100), abi.NewTokenAmount(100)).Build(t)
+		actor.constructAndVerify(rt, 1, 10, anne, bob)
+
+		// a second tranche arrives and is locked from epoch 10
+		rt.SetBalance(abi.NewTokenAmount(200))
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		actor.lockBalance(rt, 10, 20, abi.NewTokenAmount(100))
+
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, abi.NewTokenAmount(100), st.InitialBalance)
+		assert.Equal(t, []multisig.VestingTranche{{
+			Amount:         abi.NewTokenAmount(100),
+			StartEpoch:     10,
+			UnlockDuration: 20,
+		}}, st.LockedTranches)
+
+		// at epoch 10 the first tranche is fully vested and the second has not started
+		assert.Equal(t, abi.NewTokenAmount(100), st.LockedBalance(10))
+		// at epoch 20 half the second tranche has vested
+		assert.Equal(t, abi.NewTokenAmount(50), st.LockedBalance(20))
+		assert.Equal(t, big.Zero(), st.LockedBalance(30))
+
+		rt.SetEpoch(20)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			_ = actor.propose(rt, darlene, abi.NewTokenAmount(151), builtin.MethodSend, fakeParams, nil)
+		})
+
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(darlene, builtin.MethodSend, fakeParams, abi.NewTokenAmount(150), nil, exitcode.Ok)
+		actor.proposeOK(rt, darlene, abi.NewTokenAmount(150), builtin.MethodSend, fakeParams, nil)
+	})
+
+	t.Run("vested tranches are dropped when a tranche is added", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 10, anne, bob)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+
+		for i := 0; i < 3; i++ {
+			actor.lockBalance(rt, 0, 10, abi.NewTokenAmount(1))
+		}
+		actor.lockBalance(rt, 0, 100, abi.NewTokenAmount(1))
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Len(t, st.LockedTranches, 4)
+
+		// the three short tranches have vested by epoch 10
+		rt.SetEpoch(10)
+		actor.lockBalance(rt, 10, 10, abi.NewTokenAmount(2))
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.VestingTranche{
+			{Amount: abi.NewTokenAmount(1), StartEpoch: 0, UnlockDuration: 100},
+			{Amount: abi.NewTokenAmount(2), StartEpoch: 10, UnlockDuration: 10},
+		}, st.LockedTranches)
+	})
+
+	t.Run("fail to add more than the maximum unvested tranches", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 10, anne, bob)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+
+		for i := 0; i < multisig.MaxVestingTranches; i++ {
+			actor.lockBalance(rt, 0, 100, abi.NewTokenAmount(1))
+		}
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.lockBalance(rt, 0, 100, abi.NewTokenAmount(1))
+		})
+
+		// once tranches vest there is room for more
+		rt.SetEpoch(100)
+		actor.lockBalance(rt, 100, 100, abi.NewTokenAmount(1))
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Len(t, st.LockedTranches, 1)
+	})
+
+	t.Run("fail to lock balance when not called by self", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, anne, bob)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.lockBalance(rt, 0, 10, abi.NewTokenAmount(100))
+		})
+	})
+
+	t.Run("fail to lock with invalid parameters", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, anne, bob)
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.lockBalance(rt, 0, 0, abi.NewTokenAmount(100))
+		})
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.lockBalance(rt, 0, 10, abi.NewTokenAmount(-1))
+		})
+	})
+}
+
 func TestPropose(t *testing.T) {
 	actor := msActorHarness{multisig.Actor{}, t}
 
@@ -765,6 +918,114 @@ func TestApprove(t *testing.T) {
 	})
 }
 
+func TestApproveMany(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+	richard := tutil.NewIDAddr(t, 104)
+
+	const noUnlockDuration = int64(0)
+	const fakeMethod = abi.MethodNum(42)
+	var fakeParams = runtime.CBORBytes([]byte{1, 2, 3, 4})
+	var sendValue = abi.NewTokenAmount(10)
+
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("failed approvals do not revert others", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 3, noUnlockDuration, anne, bob, chuck)
+		rt.SetBalance(big.Mul(big.NewInt(3), sendValue))
+
+		// anne proposes three transactions, and bob has already approved the third
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		hashes := make([][]byte, 3)
+		for i := range hashes {
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			hashes[i] = actor.proposeOK(rt, richard, sendValue, fakeMethod, fakeParams, nil)
+		}
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.approveOK(rt, 2, hashes[2], nil)
+
+		// chuck approves the first with a bad hash, a transaction that does not exist, the second, and the third
+		rt.SetCaller(chuck, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(richard, fakeMethod, fakeParams, sendValue, runtime.CBORBytes([]byte{5}), exitcode.Ok)
+		results := actor.approveMany(rt,
+			multisig.TxnIDParams{ID: 0, ProposalHash: []byte("wrong")},
+			multisig.TxnIDParams{ID: 5},
+			multisig.TxnIDParams{ID: 1, ProposalHash: hashes[1]},
+			multisig.TxnIDParams{ID: 2, ProposalHash: hashes[2]},
+		)
+		assert.Equal(t, []multisig.ApproveReturn{
+			{Applied: false, Code: exitcode.ErrIllegalArgument},
+			{Applied: false, Code: exitcode.ErrNotFound},
+			{Applied: false, Code: exitcode.Ok},
+			{Applied: true, Code: exitcode.Ok, Ret: []byte{5}},
+		}, results)
+
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       richard,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		}, multisig.Transaction{
+			To:       richard,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne, chuck},
+		})
+	})
+
+	t.Run("executes approved transactions in order", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, anne, bob)
+		rt.SetBalance(sendValue)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeOK(rt, richard, sendValue, fakeMethod, fakeParams, nil)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		// only enough balance remains for the first transaction, so the second is left unapproved
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(richard, fakeMethod, fakeParams, sendValue, nil, exitcode.Ok)
+		results := actor.approveMany(rt, multisig.TxnIDParams{ID: 0}, multisig.TxnIDParams{ID: 1})
+		assert.Equal(t, []multisig.ApproveReturn{
+			{Applied: true, Code: exitcode.Ok, Ret: []byte{}},
+			{Applied: false, Code: exitcode.ErrInsufficientFunds},
+		}, results)
+
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		})
+	})
+
+	t.Run("fail to approve many by non-signer", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, anne, bob)
+
+		rt.SetCaller(richard, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.approveMany(rt, multisig.TxnIDParams{ID: 0})
+		})
+	})
+}
+
 func TestCancel(t *testing.T) {
 	actor := msActorHarness{multisig.Actor{}, t}
 
@@ -1124,7 +1385,7 @@ func TestAddSigner(t *testing.T) {
 				actor.addSigner(rt, tc.addSigner, tc.increase)
 				var st multisig.State
 				rt.Readonly(&st)
-				assert.Equal(t, tc.expectSigners, st.Signers)
+				assert.Equal(t, tc.expectSigners, st.SignerAddresses())
 				assert.Equal(t, tc.expectApprovals, st.NumApprovalsThreshold)
 			}
 			rt.Verify()
@@ -1234,6 +1495,19 @@ func TestRemoveSigner(t *testing.T) {
 			expectApprovals: uint64(1),
 			code:            exitcode.Ok,
 		},
+		{
+			desc: "remove signer and decrease threshold to zero",
+
+			initialSigners:   []addr.Address{anne, bob},
+			initialApprovals: uint64(1),
+
+			removeSigner: bob,
+			decrease:     true,
+
+			expectSigners:   []addr.Address{anne},
+			expectApprovals: uint64(0),
+			code:            exitcode.Ok,
+		},
 		{
 			desc: "fail remove signer if decrease set to false and number of signers below threshold",
 
@@ -1293,7 +1567,7 @@ func TestRemoveSigner(t *testing.T) {
 				actor.removeSigner(rt, tc.removeSigner, tc.decrease)
 				var st multisig.State
 				rt.Readonly(&st)
-				assert.Equal(t, tc.expectSigners, st.Signers)
+				assert.Equal(t, tc.expectSigners, st.SignerAddresses())
 				assert.Equal(t, tc.expectApprovals, st.NumApprovalsThreshold)
 			}
 			rt.Verify()
@@ -1418,7 +1692,7 @@ func TestSwapSigners(t *testing.T) {
 				actor.swapSigners(rt, tc.from, tc.to)
 				var st multisig.State
 				rt.Readonly(&st)
-				assert.Equal(t, tc.expect, st.Signers)
+				assert.Equal(t, tc.expect, st.SignerAddresses())
 			}
 			rt.Verify()
 		})
@@ -1496,6 +1770,476 @@ func TestChangeThreshold(t *testing.T) {
 	}
 }
 
+func TestExpiration(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+	richard := tutil.NewIDAddr(t, 104)
+
+	const noUnlockDuration = int64(0)
+	const numApprovals = uint64(2)
+	const fakeMethod = abi.MethodNum(42)
+	var fakeParams = []byte{1, 2, 3, 4, 5}
+	var sendValue = abi.NewTokenAmount(10)
+	var signers = []addr.Address{anne, bob}
+	const expiration = abi.ChainEpoch(100)
+
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("approve before expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		proposalHash := actor.proposeWithExpirationOK(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     fakeMethod,
+			Params:     fakeParams,
+			Expiration: expiration,
+			Approved:   []addr.Address{anne},
+		})
+
+		rt.SetEpoch(expiration - 1)
+		rt.SetBalance(sendValue)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, runtime.CBORBytes(fakeParams), sendValue, nil, 0)
+		actor.approveOK(rt, 0, proposalHash, nil)
+		actor.assertTransactions(rt)
+	})
+
+	t.Run("fail to approve expired transaction", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		proposalHash := actor.proposeWithExpirationOK(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+
+		rt.SetEpoch(expiration)
+		rt.SetBalance(sendValue)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			_ = actor.approve(rt, 0, proposalHash, nil)
+		})
+	})
+
+	t.Run("proposal hash commits to expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+
+		wrongHash := makeProposalHash(t, &multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     fakeMethod,
+			Params:     fakeParams,
+			Expiration: expiration + 1,
+			Approved:   []addr.Address{anne},
+		})
+
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			_ = actor.approve(rt, 0, wrongHash, nil)
+		})
+	})
+
+	t.Run("fail to propose with past expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetEpoch(expiration)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			_ = actor.proposeWithExpiration(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+		})
+	})
+
+	t.Run("signer prunes only expired transactions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeWithExpirationOK(rt, chuck, sendValue, fakeMethod, fakeParams, expiration+10)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		// bob did not propose the expired transaction but may still prune it
+		rt.SetEpoch(expiration)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.pruneExpired(rt)
+
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     fakeMethod,
+			Params:     fakeParams,
+			Expiration: expiration + 10,
+			Approved:   []addr.Address{anne},
+		}, multisig.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		})
+	})
+
+	t.Run("fail to prune when not signer", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, signers...)
+
+		rt.SetEpoch(expiration)
+		rt.SetCaller(richard, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			actor.pruneExpired(rt)
+		})
+	})
+}
+
+func TestLegacyEncodings(t *testing.T) {
+	// Encodings produced before transactions could expire.
+	legacyTxn := mustDecodeHex(t, "8542006642000a0242010281420065")
+	legacyPropose := mustDecodeHex(t, "8442006642000a02420102")
+	legacyHashData := mustDecodeHex(t, "8542006542006642000a02420102")
+
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+
+	t.Run("transaction", func(t *testing.T) {
+		var txn multisig.Transaction
+		require.NoError(t, txn.UnmarshalCBOR(bytes.NewReader(legacyTxn)))
+		expected := multisig.Transaction{To: bob, Value: abi.NewTokenAmount(10), Method: 2, Params: []byte{1, 2}, Approved: []addr.Address{anne}}
+		assert.Equal(t, expected, txn)
+		assert.Equal(t, legacyTxn, marshal(t, &txn))
+
+		txn.Expiration = 100
+		var decoded multisig.Transaction
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(marshal(t, &txn))))
+		assert.Equal(t, txn, decoded)
+	})
+
+	t.Run("state", func(t *testing.T) {
+		// Encoded before signers were weighted and before balances could be locked after construction.
+		legacyState := mustDecodeHex(t, "87824200654200660203420064050ad82a5825000171122062a2fed3d6e08c44835fce71f02210b1ddabfb066e39edf1e6c261988f824dd3")
+		pending := tutil.MakeCID("pending", &cid.Prefix{Version: 1, Codec: cid.DagCBOR, MhType: mh.SHA2_256, MhLength: -1})
+
+		var st multisig.State
+		require.NoError(t, st.UnmarshalCBOR(bytes.NewReader(legacyState)))
+		expected := multisig.State{
+			Signers:               []multisig.Signer{{anne, multisig.DefaultSignerWeight}, {bob, multisig.DefaultSignerWeight}},
+			NumApprovalsThreshold: 2,
+			NextTxnID:             3,
+			InitialBalance:        abi.NewTokenAmount(100),
+			StartEpoch:            5,
+			UnlockDuration:        10,
+			PendingTxns:           pending,
+		}
+		assert.Equal(t, expected, st)
+		assert.Equal(t, legacyState, marshal(t, &st))
+		totalWeight, err := st.TotalWeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), totalWeight)
+
+		// Signers of other weights are encoded as tuples, alongside bare addresses.
+		st.Signers[1].Weight = 3
+		var weighted multisig.State
+		require.NoError(t, weighted.UnmarshalCBOR(bytes.NewReader(marshal(t, &st))))
+		assert.Equal(t, st, weighted)
+		st.Signers[1].Weight = multisig.DefaultSignerWeight
+
+		st.LockedTranches = []multisig.VestingTranche{{StartEpoch: 20, UnlockDuration: 30, Amount: abi.NewTokenAmount(40)}}
+		var decoded multisig.State
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(marshal(t, &st))))
+		assert.Equal(t, st, decoded)
+	})
+
+	t.Run("transaction with empty params and approvals", func(t *testing.T) {
+		legacyEmpty := mustDecodeHex(t, "8542006642000a024080")
+		var txn multisig.Transaction
+		require.NoError(t, txn.UnmarshalCBOR(bytes.NewReader(legacyEmpty)))
+		assert.Equal(t, multisig.Transaction{To: bob, Value: abi.NewTokenAmount(10), Method: 2}, txn)
+		assert.Equal(t, legacyEmpty, marshal(t, &txn))
+	})
+
+	t.Run("propose params", func(t *testing.T) {
+		var params multisig.ProposeParams
+		require.NoError(t, params.UnmarshalCBOR(bytes.NewReader(legacyPropose)))
+		assert.Equal(t, multisig.ProposeParams{To: bob, Value: abi.NewTokenAmount(10), Method: 2, Params: []byte{1, 2}}, params)
+		assert.Equal(t, legacyPropose, marshal(t, &params))
+
+		params.Expiration = -1
+		var decoded multisig.ProposeParams
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(marshal(t, &params))))
+		assert.Equal(t, params, decoded)
+	})
+
+	t.Run("proposal hash is unchanged for transactions without expiration", func(t *testing.T) {
+		txn := multisig.Transaction{To: bob, Value: abi.NewTokenAmount(10), Method: 2, Params: []byte{1, 2}, Approved: []addr.Address{anne}}
+		hash, err := multisig.ComputeProposalHash(&txn, blake2b.Sum256)
+		require.NoError(t, err)
+		expected := blake2b.Sum256(legacyHashData)
+		assert.Equal(t, expected[:], hash)
+
+		txn.Expiration = 100
+		hash, err = multisig.ComputeProposalHash(&txn, blake2b.Sum256)
+		require.NoError(t, err)
+		assert.NotEqual(t, expected[:], hash)
+	})
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func marshal(t *testing.T, v runtime.CBORMarshaler) []byte {
+	buf := new(bytes.Buffer)
+	require.NoError(t, v.MarshalCBOR(buf))
+	return buf.Bytes()
+}
+
+func TestWeightedSigners(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+	darlene := tutil.NewIDAddr(t, 104)
+
+	const noUnlockDuration = int64(0)
+	const fakeMethod = abi.MethodNum(42)
+	var fakeParams = runtime.CBORBytes([]byte{1, 2, 3, 4})
+	var sendValue = abi.NewTokenAmount(10)
+
+	builder := mock.NewBuilder(context.Background(), receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("construction with weights", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 4, []addr.Address{anne, bob, chuck}, []uint64{3, 1, 1})
+
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{anne, 3}, {bob, 1}, {chuck, 1}}, st.Signers)
+		totalWeight, err := st.TotalWeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), totalWeight)
+		assert.Equal(t, uint64(4), st.NumApprovalsThreshold)
+	})
+
+	t.Run("fail to construct with threshold above total weight", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.Constructor, &multisig.ConstructorParams{
+				Signers:               []addr.Address{anne, bob},
+				Weights:               []uint64{2, 1},
+				NumApprovalsThreshold: 4,
+			})
+		})
+	})
+
+	t.Run("fail to construct with zero weight or mismatched weights", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.Constructor, &multisig.ConstructorParams{
+				Signers:               []addr.Address{anne, bob},
+				Weights:               []uint64{1, 0},
+				NumApprovalsThreshold: 1,
+			})
+		})
+		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.Constructor, &multisig.ConstructorParams{
+				Signers:               []addr.Address{anne, bob},
+				Weights:               []uint64{1},
+				NumApprovalsThreshold: 1,
+			})
+		})
+	})
+
+	t.Run("fail to construct with weights overflowing total", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.Constructor, &multisig.ConstructorParams{
+				Signers:               []addr.Address{anne, bob},
+				Weights:               []uint64{math.MaxUint64, 1},
+				NumApprovalsThreshold: 1,
+			})
+		})
+	})
+
+	t.Run("fail to add signer with zero or overflowing weight", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 2, []addr.Address{anne, bob}, []uint64{2, 1})
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.AddSigner, &multisig.AddSignerParams{Signer: chuck, Weight: 0})
+		})
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.AddSigner, &multisig.AddSignerParams{Signer: chuck, Weight: math.MaxUint64 - 2})
+		})
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.SwapSigner, &multisig.SwapSignerParams{From: bob, To: chuck, Weight: math.MaxUint64})
+		})
+
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{anne, 2}, {bob, 1}}, st.Signers)
+	})
+
+	t.Run("transaction executes when approval weight meets threshold", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 4, []addr.Address{anne, bob, chuck}, []uint64{3, 1, 1})
+		rt.SetBalance(sendValue)
+
+		// bob and chuck together do not meet the threshold
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		proposalHash := actor.proposeOK(rt, darlene, sendValue, fakeMethod, fakeParams, nil)
+
+		rt.SetCaller(chuck, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		actor.approveOK(rt, 0, proposalHash, nil)
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       darlene,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{bob, chuck},
+		})
+
+		// anne's approval carries the transaction
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(darlene, fakeMethod, fakeParams, sendValue, nil, exitcode.Ok)
+		actor.approveOK(rt, 0, proposalHash, nil)
+		actor.assertTransactions(rt)
+	})
+
+	t.Run("add, remove and swap weighted signers", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 2, []addr.Address{anne, bob}, []uint64{2, 1})
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.Call(actor.a.AddSigner, &multisig.AddSignerParams{Signer: chuck, Weight: 3, Increase: true})
+		rt.Verify()
+
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{anne, 2}, {bob, 1}, {chuck, 3}}, st.Signers)
+		assert.Equal(t, uint64(5), st.NumApprovalsThreshold)
+
+		// the new signer inherits the weight of the signer it replaces
+		rt.ExpectValidateCallerAddr(receiver)
+		actor.swapSigners(rt, bob, darlene)
+		rt.Verify()
+
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.Call(actor.a.SwapSigner, &multisig.SwapSignerParams{From: darlene, To: bob, Weight: 2})
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{anne, 2}, {chuck, 3}, {bob, 2}}, st.Signers)
+
+		rt.ExpectValidateCallerAddr(receiver)
+		actor.removeSigner(rt, chuck, true)
+
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{anne, 2}, {bob, 2}}, st.Signers)
+		assert.Equal(t, uint64(2), st.NumApprovalsThreshold)
+
+		// decreasing the threshold by anne's weight stops at zero
+		rt.ExpectValidateCallerAddr(receiver)
+		actor.removeSigner(rt, anne, true)
+
+		rt.GetState(&st)
+		assert.Equal(t, []multisig.Signer{{bob, 2}}, st.Signers)
+		assert.Equal(t, uint64(0), st.NumApprovalsThreshold)
+	})
+
+	t.Run("approvals from removed signers count with default weight", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 3, []addr.Address{anne, bob, chuck}, []uint64{1, 2, 1})
+		rt.SetBalance(sendValue)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		proposalHash := actor.proposeOK(rt, darlene, sendValue, fakeMethod, fakeParams, nil)
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		actor.removeSigner(rt, anne, false)
+
+		// anne's approval still counts once, so bob's approval carries the transaction
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectSend(darlene, fakeMethod, fakeParams, sendValue, nil, exitcode.Ok)
+		actor.approveOK(rt, 0, proposalHash, nil)
+		actor.assertTransactions(rt)
+	})
+
+	t.Run("change threshold up to total weight", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructWeightedAndVerify(rt, 1, []addr.Address{anne, bob}, []uint64{2, 3})
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		actor.changeNumApprovalsThreshold(rt, 5)
+		rt.Verify()
+
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.changeNumApprovalsThreshold(rt, 6)
+		})
+	})
+
+	t.Run("unweighted signers decode with default weight", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, anne.MarshalCBOR(buf))
+
+		var signer multisig.Signer
+		require.NoError(t, signer.UnmarshalCBOR(buf))
+		assert.Equal(t, multisig.Signer{Address: anne, Weight: multisig.DefaultSignerWeight}, signer)
+
+		buf.Reset()
+		require.NoError(t, (&multisig.Signer{Address: bob, Weight: 7}).MarshalCBOR(buf))
+		require.NoError(t, signer.UnmarshalCBOR(buf))
+		assert.Equal(t, multisig.Signer{Address: bob, Weight: 7}, signer)
+	})
+}
+
 //
 // Helper methods for calling multisig actor methods
 //
@@ -1518,6 +2262,19 @@ func (h *msActorHarness) constructAndVerify(rt *mock.Runtime, numApprovalsThresh
 	rt.Verify()
 }
 
+func (h *msActorHarness) constructWeightedAndVerify(rt *mock.Runtime, threshold uint64, signers []addr.Address, weights []uint64) {
+	constructParams := multisig.ConstructorParams{
+		Signers:               signers,
+		Weights:               weights,
+		NumApprovalsThreshold: threshold,
+	}
+
+	rt.ExpectValidateCallerAddr(builtin.InitActorAddr)
+	ret := rt.Call(h.a.Constructor, &constructParams)
+	assert.Nil(h.t, ret)
+	rt.Verify()
+}
+
 func (h *msActorHarness) propose(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, out runtime.CBORUnmarshaler) exitcode.ExitCode {
 	proposeParams := &multisig.ProposeParams{
 		To:     to,
@@ -1560,6 +2317,39 @@ func (h *msActorHarness) proposeOK(rt *mock.Runtime, to addr.Address, value abi.
 	return proposalHashData
 }
 
+func (h *msActorHarness) proposeWithExpiration(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) exitcode.ExitCode {
+	proposeParams := &multisig.ProposeParams{
+		To:         to,
+		Value:      value,
+		Method:     method,
+		Params:     params,
+		Expiration: expiration,
+	}
+	ret := rt.Call(h.a.Propose, proposeParams)
+	rt.Verify()
+	return ret.(*multisig.ProposeReturn).Code
+}
+
+// returns the proposal hash
+func (h *msActorHarness) proposeWithExpirationOK(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) []byte {
+	code := h.proposeWithExpiration(rt, to, value, method, params, expiration)
+	if code != exitcode.Ok {
+		h.t.Fatalf("unexpected exitcode %d from propose", code)
+	}
+
+	proposalHashData, err := multisig.ComputeProposalHash(&multisig.Transaction{
+		To:         to,
+		Value:      value,
+		Method:     method,
+		Params:     params,
+		Expiration: expiration,
+		Approved:   []addr.Address{rt.Caller()},
+	}, blake2b.Sum256)
+	require.NoError(h.t, err)
+
+	return proposalHashData
+}
+
 func (h *msActorHarness) approve(rt *mock.Runtime, txnID int64, proposalParams []byte, out runtime.CBORUnmarshaler) exitcode.ExitCode {
 	approveParams := &multisig.TxnIDParams{ID: multisig.TxnID(txnID), ProposalHash: proposalParams}
 	ret := rt.Call(h.a.Approve, approveParams)
@@ -1584,15 +2374,37 @@ func (h *msActorHarness) approveOK(rt *mock.Runtime, txnID int64, proposalParams
 	}
 }
 
+func (h *msActorHarness) approveMany(rt *mock.Runtime, approvals ...multisig.TxnIDParams) []multisig.ApproveReturn {
+	ret := rt.Call(h.a.ApproveMany, &multisig.ApproveManyParams{Approvals: approvals})
+	rt.Verify()
+	return ret.(*multisig.ApproveManyReturn).Results
+}
+
 func (h *msActorHarness) cancel(rt *mock.Runtime, txnID int64, proposalParams []byte) {
 	cancelParams := &multisig.TxnIDParams{ID: multisig.TxnID(txnID), ProposalHash: proposalParams}
 	rt.Call(h.a.Cancel, cancelParams)
 	rt.Verify()
 }
 
+func (h *msActorHarness) pruneExpired(rt *mock.Runtime) {
+	rt.Call(h.a.PruneExpired, nil)
+	rt.Verify()
+}
+
+func (h *msActorHarness) lockBalance(rt *mock.Runtime, start, duration abi.ChainEpoch, amount abi.TokenAmount) {
+	rt.ExpectValidateCallerAddr(rt.Receiver())
+	rt.Call(h.a.LockBalance, &multisig.LockBalanceParams{
+		StartEpoch:     start,
+		UnlockDuration: duration,
+		Amount:         amount,
+	})
+	rt.Verify()
+}
+
 func (h *msActorHarness) addSigner(rt *mock.Runtime, signer addr.Address, increase bool) {
 	addSignerParams := &multisig.AddSignerParams{
 		Signer:   signer,
+		Weight:   multisig.DefaultSignerWeight,
 		Increase: increase,
 	}
 	rt.Call(h.a.AddSigner, addSignerParams)
@@ -0,0 +1,89 @@
+package multisig
+
+import (
+	"fmt"
+	"io"
+
+	addr "github.com/filecoin-project/go-address"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// The weight of a signer whose weight was not given, including signers recorded before weights were introduced.
+const DefaultSignerWeight = uint64(1)
+
+// A signer of a multisig wallet and the weight its approval contributes towards the threshold.
+type Signer struct {
+	Address addr.Address
+	Weight  uint64
+}
+
+// Signers are encoded as a tuple of address and weight, except that a signer of DefaultSignerWeight is encoded as
+// a bare address, as recorded in state before signers were weighted. A bare address decodes as a signer of
+// DefaultSignerWeight, so state with only such signers keeps its original encoding.
+
+var lengthBufSigner = []byte{130}
+
+func (s *Signer) MarshalCBOR(w io.Writer) error {
+	if s == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if s.Weight == DefaultSignerWeight {
+		return s.Address.MarshalCBOR(w)
+	}
+	if _, err := w.Write(lengthBufSigner); err != nil {
+		return err
+	}
+	if err := s.Address.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return cbg.WriteMajorTypeHeader(w, cbg.MajUnsignedInt, s.Weight)
+}
+
+func (s *Signer) UnmarshalCBOR(r io.Reader) error {
+	*s = Signer{}
+
+	br := cbg.GetPeeker(r)
+	first, err := br.ReadByte()
+	if err != nil {
+		return err
+	}
+	if err := br.UnreadByte(); err != nil {
+		return err
+	}
+
+	// The major type is held in the high three bits of the initial byte.
+	if first>>5 == cbg.MajByteString {
+		if err := s.Address.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling unweighted signer address: %w", err)
+		}
+		s.Weight = DefaultSignerWeight
+		return nil
+	}
+
+	maj, extra, err := cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input for signer should be of type array or byte string")
+	}
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	if err := s.Address.UnmarshalCBOR(br); err != nil {
+		return xerrors.Errorf("unmarshaling s.Address: %w", err)
+	}
+
+	maj, extra, err = cbg.CborReadHeader(br)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajUnsignedInt {
+		return fmt.Errorf("wrong type for uint64 field")
+	}
+	s.Weight = extra
+	return nil
+}
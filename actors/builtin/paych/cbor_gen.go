@@ -14,7 +14,7 @@ import (
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{134}
+var lengthBufState = []byte{135}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -42,6 +42,11 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return err
 	}
 
+	// t.PaidOut (big.Int) (struct)
+	if err := t.PaidOut.MarshalCBOR(w); err != nil {
+		return err
+	}
+
 	// t.SettlingAt (abi.ChainEpoch) (int64)
 	if t.SettlingAt >= 0 {
 		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SettlingAt)); err != nil {
@@ -64,19 +69,12 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		}
 	}
 
-	// t.LaneStates ([]*paych.LaneState) (slice)
-	if len(t.LaneStates) > cbg.MaxLength {
-		return xerrors.Errorf("Slice value in field t.LaneStates was too long")
-	}
+	// t.LaneStates (cid.Cid) (struct)
 
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.LaneStates))); err != nil {
-		return err
-	}
-	for _, v := range t.LaneStates {
-		if err := v.MarshalCBOR(w); err != nil {
-			return err
-		}
+	if err := cbg.WriteCidBuf(scratch, w, t.LaneStates); err != nil {
+		return xerrors.Errorf("failed to write cid field t.LaneStates: %w", err)
 	}
+
 	return nil
 }
 
@@ -94,7 +92,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 6 {
+	if extra != 7 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -124,6 +122,15 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 			return xerrors.Errorf("unmarshaling t.ToSend: %w", err)
 		}
 
+	}
+	// t.PaidOut (big.Int) (struct)
+
+	{
+
+		if err := t.PaidOut.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.PaidOut: %w", err)
+		}
+
 	}
 	// t.SettlingAt (abi.ChainEpoch) (int64)
 	{
@@ -175,39 +182,22 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		t.MinSettleHeight = abi.ChainEpoch(extraI)
 	}
-	// t.LaneStates ([]*paych.LaneState) (slice)
-
-	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-	if err != nil {
-		return err
-	}
-
-	if extra > cbg.MaxLength {
-		return fmt.Errorf("t.LaneStates: array too large (%d)", extra)
-	}
-
-	if maj != cbg.MajArray {
-		return fmt.Errorf("expected cbor array")
-	}
-
-	if extra > 0 {
-		t.LaneStates = make([]*LaneState, extra)
-	}
+	// t.LaneStates (cid.Cid) (struct)
 
-	for i := 0; i < int(extra); i++ {
+	{
 
-		var v LaneState
-		if err := v.UnmarshalCBOR(br); err != nil {
-			return err
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.LaneStates: %w", err)
 		}
 
-		t.LaneStates[i] = &v
-	}
+		t.LaneStates = c
 
+	}
 	return nil
 }
 
-var lengthBufLaneState = []byte{131}
+var lengthBufLaneState = []byte{130}
 
 func (t *LaneState) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -220,12 +210,6 @@ func (t *LaneState) MarshalCBOR(w io.Writer) error {
 
 	scratch := make([]byte, 9)
 
-	// t.ID (uint64) (uint64)
-
-	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ID)); err != nil {
-		return err
-	}
-
 	// t.Redeemed (big.Int) (struct)
 	if err := t.Redeemed.MarshalCBOR(w); err != nil {
 		return err
@@ -254,24 +238,10 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 3 {
+	if extra != 2 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
-	// t.ID (uint64) (uint64)
-
-	{
-
-		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
-		if err != nil {
-			return err
-		}
-		if maj != cbg.MajUnsignedInt {
-			return fmt.Errorf("wrong type for uint64 field")
-		}
-		t.ID = uint64(extra)
-
-	}
 	// t.Redeemed (big.Int) (struct)
 
 	{
@@ -375,6 +345,290 @@ func (t *Merge) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufLegacyState = []byte{134}
+
+func (t *LegacyState) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLegacyState); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.From (address.Address) (struct)
+	if err := t.From.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.To (address.Address) (struct)
+	if err := t.To.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ToSend (big.Int) (struct)
+	if err := t.ToSend.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.SettlingAt (abi.ChainEpoch) (int64)
+	if t.SettlingAt >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.SettlingAt)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.SettlingAt-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.MinSettleHeight (abi.ChainEpoch) (int64)
+	if t.MinSettleHeight >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.MinSettleHeight)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.MinSettleHeight-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.LaneStates ([]*paych.LegacyLaneState) (slice)
+	if len(t.LaneStates) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.LaneStates was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.LaneStates))); err != nil {
+		return err
+	}
+	for _, v := range t.LaneStates {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *LegacyState) UnmarshalCBOR(r io.Reader) error {
+	*t = LegacyState{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 6 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.From (address.Address) (struct)
+
+	{
+
+		if err := t.From.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.From: %w", err)
+		}
+
+	}
+	// t.To (address.Address) (struct)
+
+	{
+
+		if err := t.To.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.To: %w", err)
+		}
+
+	}
+	// t.ToSend (big.Int) (struct)
+
+	{
+
+		if err := t.ToSend.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ToSend: %w", err)
+		}
+
+	}
+	// t.SettlingAt (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.SettlingAt = abi.ChainEpoch(extraI)
+	}
+	// t.MinSettleHeight (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.MinSettleHeight = abi.ChainEpoch(extraI)
+	}
+	// t.LaneStates ([]*paych.LegacyLaneState) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.LaneStates: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.LaneStates = make([]*LegacyLaneState, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v LegacyLaneState
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.LaneStates[i] = &v
+	}
+
+	return nil
+}
+
+var lengthBufLegacyLaneState = []byte{131}
+
+func (t *LegacyLaneState) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLegacyLaneState); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.ID (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.ID)); err != nil {
+		return err
+	}
+
+	// t.Redeemed (big.Int) (struct)
+	if err := t.Redeemed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Nonce (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Nonce)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *LegacyLaneState) UnmarshalCBOR(r io.Reader) error {
+	*t = LegacyLaneState{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 3 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.ID (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.ID = uint64(extra)
+
+	}
+	// t.Redeemed (big.Int) (struct)
+
+	{
+
+		if err := t.Redeemed.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Redeemed: %w", err)
+		}
+
+	}
+	// t.Nonce (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Nonce = uint64(extra)
+
+	}
+	return nil
+}
+
 var lengthBufConstructorParams = []byte{130}
 
 func (t *ConstructorParams) MarshalCBOR(w io.Writer) error {
@@ -555,6 +809,54 @@ func (t *UpdateChannelStateParams) UnmarshalCBOR(r io.Reader) error {
 	return nil
 }
 
+var lengthBufRegisterHTLCParams = []byte{129}
+
+func (t *RegisterHTLCParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufRegisterHTLCParams); err != nil {
+		return err
+	}
+
+	// t.Sv (paych.SignedVoucher) (struct)
+	if err := t.Sv.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *RegisterHTLCParams) UnmarshalCBOR(r io.Reader) error {
+	*t = RegisterHTLCParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sv (paych.SignedVoucher) (struct)
+
+	{
+
+		if err := t.Sv.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Sv: %w", err)
+		}
+
+	}
+	return nil
+}
+
 var lengthBufSignedVoucher = []byte{139}
 
 func (t *SignedVoucher) MarshalCBOR(w io.Writer) error {
@@ -0,0 +1,115 @@
+// Package htlc supports conditional payments routed over a chain of payment channels.
+//
+// A payment from a sender to a receiver is routed through intermediaries, each hop being a payment channel from
+// one party to the next. Each hop pays with a hash-time-locked voucher: it can be redeemed only with the secret
+// whose hash is shared by the whole route, and only up to the hop's deadline. When the receiver redeems its
+// voucher it reveals the secret on chain, which lets each intermediary in turn redeem its incoming voucher.
+//
+// Deadlines must decrease along the route so that an intermediary that learns the secret when its outgoing
+// voucher is redeemed has time to redeem its incoming voucher. The holder of each voucher should register it
+// with the channel (paych.Actor.RegisterHTLC) so that the channel cannot be collected before the deadline.
+// A voucher that is not redeemed by its deadline can no longer be redeemed, and its amount is refunded to
+// the channel's From when the channel is collected.
+//
+// Each conditional payment should use a lane of its own, since a later voucher on a lane supersedes it.
+package htlc
+
+import (
+	"bytes"
+
+	addr "github.com/filecoin-project/go-address"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/builtin/paych"
+)
+
+// A payment channel along a route, with the lane on which it will pay.
+type Hop struct {
+	Channel addr.Address
+	Lane    uint64
+	Nonce   uint64
+	// The amount redeemable from the lane, including any fees for later hops.
+	Amount abi.TokenAmount
+}
+
+// A route's common parameters.
+type Route struct {
+	// The hash of the secret that unlocks all vouchers on the route.
+	Hash [32]byte
+	// The deadline of the last hop's voucher, paying the receiver.
+	Deadline abi.ChainEpoch
+	// The number of epochs by which each hop's deadline follows that of the next hop.
+	Delta abi.ChainEpoch
+}
+
+// Vouchers returns unsigned vouchers for each hop of a route, in order from the sender to the receiver.
+// Each voucher is to be signed by its channel's From.
+func (r *Route) Vouchers(hops []Hop) ([]*paych.SignedVoucher, error) {
+	if r.Delta <= 0 {
+		return nil, xerrors.Errorf("route delta must be positive, was %d", r.Delta)
+	}
+	if r.Deadline <= 0 {
+		return nil, xerrors.Errorf("route deadline must be positive, was %d", r.Deadline)
+	}
+
+	vouchers := make([]*paych.SignedVoucher, len(hops))
+	for i, hop := range hops {
+		if i > 0 && hop.Amount.GreaterThan(hops[i-1].Amount) {
+			return nil, xerrors.Errorf("hop %d amount %v exceeds previous hop amount %v", i, hop.Amount, hops[i-1].Amount)
+		}
+		vouchers[i] = &paych.SignedVoucher{
+			ChannelAddr:    hop.Channel,
+			TimeLockMax:    r.Deadline + abi.ChainEpoch(len(hops)-1-i)*r.Delta,
+			SecretPreimage: append([]byte{}, r.Hash[:]...),
+			Lane:           hop.Lane,
+			Nonce:          hop.Nonce,
+			Amount:         hop.Amount,
+		}
+	}
+	return vouchers, nil
+}
+
+// VerifyHop checks that an intermediary can safely forward a payment received with the incoming voucher
+// by paying with the outgoing voucher: that both are hash-time-locked with the same hash, that the outgoing
+// voucher pays no more than the incoming, and that its deadline precedes the incoming voucher's by at least minDelta.
+func VerifyHop(incoming, outgoing *paych.SignedVoucher, minDelta abi.ChainEpoch) error {
+	if !incoming.HashTimeLocked() {
+		return xerrors.Errorf("incoming voucher is not hash-time-locked")
+	}
+	if !outgoing.HashTimeLocked() {
+		return xerrors.Errorf("outgoing voucher is not hash-time-locked")
+	}
+	if !bytes.Equal(incoming.SecretPreimage, outgoing.SecretPreimage) {
+		return xerrors.Errorf("incoming and outgoing vouchers have different secret preimages")
+	}
+	if outgoing.Amount.GreaterThan(incoming.Amount) {
+		return xerrors.Errorf("outgoing amount %v exceeds incoming amount %v", outgoing.Amount, incoming.Amount)
+	}
+	if outgoing.TimeLockMax+minDelta > incoming.TimeLockMax {
+		return xerrors.Errorf("outgoing deadline %d is less than %d epochs before incoming deadline %d",
+			outgoing.TimeLockMax, minDelta, incoming.TimeLockMax)
+	}
+	return nil
+}
+
+// VerifyRoute checks every hop of a route, given as vouchers in order from the sender to the receiver,
+// and that the receiver's voucher can still be redeemed at epoch.
+func VerifyRoute(vouchers []*paych.SignedVoucher, minDelta, epoch abi.ChainEpoch) error {
+	if len(vouchers) == 0 {
+		return xerrors.Errorf("empty route")
+	}
+	for i := 1; i < len(vouchers); i++ {
+		if err := VerifyHop(vouchers[i-1], vouchers[i], minDelta); err != nil {
+			return xerrors.Errorf("invalid hop %d: %w", i, err)
+		}
+	}
+	last := vouchers[len(vouchers)-1]
+	if !last.HashTimeLocked() {
+		return xerrors.Errorf("receiver voucher is not hash-time-locked")
+	}
+	if epoch > last.TimeLockMax {
+		return xerrors.Errorf("receiver voucher expired at %d", last.TimeLockMax)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package htlc_test
+
+import (
+	"testing"
+
+	"github.com/minio/blake2b-simd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin/paych/htlc"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestRoute(t *testing.T) {
+	secret := []byte("secret")
+	route := &htlc.Route{Hash: blake2b.Sum256(secret), Deadline: 100, Delta: 10}
+	hops := []htlc.Hop{
+		{Channel: tutil.NewIDAddr(t, 100), Lane: 1, Nonce: 1, Amount: big.NewInt(12)},
+		{Channel: tutil.NewIDAddr(t, 101), Lane: 4, Nonce: 1, Amount: big.NewInt(11)},
+		{Channel: tutil.NewIDAddr(t, 102), Lane: 2, Nonce: 1, Amount: big.NewInt(10)},
+	}
+
+	t.Run("builds vouchers with decreasing deadlines", func(t *testing.T) {
+		vouchers, err := route.Vouchers(hops)
+		require.NoError(t, err)
+		require.Len(t, vouchers, 3)
+
+		for i, sv := range vouchers {
+			assert.Equal(t, hops[i].Channel, sv.ChannelAddr)
+			assert.Equal(t, hops[i].Lane, sv.Lane)
+			assert.Equal(t, hops[i].Amount, sv.Amount)
+			assert.Equal(t, route.Hash[:], sv.SecretPreimage)
+			assert.True(t, sv.HashTimeLocked())
+		}
+		assert.Equal(t, abi.ChainEpoch(120), vouchers[0].TimeLockMax)
+		assert.Equal(t, abi.ChainEpoch(110), vouchers[1].TimeLockMax)
+		assert.Equal(t, abi.ChainEpoch(100), vouchers[2].TimeLockMax)
+
+		assert.NoError(t, htlc.VerifyRoute(vouchers, 10, 100))
+		// a route is not safe if intermediaries need more time than it allows
+		assert.Error(t, htlc.VerifyRoute(vouchers, 11, 100))
+		// nor once the receiver can no longer redeem
+		assert.Error(t, htlc.VerifyRoute(vouchers, 10, 101))
+	})
+
+	t.Run("rejects increasing amounts", func(t *testing.T) {
+		bad := append([]htlc.Hop{}, hops...)
+		bad[2].Amount = big.NewInt(12)
+		_, err := route.Vouchers(bad)
+		assert.Error(t, err)
+	})
+
+	t.Run("verifies hops", func(t *testing.T) {
+		vouchers, err := route.Vouchers(hops[:2])
+		require.NoError(t, err)
+		in, out := vouchers[0], vouchers[1]
+		require.NoError(t, htlc.VerifyHop(in, out, 10))
+
+		overpaid := *out
+		overpaid.Amount = big.NewInt(13)
+		assert.Error(t, htlc.VerifyHop(in, &overpaid, 10))
+
+		otherHash := *out
+		otherHash.SecretPreimage = make([]byte, 32)
+		assert.Error(t, htlc.VerifyHop(in, &otherHash, 10))
+
+		noDeadline := *out
+		noDeadline.TimeLockMax = 0
+		assert.Error(t, htlc.VerifyHop(in, &noDeadline, 10))
+
+		lateDeadline := *out
+		lateDeadline.TimeLockMax = in.TimeLockMax
+		assert.Error(t, htlc.VerifyHop(in, &lateDeadline, 10))
+	})
+}
@@ -2,21 +2,22 @@ package paych
 
 import (
 	"bytes"
-	"sort"
+	"math"
 
 	addr "github.com/filecoin-project/go-address"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
 	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/builtin/account"
 	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
 	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
 	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
 )
 
-// Maximum number of lanes in a channel.
-const LaneLimit = 256
+// Maximum lane ID, limited by the index range of the lane AMT.
+const MaxLane = math.MaxInt64
 
 const SettleDelay = builtin.EpochsInHour * 12
 
@@ -28,6 +29,8 @@ func (a Actor) Exports() []interface{} {
 		2:                         a.UpdateChannelState,
 		3:                         a.Settle,
 		4:                         a.Collect,
+		5:                         a.RegisterHTLC,
+		6:                         a.WithdrawRedeemed,
 	}
 }
 
@@ -50,15 +53,19 @@ func (pca *Actor) Constructor(rt vmr.Runtime, params *ConstructorParams) *adt.Em
 	from, err := pca.resolveAccount(rt, params.From)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve from address: %s", params.From)
 
-	st := ConstructState(from, to)
+	emptyArrCid, err := adt.MakeEmptyArray(adt.AsStore(rt)).Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create empty array")
+
+	st := ConstructState(from, to, emptyArrCid)
 	rt.State().Create(st)
 
 	return nil
 }
 
-// Resolves an address to a canonical ID address and requires it to address an account actor.
+// Resolves an address to a canonical ID address and requires it to address an account or multi-key account actor.
 // The account actor constructor checks that the embedded address is associated with an appropriate key.
 // An alternative (more expensive) would be to send a message to the actor to fetch its key.
+// A multi-key account signs vouchers with a threshold of its keys, which it verifies itself.
 func (pca *Actor) resolveAccount(rt vmr.Runtime, raw addr.Address) (addr.Address, error) {
 	resolved, ok := rt.ResolveAddress(raw)
 	if !ok {
@@ -69,9 +76,9 @@ func (pca *Actor) resolveAccount(rt vmr.Runtime, raw addr.Address) (addr.Address
 	if !ok {
 		return addr.Undef, exitcode.ErrForbidden.Wrapf("no code for address %v", resolved)
 	}
-	if codeCID != builtin.AccountActorCodeID {
-		return addr.Undef, exitcode.ErrForbidden.Wrapf("actor %v must be an account (%v), was %v", raw,
-			builtin.AccountActorCodeID, codeCID)
+	if codeCID != builtin.AccountActorCodeID && codeCID != builtin.MultiKeyAccountActorCodeID {
+		return addr.Undef, exitcode.ErrForbidden.Wrapf("actor %v must be an account (%v) or multi-key account (%v), was %v",
+			raw, builtin.AccountActorCodeID, builtin.MultiKeyAccountActorCodeID, codeCID)
 	}
 	return resolved, nil
 }
@@ -128,53 +135,19 @@ type PaymentVerifyParams struct {
 	Proof []byte
 }
 
+type RegisterHTLCParams struct {
+	Sv SignedVoucher
+}
+
 func (pca Actor) UpdateChannelState(rt vmr.Runtime, params *UpdateChannelStateParams) *adt.EmptyValue {
 	var st State
 	rt.State().Readonly(&st)
 
-	// both parties must sign voucher: one who submits it, the other explicitly signs it
-	rt.ValidateImmediateCallerIs(st.From, st.To)
-	var signer addr.Address
-	if rt.Message().Caller() == st.From {
-		signer = st.To
-	} else {
-		signer = st.From
-	}
 	sv := params.Sv
+	pca.validateVoucherSignature(rt, &st, &sv)
 
-	if sv.Signature == nil {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher has no signature")
-	}
-
-	vb, err := sv.SigningBytes()
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to serialize signedvoucher")
-
-	err = rt.Syscalls().VerifySignature(*sv.Signature, signer, vb)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "voucher signature invalid")
-
-	pchAddr := rt.Message().Receiver()
-	if pchAddr != sv.ChannelAddr {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher payment channel address %s does not match receiver %s", sv.ChannelAddr, pchAddr)
-	}
-
-	if rt.CurrEpoch() < sv.TimeLockMin {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot use this voucher yet!")
-	}
-
-	if sv.TimeLockMax != 0 && rt.CurrEpoch() > sv.TimeLockMax {
-		rt.Abortf(exitcode.ErrIllegalArgument, "this voucher has expired!")
-	}
-
-	if sv.Amount.Sign() < 0 {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher amount must be non-negative, was %v", sv.Amount)
-	}
-
-	if len(sv.SecretPreimage) > 0 {
-		hashedSecret := rt.Syscalls().HashBlake2b(params.Secret)
-		if !bytes.Equal(hashedSecret[:], sv.SecretPreimage) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "incorrect secret!")
-		}
-	}
+	err := sv.CheckRedeemable(rt.Message().Receiver(), rt.CurrEpoch(), params.Secret, rt.Syscalls().HashBlake2b)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "voucher cannot be redeemed")
 
 	if sv.Extra != nil {
 
@@ -191,83 +164,101 @@ func (pca Actor) UpdateChannelState(rt vmr.Runtime, params *UpdateChannelStatePa
 	}
 
 	rt.State().Transaction(&st, func() {
-		laneFound := true
-		// Find the voucher lane, create and insert it in sorted order if necessary.
-		laneIdx, ls := findLane(st.LaneStates, sv.Lane)
-		if ls == nil {
-			if len(st.LaneStates) >= LaneLimit {
-				rt.Abortf(exitcode.ErrIllegalArgument, "lane limit exceeded")
-			}
-			ls = &LaneState{
-				ID:       sv.Lane,
-				Redeemed: big.Zero(),
-				Nonce:    0,
-			}
-			st.LaneStates = append(st.LaneStates[:laneIdx], append([]*LaneState{ls}, st.LaneStates[laneIdx:]...)...)
-			laneFound = false
-		}
+		lanes, err := adt.AsArray(adt.AsStore(rt), st.LaneStates)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
 
-		if laneFound {
-			if ls.Nonce >= sv.Nonce {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher has an outdated nonce, existing nonce: %d, voucher nonce: %d, cannot redeem",
-					ls.Nonce, sv.Nonce)
-			}
-		}
+		st.ToSend, err = RedeemVoucher(&laneArray{lanes}, st.ToSend, st.PaidOut, rt.CurrentBalance(), &sv)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to redeem voucher")
 
-		// The next section actually calculates the payment amounts to update the payment channel state
-		// 1. (optional) sum already redeemed value of all merging lanes
-		redeemedFromOthers := big.Zero()
-		for _, merge := range sv.Merges {
-			if merge.Lane == sv.Lane {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher cannot merge lanes into its own lane")
-			}
-
-			_, otherls := findLane(st.LaneStates, merge.Lane)
-			if otherls != nil {
-				if otherls.Nonce >= merge.Nonce {
-					rt.Abortf(exitcode.ErrIllegalArgument, "merged lane in voucher has outdated nonce, cannot redeem")
-				}
-
-				redeemedFromOthers = big.Add(redeemedFromOthers, otherls.Redeemed)
-				otherls.Nonce = merge.Nonce
-			} else {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher specifies invalid merge lane %v", merge.Lane)
-			}
+		st.LaneStates, err = lanes.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush lanes")
+
+		// update channel settlingAt and MinSettleHeight if delayed by voucher
+		if sv.MinSettleHeight != 0 {
+			st.delaySettlement(sv.MinSettleHeight)
 		}
+	})
+	return nil
+}
 
-		// 2. To prevent double counting, remove already redeemed amounts (from
-		// voucher or other lanes) from the voucher amount
-		ls.Nonce = sv.Nonce
-		balanceDelta := big.Sub(sv.Amount, big.Add(redeemedFromOthers, ls.Redeemed))
-		// 3. set new redeemed value for merged-into lane
-		ls.Redeemed = sv.Amount
+// RegisterHTLC records a hash-time-locked voucher (one with both a SecretPreimage and a TimeLockMax) without
+// redeeming it, so that the channel cannot be collected until the voucher's deadline has passed.
+// The holder of such a voucher may learn its secret only shortly before the deadline, and would otherwise lose the
+// payment if the other party settled the channel in the meantime.
+// Until the deadline the voucher may be redeemed with its secret by UpdateChannelState. After the deadline it can
+// no longer be redeemed, and its amount is refunded to From when the channel is collected.
+func (pca Actor) RegisterHTLC(rt vmr.Runtime, params *RegisterHTLCParams) *adt.EmptyValue {
+	var st State
+	rt.State().Readonly(&st)
 
-		newSendBalance := big.Add(st.ToSend, balanceDelta)
+	sv := params.Sv
+	pca.validateVoucherSignature(rt, &st, &sv)
 
-		// 4. check operation validity
-		if newSendBalance.LessThan(big.Zero()) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "voucher would leave channel balance negative")
-		}
-		if newSendBalance.GreaterThan(rt.CurrentBalance()) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "not enough funds in channel to cover voucher")
-		}
+	if rt.Message().Receiver() != sv.ChannelAddr {
+		rt.Abortf(exitcode.ErrIllegalArgument, "voucher payment channel address %s does not match receiver %s", sv.ChannelAddr, rt.Message().Receiver())
+	}
+	if !sv.HashTimeLocked() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "voucher must have both a secret preimage and a time lock max")
+	}
+	if rt.CurrEpoch() > sv.TimeLockMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "this voucher has expired!")
+	}
 
-		// 5. add new redemption ToSend
-		st.ToSend = newSendBalance
+	rt.State().Transaction(&st, func() {
+		lanes, err := adt.AsArray(adt.AsStore(rt), st.LaneStates)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
 
-		// update channel settlingAt and MinSettleHeight if delayed by voucher
-		if sv.MinSettleHeight != 0 {
-			if st.SettlingAt != 0 && st.SettlingAt < sv.MinSettleHeight {
-				st.SettlingAt = sv.MinSettleHeight
-			}
-			if st.MinSettleHeight < sv.MinSettleHeight {
-				st.MinSettleHeight = sv.MinSettleHeight
-			}
-		}
+		// a voucher that could not be redeemed for a stale nonce may not delay settlement
+		err = CheckVoucherLanes(&laneArray{lanes}, &sv)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "voucher cannot be redeemed")
+
+		// the voucher is redeemable up to and including TimeLockMax, so the channel may be collected only after it
+		st.delaySettlement(sv.TimeLockMax + 1)
 	})
 	return nil
 }
 
+// Checks that the caller is a party to the channel and the voucher is signed by the other party.
+// The voucher of a multi-key account party carries an account.ThresholdSignature as its signature data, which the
+// account verifies.
+func (pca Actor) validateVoucherSignature(rt vmr.Runtime, st *State, sv *SignedVoucher) {
+	// both parties must sign voucher: one who submits it, the other explicitly signs it
+	rt.ValidateImmediateCallerIs(st.From, st.To)
+	var signer addr.Address
+	if rt.Message().Caller() == st.From {
+		signer = st.To
+	} else {
+		signer = st.From
+	}
+
+	if sv.Signature == nil {
+		rt.Abortf(exitcode.ErrIllegalArgument, "voucher has no signature")
+	}
+
+	vb, err := sv.SigningBytes()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to serialize signedvoucher")
+
+	if codeCID, ok := rt.GetActorCodeCID(signer); ok && codeCID == builtin.MultiKeyAccountActorCodeID {
+		var sig account.ThresholdSignature
+		err = sig.UnmarshalCBOR(bytes.NewReader(sv.Signature.Data))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to decode voucher threshold signature")
+
+		_, code := rt.Send(
+			signer,
+			builtin.MethodsMultiKeyAccount.VerifyThresholdSignature,
+			&account.VerifyThresholdSignatureParams{Plaintext: vb, Signatures: sig.Signatures},
+			abi.NewTokenAmount(0),
+		)
+		if !code.IsSuccess() {
+			rt.Abortf(exitcode.ErrIllegalArgument, "voucher threshold signature invalid, exit code %v", code)
+		}
+		return
+	}
+
+	err = rt.Syscalls().VerifySignature(*sv.Signature, signer, vb)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "voucher signature invalid")
+}
+
 func (pca Actor) Settle(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
 	var st State
 	rt.State().Transaction(&st, func() {
@@ -294,12 +285,12 @@ func (pca Actor) Collect(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
 		rt.Abortf(exitcode.ErrForbidden, "payment channel not settling or settled")
 	}
 
-	// send ToSend to "To"
+	// send ToSend to "To", less any amount already withdrawn
 	_, codeTo := rt.Send(
 		st.To,
 		builtin.MethodSend,
 		nil,
-		st.ToSend,
+		st.Unpaid(),
 	)
 	builtin.RequireSuccess(rt, codeTo, "Failed to send funds to `To`")
 
@@ -309,6 +300,27 @@ func (pca Actor) Collect(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
 	return nil
 }
 
+// WithdrawRedeemed pays To the amount redeemed by vouchers but not yet paid out, without settling the channel.
+// The channel remains open: later vouchers continue to redeem cumulative amounts, of which only the difference
+// from the amount already paid out is paid by a later withdrawal or by Collect.
+func (pca Actor) WithdrawRedeemed(rt vmr.Runtime, _ *adt.EmptyValue) *adt.EmptyValue {
+	var st State
+	var amount abi.TokenAmount
+	rt.State().Transaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.To)
+
+		amount = st.Unpaid()
+		st.PaidOut = st.ToSend
+	})
+
+	if amount.IsZero() {
+		return nil
+	}
+	_, code := rt.Send(st.To, builtin.MethodSend, nil, amount)
+	builtin.RequireSuccess(rt, code, "failed to send funds to `To`")
+	return nil
+}
+
 func (t *SignedVoucher) SigningBytes() ([]byte, error) {
 	osv := *t
 	osv.Signature = nil
@@ -321,14 +333,70 @@ func (t *SignedVoucher) SigningBytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Returns the insertion index for a lane ID, with the matching lane state if found, or nil.
-func findLane(lanes []*LaneState, ID uint64) (int, *LaneState) {
-	insertionIdx := sort.Search(len(lanes), func(i int) bool {
-		return lanes[i].ID >= ID
-	})
-	if insertionIdx == len(lanes) || lanes[insertionIdx].ID != uint64(insertionIdx) {
-		// Not found
-		return insertionIdx, nil
+// HashTimeLocked returns whether a voucher is redeemable only with a secret and only up to a deadline.
+func (t *SignedVoucher) HashTimeLocked() bool {
+	return len(t.SecretPreimage) > 0 && t.TimeLockMax != 0
+}
+
+// Checks the properties of a voucher that do not depend on channel state: that it is for a channel,
+// that its time locks admit redemption at an epoch, that its amount and lane are valid, and that the
+// secret (if required) matches its preimage.
+func (t *SignedVoucher) CheckRedeemable(channel addr.Address, epoch abi.ChainEpoch, secret []byte, hash func([]byte) [32]byte) error {
+	if channel != t.ChannelAddr {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher payment channel address %s does not match receiver %s", t.ChannelAddr, channel)
+	}
+
+	if epoch < t.TimeLockMin {
+		return exitcode.ErrIllegalArgument.Wrapf("cannot use this voucher yet!")
+	}
+
+	if t.TimeLockMax != 0 && epoch > t.TimeLockMax {
+		return exitcode.ErrIllegalArgument.Wrapf("this voucher has expired!")
+	}
+
+	if t.Amount.Sign() < 0 {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher amount must be non-negative, was %v", t.Amount)
+	}
+
+	if t.Lane > MaxLane {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher lane %d exceeds maximum %d", t.Lane, MaxLane)
+	}
+
+	if len(t.SecretPreimage) > 0 {
+		hashedSecret := hash(secret)
+		if !bytes.Equal(hashedSecret[:], t.SecretPreimage) {
+			return exitcode.ErrIllegalArgument.Wrapf("incorrect secret!")
+		}
+	}
+	return nil
+}
+
+// Provides the LaneStore interface over the lane AMT.
+type laneArray struct {
+	*adt.Array
+}
+
+func (a *laneArray) Lane(ID uint64) (*LaneState, error) {
+	return findLane(a.Array, ID)
+}
+
+func (a *laneArray) SetLane(ID uint64, ls *LaneState) error {
+	return a.Set(ID, ls)
+}
+
+// Returns the lane state for a lane ID, or nil if the lane does not exist.
+func findLane(lanes *adt.Array, ID uint64) (*LaneState, error) {
+	if ID > MaxLane {
+		return nil, exitcode.ErrIllegalArgument.Wrapf("lane ID %d exceeds maximum %d", ID, MaxLane)
+	}
+
+	var ls LaneState
+	found, err := lanes.Get(ID, &ls)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
 	}
-	return insertionIdx, lanes[insertionIdx]
+	return &ls, nil
 }
@@ -0,0 +1,70 @@
+package paych
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// The state of a payment channel created before lanes were stored in an AMT.
+// Lanes were held inline, in ID order.
+type LegacyState struct {
+	From            addr.Address
+	To              addr.Address
+	ToSend          abi.TokenAmount
+	SettlingAt      abi.ChainEpoch
+	MinSettleHeight abi.ChainEpoch
+	LaneStates      []*LegacyLaneState
+}
+
+// A lane of a LegacyState, which records its own ID.
+type LegacyLaneState struct {
+	ID       uint64
+	Redeemed big.Int
+	Nonce    uint64
+}
+
+// MigrateLegacyState converts a legacy channel state to the current layout, writing its lanes to a new AMT in store.
+func MigrateLegacyState(store adt.Store, old *LegacyState) (*State, error) {
+	lanes := adt.MakeEmptyArray(store)
+	for _, ls := range old.LaneStates {
+		if ls.ID > MaxLane {
+			return nil, xerrors.Errorf("lane ID %d exceeds maximum %d", ls.ID, MaxLane)
+		}
+		if err := lanes.Set(ls.ID, &LaneState{Redeemed: ls.Redeemed, Nonce: ls.Nonce}); err != nil {
+			return nil, xerrors.Errorf("failed to store lane %d: %w", ls.ID, err)
+		}
+	}
+	lanesRoot, err := lanes.Root()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to flush lanes: %w", err)
+	}
+
+	st := ConstructState(old.From, old.To, lanesRoot)
+	st.ToSend = old.ToSend
+	st.SettlingAt = old.SettlingAt
+	st.MinSettleHeight = old.MinSettleHeight
+	return st, nil
+}
+
+// MigrateLegacyStateRoot loads a legacy channel state from store, migrates it, and returns the root of the
+// migrated state.
+func MigrateLegacyStateRoot(store adt.Store, legacyRoot cid.Cid) (cid.Cid, error) {
+	var old LegacyState
+	if err := store.Get(store.Context(), legacyRoot, &old); err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load legacy state %v: %w", legacyRoot, err)
+	}
+	st, err := MigrateLegacyState(store, &old)
+	if err != nil {
+		return cid.Undef, err
+	}
+	root, err := store.Put(store.Context(), st)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to store migrated state: %w", err)
+	}
+	return root, nil
+}
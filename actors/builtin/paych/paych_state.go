@@ -2,9 +2,12 @@ package paych
 
 import (
 	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 )
 
 // A given payment channel actor is established by From
@@ -16,22 +19,38 @@ type State struct {
 	// Recipient of payouts from channel
 	To addr.Address
 
-	// Amount successfully redeemed through the payment channel, paid out on `Collect()`
+	// Amount successfully redeemed through the payment channel, paid out by `WithdrawRedeemed()` and `Collect()`
 	ToSend abi.TokenAmount
+	// Amount of ToSend already paid out to To by `WithdrawRedeemed()`
+	PaidOut abi.TokenAmount
 
 	// Height at which the channel can be `Collected`
 	SettlingAt abi.ChainEpoch
 	// Height before which the channel `ToSend` cannot be collected
 	MinSettleHeight abi.ChainEpoch
 
-	// Collections of lane states for the channel, maintained in ID order.
-	LaneStates []*LaneState
+	// Lane states for the channel, an AMT[LaneState] keyed by lane ID.
+	LaneStates cid.Cid
+}
+
+// The amount redeemed but not yet paid out to To.
+func (st *State) Unpaid() abi.TokenAmount {
+	return big.Sub(st.ToSend, st.PaidOut)
+}
+
+// Delays the earliest epoch at which the channel can be collected to at least height.
+func (st *State) delaySettlement(height abi.ChainEpoch) {
+	if st.SettlingAt != 0 && st.SettlingAt < height {
+		st.SettlingAt = height
+	}
+	if st.MinSettleHeight < height {
+		st.MinSettleHeight = height
+	}
 }
 
 // The Lane state tracks the latest (highest) voucher nonce used to merge the lane
 // as well as the amount it has already redeemed.
 type LaneState struct {
-	ID       uint64 // Unique to this channel
 	Redeemed big.Int
 	Nonce    uint64
 }
@@ -42,13 +61,124 @@ type Merge struct {
 	Nonce uint64
 }
 
-func ConstructState(from addr.Address, to addr.Address) *State {
+// Provides access to the lane states of a channel.
+type LaneStore interface {
+	// Returns the lane state for a lane ID, or nil if the lane does not exist.
+	// The returned state may be modified without affecting the store.
+	Lane(ID uint64) (*LaneState, error)
+	SetLane(ID uint64, ls *LaneState) error
+}
+
+// RedeemVoucher updates lanes to reflect the redemption of a voucher, including its lane merges, and returns the
+// channel's new ToSend amount given the current amount, the amount already paid out and the channel's balance.
+// The new amount may not fall below the amount already paid out, nor exceed the balance plus the amount paid out.
+// Violations of the voucher rules are reported as errors with exit code ErrIllegalArgument.
+// Lanes may be partially updated if an error is returned.
+func RedeemVoucher(lanes LaneStore, toSend, paidOut, balance abi.TokenAmount, sv *SignedVoucher) (abi.TokenAmount, error) {
+	if err := CheckVoucherLanes(lanes, sv); err != nil {
+		return big.Zero(), err
+	}
+
+	// Find the voucher lane, create it if necessary.
+	ls, err := lanes.Lane(sv.Lane)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to load lane %d: %w", sv.Lane, err)
+	}
+	if ls == nil {
+		ls = &LaneState{
+			Redeemed: big.Zero(),
+			Nonce:    0,
+		}
+	}
+
+	// The next section actually calculates the payment amounts to update the payment channel state
+	// 1. (optional) sum already redeemed value of all merging lanes
+	redeemedFromOthers := big.Zero()
+	for _, merge := range sv.Merges {
+		otherls, err := lanes.Lane(merge.Lane)
+		if err != nil {
+			return big.Zero(), xerrors.Errorf("failed to load lane %d: %w", merge.Lane, err)
+		}
+
+		redeemedFromOthers = big.Add(redeemedFromOthers, otherls.Redeemed)
+		otherls.Nonce = merge.Nonce
+		if err := lanes.SetLane(merge.Lane, otherls); err != nil {
+			return big.Zero(), xerrors.Errorf("failed to store lane %d: %w", merge.Lane, err)
+		}
+	}
+
+	// 2. To prevent double counting, remove already redeemed amounts (from
+	// voucher or other lanes) from the voucher amount
+	ls.Nonce = sv.Nonce
+	balanceDelta := big.Sub(sv.Amount, big.Add(redeemedFromOthers, ls.Redeemed))
+	// 3. set new redeemed value for merged-into lane
+	ls.Redeemed = sv.Amount
+
+	newSendBalance := big.Add(toSend, balanceDelta)
+
+	// 4. check operation validity
+	if newSendBalance.LessThan(big.Zero()) {
+		return big.Zero(), exitcode.ErrIllegalArgument.Wrapf("voucher would leave channel balance negative")
+	}
+	if newSendBalance.LessThan(paidOut) {
+		return big.Zero(), exitcode.ErrIllegalArgument.Wrapf("voucher would reduce redeemed amount %v below amount already paid out %v",
+			newSendBalance, paidOut)
+	}
+	if newSendBalance.GreaterThan(big.Add(balance, paidOut)) {
+		return big.Zero(), exitcode.ErrIllegalArgument.Wrapf("not enough funds in channel to cover voucher")
+	}
+
+	if err := lanes.SetLane(sv.Lane, ls); err != nil {
+		return big.Zero(), xerrors.Errorf("failed to store lane %d: %w", sv.Lane, err)
+	}
+	return newSendBalance, nil
+}
+
+// CheckVoucherLanes checks that a voucher's nonce is newer than that of its lane, and that each lane it merges
+// exists, is not its own lane or merged twice, and is merged with a newer nonce.
+// Violations are reported as errors with exit code ErrIllegalArgument.
+func CheckVoucherLanes(lanes LaneStore, sv *SignedVoucher) error {
+	ls, err := lanes.Lane(sv.Lane)
+	if err != nil {
+		return xerrors.Errorf("failed to load lane %d: %w", sv.Lane, err)
+	}
+	if ls != nil && ls.Nonce >= sv.Nonce {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher has an outdated nonce, existing nonce: %d, voucher nonce: %d, cannot redeem",
+			ls.Nonce, sv.Nonce)
+	}
+
+	merged := make(map[uint64]struct{}, len(sv.Merges))
+	for _, merge := range sv.Merges {
+		if merge.Lane == sv.Lane {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher cannot merge lanes into its own lane")
+		}
+		if _, ok := merged[merge.Lane]; ok {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher merges lane %d more than once", merge.Lane)
+		}
+		merged[merge.Lane] = struct{}{}
+
+		otherls, err := lanes.Lane(merge.Lane)
+		if err != nil {
+			return xerrors.Errorf("failed to load lane %d: %w", merge.Lane, err)
+		}
+		if otherls == nil {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher specifies invalid merge lane %v", merge.Lane)
+		}
+		if otherls.Nonce >= merge.Nonce {
+			return exitcode.ErrIllegalArgument.Wrapf("merged lane in voucher has outdated nonce, cannot redeem")
+		}
+	}
+	return nil
+}
+
+func ConstructState(from addr.Address, to addr.Address, emptyArrCid cid.Cid) *State {
 	return &State{
 		From:            from,
 		To:              to,
 		ToSend:          big.Zero(),
+		PaidOut:         big.Zero(),
 		SettlingAt:      0,
 		MinSettleHeight: 0,
-		LaneStates:      []*LaneState{},
+		LaneStates:      emptyArrCid,
 	}
 }
@@ -1,6 +1,7 @@
 package paych_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -15,10 +16,13 @@ import (
 	"github.com/filecoin-project/specs-actors/actors/abi"
 	"github.com/filecoin-project/specs-actors/actors/abi/big"
 	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/builtin/account"
 	. "github.com/filecoin-project/specs-actors/actors/builtin/paych"
 	"github.com/filecoin-project/specs-actors/actors/crypto"
 	"github.com/filecoin-project/specs-actors/actors/runtime"
 	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
 	"github.com/filecoin-project/specs-actors/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/support/testing"
 )
@@ -45,6 +49,15 @@ func TestPaymentChannelActor_Constructor(t *testing.T) {
 		actor.constructAndVerify(t, rt, payerAddr, payeeAddr)
 	})
 
+	t.Run("can create a payment channel with a multi-key account party", func(t *testing.T) {
+		builder := mock.NewBuilder(ctx, paychAddr).
+			WithCaller(callerAddr, builtin.InitActorCodeID).
+			WithActorType(payerAddr, builtin.MultiKeyAccountActorCodeID).
+			WithActorType(payeeAddr, builtin.AccountActorCodeID)
+		rt := builder.Build(t)
+		actor.constructAndVerify(t, rt, payerAddr, payeeAddr)
+	})
+
 	nonAccountCodeID := builtin.MultisigActorCodeID
 	testCases := []struct {
 		desc        string
@@ -219,11 +232,12 @@ func TestPaymentChannelActor_CreateLane(t *testing.T) {
 				rt.Call(actor.UpdateChannelState, ucp)
 				var st State
 				rt.GetState(&st)
-				assert.Len(t, st.LaneStates, 1)
-				ls := st.LaneStates[0]
+				lanes := getLanes(t, rt, &st)
+				assert.Len(t, lanes, 1)
+				ls := lanes[sv.Lane]
+				require.NotNil(t, ls)
 				assert.Equal(t, sv.Amount, ls.Redeemed)
 				assert.Equal(t, sv.Nonce, ls.Nonce)
-				assert.Equal(t, sv.Lane, ls.ID)
 			} else {
 				rt.ExpectAbort(tc.expExitCode, func() {
 					rt.Call(actor.UpdateChannelState, ucp)
@@ -246,7 +260,6 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 		rt.GetState(&st1)
 
 		expLs := LaneState{
-			ID:       0,
 			Redeemed: newVoucherAmt,
 			Nonce:    2,
 		}
@@ -268,9 +281,8 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 			ToSend:          newVoucherAmt,
 			SettlingAt:      st1.SettlingAt,
 			MinSettleHeight: st1.MinSettleHeight,
-			LaneStates:      []*LaneState{&expLs},
 		}
-		verifyState(t, rt, 1, expState)
+		verifyState(t, rt, expState, map[uint64]*LaneState{0: &expLs})
 	})
 
 	t.Run("redeems voucher for correct lane", func(t *testing.T) {
@@ -283,7 +295,7 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 		ucp := &UpdateChannelStateParams{Sv: *sv}
 		ucp.Sv.Amount = newVoucherAmt
 		ucp.Sv.Lane = 1
-		lsToUpdate := st1.LaneStates[ucp.Sv.Lane]
+		lsToUpdate := getLanes(t, rt, &st1)[ucp.Sv.Lane]
 		ucp.Sv.Nonce = lsToUpdate.Nonce + 1
 
 		// Sending to same lane updates the lane with "new" state
@@ -295,7 +307,7 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 		rt.Verify()
 
 		rt.GetState(&st2)
-		lUpdated := st2.LaneStates[ucp.Sv.Lane]
+		lUpdated := getLanes(t, rt, &st2)[ucp.Sv.Lane]
 
 		bDelta := big.Sub(ucp.Sv.Amount, lsToUpdate.Redeemed)
 		expToSend := big.Add(initialAmt, bDelta)
@@ -327,6 +339,79 @@ func TestActor_UpdateChannelStateRedeem(t *testing.T) {
 	})
 }
 
+func TestActor_UpdateChannelStateMultiKey(t *testing.T) {
+	ctx := context.Background()
+	paychAddr := tutil.NewIDAddr(t, 100)
+	payerAddr := tutil.NewIDAddr(t, 102)
+	payeeAddr := tutil.NewIDAddr(t, 103)
+	actor := pcActorHarness{Actor{}, t, paychAddr, payerAddr, payeeAddr}
+
+	keySigs := []account.KeySignature{
+		{KeyIndex: 0, Signature: crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("zero")}},
+		{KeyIndex: 2, Signature: crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: []byte("two")}},
+	}
+	buf := new(bytes.Buffer)
+	require.NoError(t, (&account.ThresholdSignature{Signatures: keySigs}).MarshalCBOR(buf))
+	sv := SignedVoucher{
+		ChannelAddr: paychAddr,
+		Lane:        0,
+		Nonce:       1,
+		Amount:      big.NewInt(10),
+		Signature:   &crypto.Signature{Type: crypto.SigTypeBLS, Data: buf.Bytes()},
+	}
+
+	setup := func(t *testing.T) *mock.Runtime {
+		rt := mock.NewBuilder(ctx, paychAddr).
+			WithBalance(abi.NewTokenAmount(100), abi.NewTokenAmount(0)).
+			WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+			WithActorType(payerAddr, builtin.MultiKeyAccountActorCodeID).
+			WithActorType(payeeAddr, builtin.AccountActorCodeID).
+			Build(t)
+		actor.constructAndVerify(t, rt, payerAddr, payeeAddr)
+		return rt
+	}
+
+	t.Run("redeems a voucher signed by a threshold of the payer's keys", func(t *testing.T) {
+		rt := setup(t)
+		rt.SetCaller(payeeAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(payerAddr, payeeAddr)
+		rt.ExpectSend(payerAddr, builtin.MethodsMultiKeyAccount.VerifyThresholdSignature,
+			&account.VerifyThresholdSignatureParams{Plaintext: voucherBytes(t, &sv), Signatures: keySigs},
+			big.Zero(), nil, exitcode.Ok)
+		rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: sv})
+		rt.Verify()
+
+		var st State
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(10), st.ToSend)
+	})
+
+	t.Run("fails if the payer rejects the threshold signature", func(t *testing.T) {
+		rt := setup(t)
+		rt.SetCaller(payeeAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(payerAddr, payeeAddr)
+		rt.ExpectSend(payerAddr, builtin.MethodsMultiKeyAccount.VerifyThresholdSignature,
+			&account.VerifyThresholdSignatureParams{Plaintext: voucherBytes(t, &sv), Signatures: keySigs},
+			big.Zero(), nil, exitcode.ErrForbidden)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: sv})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails if the signature data is not a threshold signature", func(t *testing.T) {
+		rt := setup(t)
+		rt.SetCaller(payeeAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(payerAddr, payeeAddr)
+		bad := sv
+		bad.Signature = &crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte{0, 1, 2}}
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: bad})
+		})
+		rt.Verify()
+	})
+}
+
 func TestActor_UpdateChannelStateMergeSuccess(t *testing.T) {
 	// Check that a lane merge correctly updates lane states
 	numLanes := 3
@@ -336,14 +421,16 @@ func TestActor_UpdateChannelStateMergeSuccess(t *testing.T) {
 	rt.GetState(&st1)
 	rt.SetCaller(st1.From, builtin.AccountActorCodeID)
 
-	mergeTo := st1.LaneStates[0]
-	mergeFrom := st1.LaneStates[1]
+	lanes1 := getLanes(t, rt, &st1)
+	mergeToID, mergeFromID := uint64(0), uint64(1)
+	mergeTo := lanes1[mergeToID]
+	mergeFrom := lanes1[mergeFromID]
 
 	// Note sv.Amount = 4
-	sv.Lane = mergeTo.ID
+	sv.Lane = mergeToID
 	mergeNonce := mergeTo.Nonce + 10
 
-	merges := []Merge{{Lane: mergeFrom.ID, Nonce: mergeNonce}}
+	merges := []Merge{{Lane: mergeFromID, Nonce: mergeNonce}}
 	sv.Merges = merges
 
 	ucp := &UpdateChannelStateParams{Sv: *sv}
@@ -353,8 +440,8 @@ func TestActor_UpdateChannelStateMergeSuccess(t *testing.T) {
 	require.Nil(t, ret)
 	rt.Verify()
 
-	expMergeTo := LaneState{ID: mergeTo.ID, Redeemed: sv.Amount, Nonce: sv.Nonce}
-	expMergeFrom := LaneState{ID: mergeFrom.ID, Redeemed: mergeFrom.Redeemed, Nonce: mergeNonce}
+	expMergeTo := LaneState{Redeemed: sv.Amount, Nonce: sv.Nonce}
+	expMergeFrom := LaneState{Redeemed: mergeFrom.Redeemed, Nonce: mergeNonce}
 
 	// calculate ToSend amount
 	redeemed := big.Add(mergeFrom.Redeemed, mergeTo.Redeemed)
@@ -364,8 +451,11 @@ func TestActor_UpdateChannelStateMergeSuccess(t *testing.T) {
 	// last lane should be unchanged
 	expState := st1
 	expState.ToSend = expSendAmt
-	expState.LaneStates = []*LaneState{&expMergeTo, &expMergeFrom, st1.LaneStates[2]}
-	verifyState(t, rt, numLanes, expState)
+	verifyState(t, rt, expState, map[uint64]*LaneState{
+		mergeToID:   &expMergeTo,
+		mergeFromID: &expMergeFrom,
+		2:           lanes1[2],
+	})
 }
 
 func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
@@ -406,12 +496,9 @@ func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
 
 			var st1 State
 			rt.GetState(&st1)
-			mergeTo := st1.LaneStates[0]
-			mergeFrom := st1.LaneStates[tc.lane]
-
-			sv.Lane = mergeTo.ID
+			sv.Lane = 0
 			sv.Nonce = tc.voucherNonce
-			merges := []Merge{{Lane: mergeFrom.ID, Nonce: tc.mergeNonce}}
+			merges := []Merge{{Lane: tc.lane, Nonce: tc.mergeNonce}}
 			sv.Merges = merges
 			ucp := &UpdateChannelStateParams{Sv: *sv}
 
@@ -430,12 +517,9 @@ func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
 
 		var st1 State
 		rt.GetState(&st1)
-		mergeTo := st1.LaneStates[0]
-		mergeFrom := LaneState{ID: 999, Nonce: 2}
-
-		sv.Lane = mergeTo.ID
+		sv.Lane = 0
 		sv.Nonce = 10
-		merges := []Merge{{Lane: mergeFrom.ID, Nonce: sv.Nonce}}
+		merges := []Merge{{Lane: 999, Nonce: sv.Nonce}}
 		sv.Merges = merges
 		ucp := &UpdateChannelStateParams{Sv: *sv}
 
@@ -448,12 +532,12 @@ func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
 		rt.Verify()
 	})
 
-	t.Run("Too many lanes, fails with: lane limit exceeded", func(t *testing.T) {
-		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), LaneLimit)
+	t.Run("Lane ID too large, fails with: voucher lane exceeds maximum", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
 
 		var st1 State
 		rt.GetState(&st1)
-		sv.Lane++
+		sv.Lane = MaxLane + 1
 		sv.Nonce++
 		sv.Amount = abi.NewTokenAmount(100)
 		ucp := &UpdateChannelStateParams{Sv: *sv}
@@ -467,6 +551,67 @@ func TestActor_UpdateChannelStateMergeFailure(t *testing.T) {
 	})
 }
 
+func TestActor_UpdateChannelStateSparseLanes(t *testing.T) {
+	rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
+	bigLane := uint64(1) << 40
+
+	requireAddNewLane(t, rt, actor, laneParams{
+		epochNum: 2,
+		from:     actor.payer,
+		to:       actor.payee,
+		amt:      big.NewInt(10),
+		lane:     bigLane,
+		nonce:    1,
+	})
+
+	var st State
+	rt.GetState(&st)
+	verifyState(t, rt, State{From: st.From, To: st.To, ToSend: big.NewInt(11)}, map[uint64]*LaneState{
+		0:       {Redeemed: big.NewInt(1), Nonce: 1},
+		bigLane: {Redeemed: big.NewInt(10), Nonce: 1},
+	})
+}
+
+func TestMigrateLegacyState(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	from := tutil.NewIDAddr(t, 101)
+	to := tutil.NewIDAddr(t, 102)
+
+	legacy := LegacyState{
+		From:            from,
+		To:              to,
+		ToSend:          big.NewInt(15),
+		SettlingAt:      100,
+		MinSettleHeight: 50,
+		LaneStates: []*LegacyLaneState{
+			{ID: 0, Redeemed: big.NewInt(5), Nonce: 2},
+			{ID: 7, Redeemed: big.NewInt(10), Nonce: 1},
+		},
+	}
+	legacyRoot, err := store.Put(context.Background(), &legacy)
+	require.NoError(t, err)
+
+	root, err := MigrateLegacyStateRoot(store, legacyRoot)
+	require.NoError(t, err)
+
+	var st State
+	require.NoError(t, store.Get(context.Background(), root, &st))
+	assert.Equal(t, from, st.From)
+	assert.Equal(t, to, st.To)
+	assert.Equal(t, big.NewInt(15), st.ToSend)
+	assert.Equal(t, abi.ChainEpoch(100), st.SettlingAt)
+	assert.Equal(t, abi.ChainEpoch(50), st.MinSettleHeight)
+
+	lanes, err := adt.AsArray(store, st.LaneStates)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), lanes.Length())
+	var ls LaneState
+	found, err := lanes.Get(7, &ls)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, LaneState{Redeemed: big.NewInt(10), Nonce: 1}, ls)
+}
+
 func TestActor_UpdateChannelStateExtra(t *testing.T) {
 	mnum := builtin.MethodsPaych.UpdateChannelState
 	fakeParams := runtime.CBORBytes([]byte{1, 2, 3, 4})
@@ -608,6 +753,119 @@ func TestActor_UpdateChannelStateSecretPreimage(t *testing.T) {
 	})
 }
 
+func TestActor_RegisterHTLC(t *testing.T) {
+	// the test hasher hashes every secret to zero
+	preimage := make([]byte, 32)
+
+	t.Run("Delays settlement until the voucher deadline", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+		ep := abi.ChainEpoch(10)
+		rt.SetEpoch(ep)
+		var st State
+		rt.GetState(&st)
+
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.Call(actor.Settle, nil)
+		rt.GetState(&st)
+		require.Equal(t, ep+SettleDelay, st.SettlingAt)
+
+		htlc := *sv
+		htlc.SecretPreimage = preimage
+		htlc.TimeLockMax = st.SettlingAt + 100
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*htlc.Signature, st.To, voucherBytes(t, &htlc), nil)
+		rt.Call(actor.RegisterHTLC, &RegisterHTLCParams{Sv: htlc})
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, htlc.TimeLockMax+1, st.SettlingAt)
+		assert.Equal(t, htlc.TimeLockMax+1, st.MinSettleHeight)
+
+		// the channel cannot be collected while the voucher can be redeemed
+		rt.SetEpoch(htlc.TimeLockMax)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.Collect, nil)
+		})
+
+		// the voucher cannot be redeemed after its deadline
+		rt.SetEpoch(htlc.TimeLockMax + 1)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*htlc.Signature, st.To, voucherBytes(t, &htlc), nil)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: htlc, Secret: []byte("secret")})
+		})
+
+		// when the channel is collected, the unredeemed voucher amount is refunded to From
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectSend(st.To, builtin.MethodSend, nil, st.ToSend, nil, exitcode.Ok)
+		rt.ExpectDeleteActor(st.From)
+		rt.Call(actor.Collect, nil)
+		rt.Verify()
+	})
+
+	t.Run("Voucher is redeemable with the secret before the deadline", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		htlc := *sv
+		htlc.SecretPreimage = preimage
+		htlc.TimeLockMax = 100
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*htlc.Signature, st.To, voucherBytes(t, &htlc), nil)
+		rt.Call(actor.RegisterHTLC, &RegisterHTLCParams{Sv: htlc})
+
+		rt.SetEpoch(htlc.TimeLockMax)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*htlc.Signature, st.To, voucherBytes(t, &htlc), nil)
+		rt.Call(actor.UpdateChannelState, &UpdateChannelStateParams{Sv: htlc, Secret: []byte("secret")})
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, htlc.Amount, st.ToSend)
+	})
+
+	testCases := []struct {
+		name           string
+		preimage       []byte
+		timeLockMax    abi.ChainEpoch
+		channelAddrOff bool
+		staleNonce     bool
+	}{
+		{name: "fails without secret preimage", timeLockMax: 100},
+		{name: "fails without deadline", preimage: preimage},
+		{name: "fails after deadline", preimage: preimage, timeLockMax: 5},
+		{name: "fails for another channel", preimage: preimage, timeLockMax: 100, channelAddrOff: true},
+		{name: "fails with stale nonce", preimage: preimage, timeLockMax: 100, staleNonce: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+			rt.SetEpoch(10)
+			var st State
+			rt.GetState(&st)
+
+			htlc := *sv
+			htlc.SecretPreimage = tc.preimage
+			htlc.TimeLockMax = tc.timeLockMax
+			if tc.channelAddrOff {
+				htlc.ChannelAddr = tutil.NewIDAddr(t, 999)
+			}
+			if tc.staleNonce {
+				// the lane has already redeemed a voucher with this nonce
+				htlc.Nonce--
+			}
+			rt.ExpectValidateCallerAddr(st.From, st.To)
+			rt.ExpectVerifySignature(*htlc.Signature, st.To, voucherBytes(t, &htlc), nil)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.RegisterHTLC, &RegisterHTLCParams{Sv: htlc})
+			})
+			rt.Verify()
+		})
+	}
+}
+
 func TestActor_Settle(t *testing.T) {
 	ep := abi.ChainEpoch(10)
 
@@ -674,6 +932,124 @@ func TestActor_Settle(t *testing.T) {
 	})
 }
 
+func TestActor_WithdrawRedeemed(t *testing.T) {
+	t.Run("Pays only the amount redeemed since the last withdrawal", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+		require.Equal(t, big.NewInt(1), st.ToSend)
+
+		actor.withdrawRedeemed(rt, st.ToSend)
+		rt.GetState(&st)
+		assert.Equal(t, st.ToSend, st.PaidOut)
+		assert.Equal(t, big.NewInt(99999), rt.Balance())
+
+		// nothing further to withdraw
+		actor.withdrawRedeemed(rt, big.Zero())
+
+		// a later voucher on the lane redeems its cumulative amount, of which only the difference is paid
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.NewInt(10)
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, st.To, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+		rt.Verify()
+
+		actor.withdrawRedeemed(rt, big.NewInt(9))
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(10), st.ToSend)
+		assert.Equal(t, big.NewInt(10), st.PaidOut)
+		assert.Equal(t, big.NewInt(99990), rt.Balance())
+
+		// Collect pays only the amount not yet withdrawn
+		ucp.Sv.Nonce++
+		ucp.Sv.Amount = big.NewInt(15)
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, st.To, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.Call(actor.Settle, nil)
+		rt.GetState(&st)
+		rt.SetEpoch(st.SettlingAt)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectSend(st.To, builtin.MethodSend, nil, big.NewInt(5), nil, exitcode.Ok)
+		rt.ExpectDeleteActor(st.From)
+		rt.Call(actor.Collect, nil)
+		rt.Verify()
+	})
+
+	t.Run("Vouchers may redeem funds added after a withdrawal", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetBalance(big.NewInt(10))
+		actor.withdrawRedeemed(rt, big.NewInt(1))
+		require.Equal(t, big.NewInt(9), rt.Balance())
+
+		// the withdrawn amount still counts towards the channel funds
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.NewInt(11)
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, st.To, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+
+		// From tops up the channel
+		rt.SetBalance(big.NewInt(10))
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, st.To, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+		rt.Verify()
+
+		actor.withdrawRedeemed(rt, big.NewInt(10))
+		assert.Equal(t, int64(0), rt.Balance().Int64())
+	})
+
+	t.Run("Vouchers may not reduce the redeemed amount below the amount paid out", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, context.Background(), 2)
+		var st State
+		rt.GetState(&st)
+		require.Equal(t, big.NewInt(3), st.ToSend)
+
+		actor.withdrawRedeemed(rt, big.NewInt(3))
+
+		// merging lane 0 into lane 1 for less than both lanes redeemed reduces ToSend below PaidOut
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.NewInt(2)
+		ucp.Sv.Merges = []Merge{{Lane: 0, Nonce: 2}}
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, st.To, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(3), st.ToSend)
+		assert.Equal(t, big.NewInt(3), st.PaidOut)
+	})
+
+	t.Run("Fails if caller is not To", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.To)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.WithdrawRedeemed, nil)
+		})
+		rt.Verify()
+	})
+}
+
 func TestActor_Collect(t *testing.T) {
 	t.Run("Happy path", func(t *testing.T) {
 		rt, actor, _ := requireCreateChannelWithLanes(t, context.Background(), 1)
@@ -813,6 +1189,16 @@ func requireAddNewLane(t *testing.T, rt *mock.Runtime, actor *pcActorHarness, pa
 	return &sv
 }
 
+func (h *pcActorHarness) withdrawRedeemed(rt *mock.Runtime, expectedAmount abi.TokenAmount) {
+	rt.SetCaller(h.payee, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payee)
+	if !expectedAmount.IsZero() {
+		rt.ExpectSend(h.payee, builtin.MethodSend, nil, expectedAmount, nil, exitcode.Ok)
+	}
+	rt.Call(h.Actor.WithdrawRedeemed, nil)
+	rt.Verify()
+}
+
 func (h *pcActorHarness) constructAndVerify(t *testing.T, rt *mock.Runtime, sender, receiver addr.Address) {
 	params := &ConstructorParams{To: receiver, From: sender}
 
@@ -827,10 +1213,10 @@ func verifyInitialState(t *testing.T, rt *mock.Runtime, sender, receiver addr.Ad
 	var st State
 	rt.GetState(&st)
 	expectedState := State{From: sender, To: receiver, ToSend: abi.NewTokenAmount(0)}
-	verifyState(t, rt, -1, expectedState)
+	verifyState(t, rt, expectedState, map[uint64]*LaneState{})
 }
 
-func verifyState(t *testing.T, rt *mock.Runtime, expLanes int, expectedState State) {
+func verifyState(t *testing.T, rt *mock.Runtime, expectedState State, expectedLanes map[uint64]*LaneState) {
 	var st State
 	rt.GetState(&st)
 	assert.Equal(t, expectedState.To, st.To)
@@ -838,12 +1224,23 @@ func verifyState(t *testing.T, rt *mock.Runtime, expLanes int, expectedState Sta
 	assert.Equal(t, expectedState.MinSettleHeight, st.MinSettleHeight)
 	assert.Equal(t, expectedState.SettlingAt, st.SettlingAt)
 	assert.Equal(t, expectedState.ToSend, st.ToSend)
-	if expLanes >= 0 {
-		require.Len(t, st.LaneStates, expLanes)
-		assert.True(t, reflect.DeepEqual(expectedState.LaneStates, st.LaneStates))
-	} else {
-		assert.Len(t, st.LaneStates, 0)
-	}
+	assert.True(t, reflect.DeepEqual(expectedLanes, getLanes(t, rt, &st)))
+}
+
+// Returns all of a channel's lanes, keyed by lane ID.
+func getLanes(t *testing.T, rt *mock.Runtime, st *State) map[uint64]*LaneState {
+	arr, err := adt.AsArray(adt.AsStore(rt), st.LaneStates)
+	require.NoError(t, err)
+
+	lanes := map[uint64]*LaneState{}
+	var ls LaneState
+	err = arr.ForEach(&ls, func(i int64) error {
+		copied := ls
+		lanes[uint64(i)] = &copied
+		return nil
+	})
+	require.NoError(t, err)
+	return lanes
 }
 
 func voucherBytes(t *testing.T, sv *SignedVoucher) []byte {
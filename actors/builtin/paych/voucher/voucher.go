@@ -0,0 +1,256 @@
+// Package voucher supports creating and checking payment channel vouchers off-chain.
+//
+// Vouchers are checked with the same rules the payment channel actor applies in UpdateChannelState,
+// against the channel's on-chain state and any vouchers accepted locally but not yet submitted.
+package voucher
+
+import (
+	"context"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/minio/blake2b-simd"
+	"golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin/paych"
+	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
+	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// Signs data with the key of an address.
+type Signer interface {
+	Sign(ctx context.Context, signer addr.Address, data []byte) (*crypto.Signature, error)
+}
+
+// Adapts a function to the Signer interface.
+type SignerFunc func(ctx context.Context, signer addr.Address, data []byte) (*crypto.Signature, error)
+
+func (f SignerFunc) Sign(ctx context.Context, signer addr.Address, data []byte) (*crypto.Signature, error) {
+	return f(ctx, signer, data)
+}
+
+// Sign sets a voucher's signature to the signature of signerAddr over its signing bytes.
+func Sign(ctx context.Context, signer Signer, signerAddr addr.Address, sv *paych.SignedVoucher) error {
+	vb, err := sv.SigningBytes()
+	if err != nil {
+		return xerrors.Errorf("failed to serialize voucher: %w", err)
+	}
+	sig, err := signer.Sign(ctx, signerAddr, vb)
+	if err != nil {
+		return xerrors.Errorf("failed to sign voucher: %w", err)
+	}
+	sv.Signature = sig
+	return nil
+}
+
+// The circumstances in which a voucher is to be redeemed.
+type Env struct {
+	// The epoch at which the voucher is to be redeemed.
+	Epoch abi.ChainEpoch
+	// The channel party that will submit the voucher. The voucher must be signed by the other party.
+	Submitter addr.Address
+	// The secret and proof that will be submitted with the voucher.
+	Secret []byte
+	Proof  []byte
+	// Verifies the voucher signature, as the VerifySignature syscall.
+	VerifySignature func(sig crypto.Signature, signer addr.Address, plaintext []byte) error
+	// Verifies the voucher's Extra with the proof, as the actor named in Extra would.
+	// Required only for vouchers with Extra.
+	VerifyExtra func(extra *paych.ModVerifyParams, proof []byte) error
+}
+
+// A local view of a payment channel's redemptions.
+// It starts from the channel's on-chain state, to which vouchers may be applied without submitting them.
+type Channel struct {
+	Address addr.Address
+	From    addr.Address
+	To      addr.Address
+	// The channel actor's balance.
+	Balance abi.TokenAmount
+
+	toSend     abi.TokenAmount
+	paidOut    abi.TokenAmount
+	chainLanes *adt.Array
+	// Lanes updated by locally applied vouchers, overriding those on chain.
+	localLanes map[uint64]paych.LaneState
+}
+
+var _ paych.LaneStore = (*Channel)(nil)
+
+// LoadChannel returns a view of a channel with address chAddr, on-chain state st and balance.
+func LoadChannel(store adt.Store, chAddr addr.Address, st *paych.State, balance abi.TokenAmount) (*Channel, error) {
+	lanes, err := adt.AsArray(store, st.LaneStates)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load lanes: %w", err)
+	}
+	return &Channel{
+		Address:    chAddr,
+		From:       st.From,
+		To:         st.To,
+		Balance:    balance,
+		toSend:     st.ToSend,
+		paidOut:    st.PaidOut,
+		chainLanes: lanes,
+		localLanes: map[uint64]paych.LaneState{},
+	}, nil
+}
+
+// The amount redeemed by all vouchers applied to the channel.
+func (c *Channel) ToSend() abi.TokenAmount {
+	return c.toSend
+}
+
+// The amount redeemed by all vouchers applied to the channel but not yet paid out to To.
+func (c *Channel) Unpaid() abi.TokenAmount {
+	return big.Sub(c.toSend, c.paidOut)
+}
+
+// Returns the state of a lane after all vouchers applied to the channel, or nil if the lane does not exist.
+func (c *Channel) Lane(ID uint64) (*paych.LaneState, error) {
+	if ls, ok := c.localLanes[ID]; ok {
+		return &ls, nil
+	}
+	if ID > paych.MaxLane {
+		return nil, exitcode.ErrIllegalArgument.Wrapf("lane ID %d exceeds maximum %d", ID, paych.MaxLane)
+	}
+	var ls paych.LaneState
+	found, err := c.chainLanes.Get(ID, &ls)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &ls, nil
+}
+
+// Records a lane state locally. The on-chain state is not modified.
+func (c *Channel) SetLane(ID uint64, ls *paych.LaneState) error {
+	c.localLanes[ID] = *ls
+	return nil
+}
+
+// Check checks that a voucher would be accepted by the channel actor after the vouchers already applied,
+// and returns the amount by which it would increase the channel's redeemed amount.
+// Rule violations are reported as errors with the exit code with which the actor would abort.
+func (c *Channel) Check(sv *paych.SignedVoucher, env *Env) (abi.TokenAmount, error) {
+	_, delta, err := c.check(sv, env)
+	return delta, err
+}
+
+// Apply checks a voucher as for Check and, if valid, applies it to the channel.
+func (c *Channel) Apply(sv *paych.SignedVoucher, env *Env) (abi.TokenAmount, error) {
+	next, delta, err := c.check(sv, env)
+	if err != nil {
+		return big.Zero(), err
+	}
+	*c = *next
+	return delta, nil
+}
+
+// AddHistory applies vouchers that were previously accepted but may not yet have been submitted, in order.
+// Vouchers are assumed to have been checked when they were accepted, so only the channel state rules are applied.
+// A voucher whose lane nonce has already been reached, such as one already redeemed on chain, is skipped.
+func (c *Channel) AddHistory(vouchers ...*paych.SignedVoucher) error {
+	for _, sv := range vouchers {
+		ls, err := c.Lane(sv.Lane)
+		if err != nil {
+			return xerrors.Errorf("failed to load lane %d: %w", sv.Lane, err)
+		}
+		if ls != nil && ls.Nonce >= sv.Nonce {
+			continue
+		}
+		next := c.fork()
+		next.toSend, err = paych.RedeemVoucher(next, next.toSend, next.paidOut, next.Balance, sv)
+		if err != nil {
+			return xerrors.Errorf("failed to apply voucher for lane %d nonce %d: %w", sv.Lane, sv.Nonce, err)
+		}
+		*c = *next
+	}
+	return nil
+}
+
+// NextVoucher returns an unsigned voucher paying an additional amount on a lane, after all applied vouchers.
+func (c *Channel) NextVoucher(lane uint64, amount abi.TokenAmount) (*paych.SignedVoucher, error) {
+	ls, err := c.Lane(lane)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load lane %d: %w", lane, err)
+	}
+	if ls == nil {
+		ls = &paych.LaneState{Redeemed: big.Zero()}
+	}
+	return &paych.SignedVoucher{
+		ChannelAddr: c.Address,
+		Lane:        lane,
+		Nonce:       ls.Nonce + 1,
+		Amount:      big.Add(ls.Redeemed, amount),
+	}, nil
+}
+
+// Validate checks a voucher against a channel's on-chain state and a local history of vouchers, as for Channel.Check.
+func Validate(store adt.Store, chAddr addr.Address, st *paych.State, balance abi.TokenAmount,
+	history []*paych.SignedVoucher, sv *paych.SignedVoucher, env *Env) (abi.TokenAmount, error) {
+	ch, err := LoadChannel(store, chAddr, st, balance)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if err := ch.AddHistory(history...); err != nil {
+		return big.Zero(), err
+	}
+	return ch.Check(sv, env)
+}
+
+// Applies the checks of UpdateChannelState, in the same order, to a copy of the channel.
+func (c *Channel) check(sv *paych.SignedVoucher, env *Env) (*Channel, abi.TokenAmount, error) {
+	var signer addr.Address
+	switch env.Submitter {
+	case c.From:
+		signer = c.To
+	case c.To:
+		signer = c.From
+	default:
+		return nil, big.Zero(), exitcode.SysErrForbidden.Wrapf("submitter %v is not a channel party", env.Submitter)
+	}
+
+	if sv.Signature == nil {
+		return nil, big.Zero(), exitcode.ErrIllegalArgument.Wrapf("voucher has no signature")
+	}
+	vb, err := sv.SigningBytes()
+	if err != nil {
+		return nil, big.Zero(), exitcode.ErrIllegalArgument.Wrapf("failed to serialize signedvoucher: %v", err)
+	}
+	if err := env.VerifySignature(*sv.Signature, signer, vb); err != nil {
+		return nil, big.Zero(), exitcode.ErrIllegalArgument.Wrapf("voucher signature invalid: %v", err)
+	}
+
+	if err := sv.CheckRedeemable(c.Address, env.Epoch, env.Secret, blake2b.Sum256); err != nil {
+		return nil, big.Zero(), err
+	}
+
+	if sv.Extra != nil {
+		if env.VerifyExtra == nil {
+			return nil, big.Zero(), xerrors.Errorf("no verifier for voucher extra")
+		}
+		if err := env.VerifyExtra(sv.Extra, env.Proof); err != nil {
+			return nil, big.Zero(), xerrors.Errorf("spend voucher verification failed: %w", err)
+		}
+	}
+
+	next := c.fork()
+	next.toSend, err = paych.RedeemVoucher(next, next.toSend, next.paidOut, next.Balance, sv)
+	if err != nil {
+		return nil, big.Zero(), err
+	}
+	return next, big.Sub(next.toSend, c.toSend), nil
+}
+
+func (c *Channel) fork() *Channel {
+	next := *c
+	next.localLanes = make(map[uint64]paych.LaneState, len(c.localLanes))
+	for id, ls := range c.localLanes { //nolint:nomaprange
+		next.localLanes[id] = ls
+	}
+	return &next
+}
@@ -0,0 +1,245 @@
+package voucher_test
+
+import (
+	"context"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/minio/blake2b-simd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/actors/builtin/paych/voucher"
+	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
+	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+// Signs with a signature naming the signer, which acceptSigs accepts only for the named signer.
+var testSigner = voucher.SignerFunc(func(_ context.Context, signer addr.Address, data []byte) (*crypto.Signature, error) {
+	return &crypto.Signature{Type: crypto.SigTypeBLS, Data: append(signer.Bytes(), data...)}, nil
+})
+
+func acceptSigs(sig crypto.Signature, signer addr.Address, plaintext []byte) error {
+	expected, _ := testSigner(context.Background(), signer, plaintext)
+	if !expected.Equals(&sig) {
+		return exitcode.ErrIllegalArgument.Wrapf("bad signature")
+	}
+	return nil
+}
+
+func TestSign(t *testing.T) {
+	from := tutil.NewIDAddr(t, 101)
+	sv := &paych.SignedVoucher{ChannelAddr: tutil.NewIDAddr(t, 100), Lane: 1, Nonce: 2, Amount: big.NewInt(3)}
+	require.NoError(t, voucher.Sign(context.Background(), testSigner, from, sv))
+
+	vb, err := sv.SigningBytes()
+	require.NoError(t, err)
+	assert.NoError(t, acceptSigs(*sv.Signature, from, vb))
+}
+
+func TestChannel(t *testing.T) {
+	h := newHarness(t, abi.NewTokenAmount(100))
+
+	t.Run("computes incremental redeemable amounts", func(t *testing.T) {
+		ch := h.loadChannel(t)
+
+		sv := h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(10)})
+		delta, err := ch.Apply(sv, h.env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), delta)
+
+		next, err := ch.NextVoucher(0, big.NewInt(5))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), next.Nonce)
+		assert.Equal(t, big.NewInt(15), next.Amount)
+
+		delta, err = ch.Check(h.signed(t, next), h.env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(5), delta)
+
+		// check does not apply the voucher
+		assert.Equal(t, big.NewInt(10), ch.ToSend())
+	})
+
+	t.Run("validates against local history", func(t *testing.T) {
+		history := []*paych.SignedVoucher{
+			h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(10)}),
+			h.signed(t, &paych.SignedVoucher{Lane: 1, Nonce: 1, Amount: big.NewInt(20)}),
+		}
+
+		// merging lane 1 into lane 0 redeems only what is not already redeemed on either lane
+		sv := h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 2, Amount: big.NewInt(40), Merges: []paych.Merge{{Lane: 1, Nonce: 2}}})
+		delta, err := voucher.Validate(h.store, h.chAddr, h.state(t), h.balance, history, sv, h.env(nil))
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), delta)
+
+		// a voucher superseded by the history is rejected
+		stale := h.signed(t, &paych.SignedVoucher{Lane: 1, Nonce: 1, Amount: big.NewInt(30)})
+		_, err = voucher.Validate(h.store, h.chAddr, h.state(t), h.balance, history, stale, h.env(nil))
+		assert.Equal(t, exitcode.ErrIllegalArgument, exitcode.Unwrap(err, exitcode.Ok))
+	})
+
+	t.Run("rejects reducing redemptions below the amount paid out", func(t *testing.T) {
+		h := newHarness(t, abi.NewTokenAmount(100))
+		for _, sv := range []*paych.SignedVoucher{
+			h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(10)}),
+			h.signed(t, &paych.SignedVoucher{Lane: 1, Nonce: 1, Amount: big.NewInt(20)}),
+		} {
+			h.redeem(t, sv, 0, nil, exitcode.Ok)
+		}
+		h.rt.SetCaller(h.to, builtin.AccountActorCodeID)
+		h.rt.ExpectValidateCallerAddr(h.to)
+		h.rt.ExpectSend(h.to, builtin.MethodSend, nil, big.NewInt(30), nil, exitcode.Ok)
+		h.rt.Call(h.actor.WithdrawRedeemed, nil)
+		h.rt.Verify()
+
+		// merging lane 1 into lane 0 for less than both redeemed would take back funds already paid out
+		sv := h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 2, Amount: big.NewInt(25), Merges: []paych.Merge{{Lane: 1, Nonce: 2}}})
+		_, err := h.loadChannel(t).Check(sv, h.env(nil))
+		assert.Equal(t, exitcode.ErrIllegalArgument, exitcode.Unwrap(err, exitcode.Ok))
+
+		// nor may such a voucher be applied as history
+		assert.Error(t, h.loadChannel(t).AddHistory(sv))
+	})
+
+	t.Run("rejects unauthorized submitter", func(t *testing.T) {
+		ch := h.loadChannel(t)
+		sv := h.signed(t, &paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(10)})
+		env := h.env(nil)
+		env.Submitter = tutil.NewIDAddr(t, 999)
+		_, err := ch.Check(sv, env)
+		assert.Equal(t, exitcode.SysErrForbidden, exitcode.Unwrap(err, exitcode.Ok))
+	})
+}
+
+// Checks that vouchers are accepted or rejected exactly when the actor would, and redeem the same amounts.
+func TestAgreesWithActor(t *testing.T) {
+	secret := []byte("secret")
+	preimage := blake2b.Sum256(secret)
+
+	vouchers := []struct {
+		desc  string
+		sv    paych.SignedVoucher
+		epoch abi.ChainEpoch
+	}{
+		{"new lane", paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(10)}, 5},
+		{"second lane", paych.SignedVoucher{Lane: 3, Nonce: 1, Amount: big.NewInt(20)}, 5},
+		{"reused nonce", paych.SignedVoucher{Lane: 0, Nonce: 1, Amount: big.NewInt(15)}, 5},
+		{"lower amount", paych.SignedVoucher{Lane: 0, Nonce: 2, Amount: big.NewInt(5)}, 5},
+		{"negative amount", paych.SignedVoucher{Lane: 0, Nonce: 3, Amount: big.NewInt(-1)}, 5},
+		{"too early", paych.SignedVoucher{Lane: 0, Nonce: 3, Amount: big.NewInt(12), TimeLockMin: 10}, 5},
+		{"expired", paych.SignedVoucher{Lane: 0, Nonce: 3, Amount: big.NewInt(12), TimeLockMax: 4}, 5},
+		{"wrong secret", paych.SignedVoucher{Lane: 0, Nonce: 3, Amount: big.NewInt(12), SecretPreimage: []byte("nope")}, 5},
+		{"right secret", paych.SignedVoucher{Lane: 0, Nonce: 3, Amount: big.NewInt(12), SecretPreimage: preimage[:]}, 5},
+		{"merge own lane", paych.SignedVoucher{Lane: 0, Nonce: 4, Amount: big.NewInt(50), Merges: []paych.Merge{{Lane: 0, Nonce: 5}}}, 5},
+		{"merge missing lane", paych.SignedVoucher{Lane: 0, Nonce: 4, Amount: big.NewInt(50), Merges: []paych.Merge{{Lane: 7, Nonce: 5}}}, 5},
+		{"merge stale nonce", paych.SignedVoucher{Lane: 0, Nonce: 4, Amount: big.NewInt(50), Merges: []paych.Merge{{Lane: 3, Nonce: 1}}}, 5},
+		{"merge", paych.SignedVoucher{Lane: 0, Nonce: 4, Amount: big.NewInt(50), Merges: []paych.Merge{{Lane: 3, Nonce: 2}}}, 5},
+		{"exceeds balance", paych.SignedVoucher{Lane: 1, Nonce: 1, Amount: big.NewInt(80)}, 5},
+		{"lane too large", paych.SignedVoucher{Lane: paych.MaxLane + 1, Nonce: 1, Amount: big.NewInt(1)}, 5},
+	}
+
+	h := newHarness(t, abi.NewTokenAmount(100))
+	for _, v := range vouchers {
+		ch := h.loadChannel(t)
+		sv := h.signed(t, &v.sv)
+
+		env := h.env(secret)
+		env.Epoch = v.epoch
+		delta, checkErr := ch.Check(sv, env)
+
+		// the actor must abort with the code of the off-chain rejection, or else redeem the same amount
+		before := h.state(t).ToSend
+		h.redeem(t, sv, v.epoch, secret, exitcode.Unwrap(checkErr, exitcode.Ok))
+		if checkErr == nil {
+			assert.Equal(t, big.Sub(h.state(t).ToSend, before), delta, v.desc)
+		}
+	}
+}
+
+type harness struct {
+	actor   paych.Actor
+	rt      *mock.Runtime
+	store   adt.Store
+	chAddr  addr.Address
+	from    addr.Address
+	to      addr.Address
+	balance abi.TokenAmount
+}
+
+func newHarness(t *testing.T, balance abi.TokenAmount) *harness {
+	h := &harness{
+		chAddr:  tutil.NewIDAddr(t, 100),
+		from:    tutil.NewIDAddr(t, 101),
+		to:      tutil.NewIDAddr(t, 102),
+		balance: balance,
+	}
+	h.rt = mock.NewBuilder(context.Background(), h.chAddr).
+		WithBalance(balance, big.Zero()).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithActorType(h.from, builtin.AccountActorCodeID).
+		WithActorType(h.to, builtin.AccountActorCodeID).
+		WithHasher(blake2b.Sum256).
+		Build(t)
+	h.store = adt.AsStore(h.rt)
+
+	h.rt.ExpectValidateCallerType(builtin.InitActorCodeID)
+	h.rt.Call(h.actor.Constructor, &paych.ConstructorParams{From: h.from, To: h.to})
+	h.rt.Verify()
+	return h
+}
+
+func (h *harness) state(t *testing.T) *paych.State {
+	var st paych.State
+	h.rt.GetState(&st)
+	return &st
+}
+
+func (h *harness) loadChannel(t *testing.T) *voucher.Channel {
+	ch, err := voucher.LoadChannel(h.store, h.chAddr, h.state(t), h.balance)
+	require.NoError(t, err)
+	return ch
+}
+
+func (h *harness) env(secret []byte) *voucher.Env {
+	return &voucher.Env{
+		Submitter:       h.to,
+		Secret:          secret,
+		VerifySignature: acceptSigs,
+	}
+}
+
+// Returns a copy of a voucher for the harness channel, signed by From.
+func (h *harness) signed(t *testing.T, sv *paych.SignedVoucher) *paych.SignedVoucher {
+	signed := *sv
+	signed.ChannelAddr = h.chAddr
+	require.NoError(t, voucher.Sign(context.Background(), testSigner, h.from, &signed))
+	return &signed
+}
+
+// Submits a voucher to the actor, expecting it to exit with a code.
+func (h *harness) redeem(t *testing.T, sv *paych.SignedVoucher, epoch abi.ChainEpoch, secret []byte, code exitcode.ExitCode) {
+	vb, err := sv.SigningBytes()
+	require.NoError(t, err)
+
+	h.rt.SetEpoch(epoch)
+	h.rt.SetCaller(h.to, builtin.AccountActorCodeID)
+	h.rt.ExpectValidateCallerAddr(h.from, h.to)
+	h.rt.ExpectVerifySignature(*sv.Signature, h.from, vb, nil)
+	params := &paych.UpdateChannelStateParams{Sv: *sv, Secret: secret}
+	if code == exitcode.Ok {
+		h.rt.Call(h.actor.UpdateChannelState, params)
+	} else {
+		h.rt.ExpectAbort(code, func() {
+			h.rt.Call(h.actor.UpdateChannelState, params)
+		})
+	}
+	h.rt.Verify()
+}
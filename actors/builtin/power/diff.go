@@ -0,0 +1,49 @@
+package power
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// A change to a miner's claim between two power states.
+// From is nil for an added claim, and To is nil for a removed one.
+type ClaimChange struct {
+	Miner addr.Address
+	From  *Claim
+	To    *Claim
+}
+
+// DiffClaims returns the claims that changed between two power states.
+func DiffClaims(store adt.Store, pre, cur *State) ([]ClaimChange, error) {
+	changes, err := adt.DiffMap(store, pre.Claims, cur.Claims)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to diff claims: %w", err)
+	}
+	preClaims, err := adt.AsMap(store, pre.Claims)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load claims: %w", err)
+	}
+	curClaims, err := adt.AsMap(store, cur.Claims)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load claims: %w", err)
+	}
+
+	var result []ClaimChange
+	for _, key := range changes.Keys() {
+		miner, err := addr.NewFromBytes([]byte(key))
+		if err != nil {
+			return nil, xerrors.Errorf("failed to parse claim key: %w", err)
+		}
+		change := ClaimChange{Miner: miner}
+		if change.From, _, err = getClaim(preClaims, miner); err != nil {
+			return nil, err
+		}
+		if change.To, _, err = getClaim(curClaims, miner); err != nil {
+			return nil, err
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package power_test
+
+import (
+	"context"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestDiffClaims(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	pre := power.ConstructState(emptyMap, emptyMap)
+
+	miner1 := tutil.NewIDAddr(t, 101)
+	miner2 := tutil.NewIDAddr(t, 102)
+	putClaim(t, store, pre, miner1, &power.Claim{RawBytePower: abi.NewStoragePower(10), QualityAdjPower: abi.NewStoragePower(20)})
+
+	cur := *pre
+	require.NoError(t, cur.AddToClaim(store, miner1, abi.NewStoragePower(1), abi.NewStoragePower(2)))
+	putClaim(t, store, &cur, miner2, &power.Claim{RawBytePower: abi.NewStoragePower(3), QualityAdjPower: abi.NewStoragePower(4)})
+
+	changes, err := power.DiffClaims(store, pre, &cur)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	byMiner := map[string]power.ClaimChange{}
+	for _, c := range changes {
+		byMiner[c.Miner.String()] = c
+	}
+
+	modified := byMiner[miner1.String()]
+	assert.Equal(t, abi.NewStoragePower(10), modified.From.RawBytePower)
+	assert.Equal(t, abi.NewStoragePower(11), modified.To.RawBytePower)
+
+	added := byMiner[miner2.String()]
+	assert.Nil(t, added.From)
+	assert.Equal(t, abi.NewStoragePower(4), added.To.QualityAdjPower)
+}
+
+func putClaim(t *testing.T, store adt.Store, st *power.State, miner addr.Address, claim *power.Claim) {
+	claims, err := adt.AsMap(store, st.Claims)
+	require.NoError(t, err)
+	require.NoError(t, claims.Put(adt.AddrKey(miner), claim))
+	st.Claims, err = claims.Root()
+	require.NoError(t, err)
+}
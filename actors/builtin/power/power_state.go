@@ -97,7 +97,7 @@ func ConstructState(emptyMapCid, emptyMMapCid cid.Cid) *State {
 // winners outside the chain state. If the miner has over a threshold of power
 // the miner meets the minimum.  If the network is a below a threshold of
 // miners and has power > zero the miner meets the minimum.
-func (st *State) MinerNominalPowerMeetsConsensusMinimum(s adt.Store, miner addr.Address) (bool, error) { //nolint:deadcode,unused
+func (st *State) MinerNominalPowerMeetsConsensusMinimum(s adt.Store, miner addr.Address) (bool, error) {
 	claims, err := adt.AsMap(s, st.Claims)
 	if err != nil {
 		return false, xerrors.Errorf("failed to load claims: %w", err)
@@ -111,10 +111,8 @@ func (st *State) MinerNominalPowerMeetsConsensusMinimum(s adt.Store, miner addr.
 		return false, errors.Errorf("no claim for actor %v", miner)
 	}
 
-	minerNominalPower := claim.QualityAdjPower
-
 	// if miner is larger than min power requirement, we're set
-	if minerNominalPower.GreaterThanEqual(ConsensusMinerMinPower) {
+	if claim.MeetsConsensusMinimum() {
 		return true, nil
 	}
 
@@ -124,7 +122,13 @@ func (st *State) MinerNominalPowerMeetsConsensusMinimum(s adt.Store, miner addr.
 	}
 
 	// If fewer than ConsensusMinerMinMiners over threshold miner can win a block with non-zero power
-	return minerNominalPower.GreaterThanEqual(abi.NewStoragePower(0)), nil
+	return claim.QualityAdjPower.GreaterThan(big.Zero()), nil
+}
+
+// MeetsConsensusMinimum returns whether the claim's quality adjusted power is at least the minimum
+// for consensus. Only miners whose claims meet the minimum count towards MinerAboveMinPowerCount.
+func (c *Claim) MeetsConsensusMinimum() bool {
+	return c.QualityAdjPower.GreaterThanEqual(ConsensusMinerMinPower)
 }
 
 // Parameters may be negative to subtract.
@@ -164,8 +168,8 @@ func (st *State) addToClaim(claims *adt.Map, miner addr.Address, power abi.Stora
 		QualityAdjPower: big.Add(oldClaim.QualityAdjPower, qapower),
 	}
 
-	prevBelow := oldClaim.QualityAdjPower.LessThan(ConsensusMinerMinPower)
-	stillBelow := newClaim.QualityAdjPower.LessThan(ConsensusMinerMinPower)
+	prevBelow := !oldClaim.MeetsConsensusMinimum()
+	stillBelow := !newClaim.MeetsConsensusMinimum()
 
 	if prevBelow && !stillBelow {
 		// just passed min miner size
@@ -554,6 +554,80 @@ func TestPowerAndPledgeAccounting(t *testing.T) {
 	})
 }
 
+func TestMinerConsensusEligibility(t *testing.T) {
+	actor := newHarness(t)
+	owner := tutil.NewIDAddr(t, 101)
+	miner1 := tutil.NewIDAddr(t, 111)
+	miner2 := tutil.NewIDAddr(t, 112)
+	miner3 := tutil.NewIDAddr(t, 113)
+	miner4 := tutil.NewIDAddr(t, 114)
+
+	powerUnit := power.ConsensusMinerMinPower
+	smallPowerUnit := big.NewInt(1_000_000)
+	// Subtests implicitly rely on ConsensusMinerMinMiners = 3
+	require.Equal(t, 3, power.ConsensusMinerMinMiners)
+
+	builder := mock.NewBuilder(context.Background(), builtin.StoragePowerActorAddr).
+		WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID)
+
+	eligible := func(rt *mock.Runtime, miner addr.Address) bool {
+		st := getState(rt)
+		ok, err := st.MinerNominalPowerMeetsConsensusMinimum(adt.AsStore(rt), miner)
+		require.NoError(t, err)
+		return ok
+	}
+
+	t.Run("miners with non-zero power are eligible below min miner count", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+
+		actor.updateClaimedPower(rt, miner1, smallPowerUnit, smallPowerUnit)
+		assert.True(t, eligible(rt, miner1))
+		assert.False(t, actor.getClaim(rt, miner1).MeetsConsensusMinimum())
+
+		// a miner with no power can never win
+		assert.False(t, eligible(rt, miner2))
+	})
+
+	t.Run("only miners above min power are eligible once min miner count is reached", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		actor.createMinerBasic(rt, owner, owner, miner1)
+		actor.createMinerBasic(rt, owner, owner, miner2)
+		actor.createMinerBasic(rt, owner, owner, miner3)
+		actor.createMinerBasic(rt, owner, owner, miner4)
+
+		actor.updateClaimedPower(rt, miner1, powerUnit, powerUnit)
+		actor.updateClaimedPower(rt, miner2, powerUnit, powerUnit)
+		actor.updateClaimedPower(rt, miner3, powerUnit, powerUnit)
+		actor.updateClaimedPower(rt, miner4, smallPowerUnit, smallPowerUnit)
+		require.EqualValues(t, 3, getState(rt).MinerAboveMinPowerCount)
+
+		assert.True(t, actor.getClaim(rt, miner1).MeetsConsensusMinimum())
+		assert.True(t, eligible(rt, miner1))
+		assert.False(t, eligible(rt, miner4))
+
+		// miner3 dropping below the minimum re-enables the small miner
+		actor.updateClaimedPower(rt, miner3, smallPowerUnit.Neg(), smallPowerUnit.Neg())
+		require.EqualValues(t, 2, getState(rt).MinerAboveMinPowerCount)
+		assert.True(t, eligible(rt, miner3))
+		assert.True(t, eligible(rt, miner4))
+	})
+
+	t.Run("fails for unknown miner", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		st := getState(rt)
+		_, err := st.MinerNominalPowerMeetsConsensusMinimum(adt.AsStore(rt), miner1)
+		assert.Error(t, err)
+	})
+}
+
 func TestCron(t *testing.T) {
 	actor := newHarness(t)
 	miner1 := tutil.NewIDAddr(t, 101)
@@ -14,7 +14,7 @@ import (
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{137}
+var lengthBufState = []byte{138}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -83,6 +83,13 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 	if err := t.TotalMined.MarshalCBOR(w); err != nil {
 		return err
 	}
+
+	// t.MinerRewards (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.MinerRewards); err != nil {
+		return xerrors.Errorf("failed to write cid field t.MinerRewards: %w", err)
+	}
+
 	return nil
 }
 
@@ -100,6 +107,296 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
+	if extra != 10 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.CumsumBaseline (big.Int) (struct)
+
+	{
+
+		if err := t.CumsumBaseline.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.CumsumBaseline: %w", err)
+		}
+
+	}
+	// t.CumsumRealized (big.Int) (struct)
+
+	{
+
+		if err := t.CumsumRealized.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.CumsumRealized: %w", err)
+		}
+
+	}
+	// t.EffectiveNetworkTime (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.EffectiveNetworkTime = abi.ChainEpoch(extraI)
+	}
+	// t.EffectiveBaselinePower (big.Int) (struct)
+
+	{
+
+		if err := t.EffectiveBaselinePower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.EffectiveBaselinePower: %w", err)
+		}
+
+	}
+	// t.ThisEpochReward (big.Int) (struct)
+
+	{
+
+		if err := t.ThisEpochReward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ThisEpochReward: %w", err)
+		}
+
+	}
+	// t.ThisEpochRewardSmoothed (smoothing.FilterEstimate) (struct)
+
+	{
+
+		b, err := br.ReadByte()
+		if err != nil {
+			return err
+		}
+		if b != cbg.CborNull[0] {
+			if err := br.UnreadByte(); err != nil {
+				return err
+			}
+			t.ThisEpochRewardSmoothed = new(smoothing.FilterEstimate)
+			if err := t.ThisEpochRewardSmoothed.UnmarshalCBOR(br); err != nil {
+				return xerrors.Errorf("unmarshaling t.ThisEpochRewardSmoothed pointer: %w", err)
+			}
+		}
+
+	}
+	// t.ThisEpochBaselinePower (big.Int) (struct)
+
+	{
+
+		if err := t.ThisEpochBaselinePower.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.ThisEpochBaselinePower: %w", err)
+		}
+
+	}
+	// t.Epoch (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Epoch = abi.ChainEpoch(extraI)
+	}
+	// t.TotalMined (big.Int) (struct)
+
+	{
+
+		if err := t.TotalMined.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.TotalMined: %w", err)
+		}
+
+	}
+	// t.MinerRewards (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.MinerRewards: %w", err)
+		}
+
+		t.MinerRewards = c
+
+	}
+	return nil
+}
+
+var lengthBufMinerRewardTotals = []byte{130}
+
+func (t *MinerRewardTotals) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufMinerRewardTotals); err != nil {
+		return err
+	}
+
+	// t.Reward (big.Int) (struct)
+	if err := t.Reward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Penalty (big.Int) (struct)
+	if err := t.Penalty.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *MinerRewardTotals) UnmarshalCBOR(r io.Reader) error {
+	*t = MinerRewardTotals{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Reward (big.Int) (struct)
+
+	{
+
+		if err := t.Reward.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Reward: %w", err)
+		}
+
+	}
+	// t.Penalty (big.Int) (struct)
+
+	{
+
+		if err := t.Penalty.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Penalty: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufLegacyState = []byte{137}
+
+func (t *LegacyState) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLegacyState); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.CumsumBaseline (big.Int) (struct)
+	if err := t.CumsumBaseline.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.CumsumRealized (big.Int) (struct)
+	if err := t.CumsumRealized.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.EffectiveNetworkTime (abi.ChainEpoch) (int64)
+	if t.EffectiveNetworkTime >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.EffectiveNetworkTime)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.EffectiveNetworkTime-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.EffectiveBaselinePower (big.Int) (struct)
+	if err := t.EffectiveBaselinePower.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ThisEpochReward (big.Int) (struct)
+	if err := t.ThisEpochReward.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ThisEpochRewardSmoothed (smoothing.FilterEstimate) (struct)
+	if err := t.ThisEpochRewardSmoothed.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.ThisEpochBaselinePower (big.Int) (struct)
+	if err := t.ThisEpochBaselinePower.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.Epoch (abi.ChainEpoch) (int64)
+	if t.Epoch >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Epoch)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Epoch-1)); err != nil {
+			return err
+		}
+	}
+
+	// t.TotalMined (big.Int) (struct)
+	if err := t.TotalMined.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *LegacyState) UnmarshalCBOR(r io.Reader) error {
+	*t = LegacyState{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
 	if extra != 9 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
@@ -0,0 +1,120 @@
+package reward
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/util/smoothing"
+)
+
+// The projection functions here are not used by the actor. They are exported as a convenience for tools that
+// need to forecast reward emission by applying the actor's reward computation to a hypothetical power trajectory.
+
+// The reward state after processing one epoch of a projection.
+type EpochProjection struct {
+	// The epoch for which the reward was computed.
+	Epoch abi.ChainEpoch
+	// The network power given for this epoch.
+	RealizedPower abi.StoragePower
+	// The baseline power the network is targeting at this epoch.
+	BaselinePower abi.StoragePower
+	// Ceiling of the effective network time.
+	EffectiveNetworkTime abi.ChainEpoch
+	// The reward paid to all expected leaders of this epoch.
+	ThisEpochReward abi.TokenAmount
+	// The smoothed estimate of ThisEpochReward.
+	ThisEpochRewardSmoothed smoothing.FilterEstimate
+	// The sum of ThisEpochReward over this and all prior projected epochs.
+	CumulativeMinted abi.TokenAmount
+}
+
+// An epoch at which realized power crosses the baseline.
+type BaselineCrossing struct {
+	Epoch abi.ChainEpoch
+	// Whether realized power has risen to meet the baseline (true) or fallen below it (false).
+	Above bool
+}
+
+type Projection struct {
+	Epochs []EpochProjection
+	// Epochs at which realized power crosses the baseline, in order.
+	// The starting state is taken to be below the baseline.
+	Crossings []BaselineCrossing
+}
+
+// ProjectRewards computes reward emission over successive epochs, starting from (but not modifying) `st` and taking
+// the network's realized power at each epoch from `realizedPower`.
+// Every epoch is assumed to be non-null with the expected number of leaders, so the whole of each epoch's reward is
+// minted.
+//
+// A projection from genesis may start from the state returned by ConstructState.
+func ProjectRewards(st *State, realizedPower []abi.StoragePower) *Projection {
+	proj := &Projection{
+		Epochs: make([]EpochProjection, 0, len(realizedPower)),
+	}
+
+	sim := *st
+	minted := big.Zero()
+	above := false
+	for _, power := range realizedPower {
+		sim.updateToNextEpochWithReward(power)
+		sim.updateSmoothedEstimates(1)
+		minted = big.Add(minted, sim.ThisEpochReward)
+
+		if nowAbove := power.GreaterThanEqual(sim.ThisEpochBaselinePower); nowAbove != above {
+			proj.Crossings = append(proj.Crossings, BaselineCrossing{Epoch: sim.Epoch, Above: nowAbove})
+			above = nowAbove
+		}
+
+		proj.Epochs = append(proj.Epochs, EpochProjection{
+			Epoch:                   sim.Epoch,
+			RealizedPower:           power,
+			BaselinePower:           sim.ThisEpochBaselinePower,
+			EffectiveNetworkTime:    sim.EffectiveNetworkTime,
+			ThisEpochReward:         sim.ThisEpochReward,
+			ThisEpochRewardSmoothed: *sim.ThisEpochRewardSmoothed,
+			CumulativeMinted:        minted,
+		})
+	}
+	return proj
+}
+
+var projectionCSVHeader = []string{
+	"epoch",
+	"realized_power",
+	"baseline_power",
+	"effective_network_time",
+	"this_epoch_reward",
+	"reward_smoothed_position",
+	"reward_smoothed_velocity",
+	"cumulative_minted",
+}
+
+// WriteCSV writes the per-epoch projection as CSV with a header row.
+// Token amounts are in attoFIL. The smoothed estimate is in Q.128 format, as stored in state.
+func (p *Projection) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(projectionCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range p.Epochs {
+		row := []string{
+			fmt.Sprint(e.Epoch),
+			e.RealizedPower.String(),
+			e.BaselinePower.String(),
+			fmt.Sprint(e.EffectiveNetworkTime),
+			e.ThisEpochReward.String(),
+			e.ThisEpochRewardSmoothed.PositionEstimate.String(),
+			e.ThisEpochRewardSmoothed.VelocityEstimate.String(),
+			e.CumulativeMinted.String(),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
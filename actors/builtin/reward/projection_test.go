@@ -0,0 +1,92 @@
+package reward_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin"
+	"github.com/filecoin-project/specs-actors/actors/builtin/reward"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+	"github.com/filecoin-project/specs-actors/support/mock"
+)
+
+func TestProjectRewards(t *testing.T) {
+	actor := rewardHarness{reward.Actor{}, t}
+	belowBaseline := big.Lsh(abi.NewStoragePower(1), 39)
+	aboveBaseline := big.Lsh(abi.NewStoragePower(1), 62)
+
+	t.Run("matches actor reward computation", func(t *testing.T) {
+		rt := mock.NewBuilder(context.Background(), builtin.RewardActorAddr).
+			WithCaller(builtin.SystemActorAddr, builtin.SystemActorCodeID).
+			Build(t)
+		startPower := abi.NewStoragePower(0)
+		actor.constructAndVerify(rt, &startPower)
+
+		trajectory := []abi.StoragePower{belowBaseline, belowBaseline, aboveBaseline, aboveBaseline, belowBaseline}
+		proj := reward.ProjectRewards(getState(rt), trajectory)
+		require.Len(t, proj.Epochs, len(trajectory))
+
+		minted := big.Zero()
+		for i, power := range trajectory {
+			rt.SetEpoch(abi.ChainEpoch(i))
+			actor.updateNetworkKPI(rt, &power)
+			st := getState(rt)
+
+			e := proj.Epochs[i]
+			assert.Equal(t, st.Epoch, e.Epoch)
+			assert.Equal(t, st.ThisEpochReward, e.ThisEpochReward)
+			assert.Equal(t, *st.ThisEpochRewardSmoothed, e.ThisEpochRewardSmoothed)
+			assert.Equal(t, st.ThisEpochBaselinePower, e.BaselinePower)
+			assert.Equal(t, st.EffectiveNetworkTime, e.EffectiveNetworkTime)
+
+			minted = big.Add(minted, st.ThisEpochReward)
+			assert.Equal(t, minted, e.CumulativeMinted)
+		}
+
+		assert.Equal(t, []reward.BaselineCrossing{
+			{Epoch: 3, Above: true},
+			{Epoch: 5, Above: false},
+		}, proj.Crossings)
+	})
+
+	t.Run("does not modify initial state", func(t *testing.T) {
+		st := newGenesisState(t)
+		prev := *st
+		prevSmoothed := *st.ThisEpochRewardSmoothed
+
+		reward.ProjectRewards(st, []abi.StoragePower{aboveBaseline, aboveBaseline})
+		assert.Equal(t, prev.Epoch, st.Epoch)
+		assert.Equal(t, prev.ThisEpochReward, st.ThisEpochReward)
+		assert.Equal(t, prev.CumsumRealized, st.CumsumRealized)
+		assert.Equal(t, prevSmoothed, *st.ThisEpochRewardSmoothed)
+	})
+
+	t.Run("writes csv", func(t *testing.T) {
+		proj := reward.ProjectRewards(newGenesisState(t), []abi.StoragePower{belowBaseline, belowBaseline, belowBaseline})
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, proj.WriteCSV(buf))
+
+		records, err := csv.NewReader(buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, "epoch", records[0][0])
+		assert.Equal(t, "cumulative_minted", records[0][len(records[0])-1])
+		assert.Equal(t, "3", records[3][0])
+		assert.Equal(t, proj.Epochs[2].CumulativeMinted.String(), records[3][len(records[3])-1])
+	})
+}
+
+func newGenesisState(t *testing.T) *reward.State {
+	emptyMap, err := adt.MakeEmptyMap(ipld.NewADTStore(context.Background())).Root()
+	require.NoError(t, err)
+	return reward.ConstructState(big.Zero(), emptyMap)
+}
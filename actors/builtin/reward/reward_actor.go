@@ -34,7 +34,10 @@ func (a Actor) Constructor(rt vmr.Runtime, currRealizedPower *abi.StoragePower)
 		rt.Abortf(exitcode.ErrIllegalArgument, "arugment should not be nil")
 		return nil // linter does not understand abort exiting
 	}
-	st := ConstructState(*currRealizedPower)
+	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to construct state")
+
+	st := ConstructState(*currRealizedPower, emptyMap)
 	rt.State().Create(st)
 	return nil
 }
@@ -80,6 +83,7 @@ func (a Actor) AwardBlockReward(rt vmr.Runtime, params *AwardBlockRewardParams)
 
 	penalty := abi.NewTokenAmount(0)
 	totalReward := big.Zero()
+	rewardPayable := big.Zero()
 	var st State
 	rt.State().Transaction(&st, func() {
 		blockReward := big.Mul(st.ThisEpochReward, big.NewInt(params.WinCount))
@@ -94,20 +98,22 @@ func (a Actor) AwardBlockReward(rt vmr.Runtime, params *AwardBlockRewardParams)
 			AssertMsg(blockReward.GreaterThanEqual(big.Zero()), "programming error, block reward is %v below zero", blockReward)
 		}
 		st.TotalMined = big.Add(st.TotalMined, blockReward)
-	})
 
-	// Cap the penalty at the total reward value.
-	penalty = big.Min(params.Penalty, totalReward)
+		// Cap the penalty at the total reward value.
+		penalty = big.Min(params.Penalty, totalReward)
 
-	// Reduce the payable reward by the penalty.
-	rewardPayable := big.Sub(totalReward, penalty)
+		// Reduce the payable reward by the penalty.
+		rewardPayable = big.Sub(totalReward, penalty)
+	})
 
 	AssertMsg(big.Add(rewardPayable, penalty).LessThanEqual(priorBalance),
 		"reward payable %v + penalty %v exceeds balance %v", rewardPayable, penalty, priorBalance)
 
 	// if this fails, we can assume the miner is responsible and avoid failing here.
+	rewardPaid := rewardPayable
 	_, code := rt.Send(minerAddr, builtin.MethodsMiner.AddLockedFund, &rewardPayable, rewardPayable)
 	if !code.IsSuccess() {
+		rewardPaid = big.Zero()
 		rt.Log(vmr.ERROR, "failed to send AddLockedFund call to the miner actor with funds: %v, code: %v", rewardPayable, code)
 		_, code := rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, rewardPayable)
 		if !code.IsSuccess() {
@@ -115,6 +121,12 @@ func (a Actor) AwardBlockReward(rt vmr.Runtime, params *AwardBlockRewardParams)
 		}
 	}
 
+	// Record only the reward actually received by the miner.
+	rt.State().Transaction(&st, func() {
+		err := st.addMinerReward(adt.AsStore(rt), minerAddr, rewardPaid, penalty)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record reward for miner %v", minerAddr)
+	})
+
 	// Burn the penalty amount.
 	if penalty.GreaterThan(abi.NewTokenAmount(0)) {
 		_, code = rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, penalty)
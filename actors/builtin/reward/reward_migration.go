@@ -0,0 +1,62 @@
+package reward
+
+import (
+	cid "github.com/ipfs/go-cid"
+	xerrors "golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/actors/util/smoothing"
+)
+
+// The state of the reward actor before it tracked cumulative rewards and penalties for each miner.
+type LegacyState struct {
+	CumsumBaseline          Spacetime
+	CumsumRealized          Spacetime
+	EffectiveNetworkTime    abi.ChainEpoch
+	EffectiveBaselinePower  abi.StoragePower
+	ThisEpochReward         abi.TokenAmount
+	ThisEpochRewardSmoothed *smoothing.FilterEstimate
+	ThisEpochBaselinePower  abi.StoragePower
+	Epoch                   abi.ChainEpoch
+	TotalMined              abi.TokenAmount
+}
+
+// MigrateLegacyState converts a legacy reward actor state to the current layout, with no miner reward totals.
+// Rewards paid before the migration are not attributed to any miner.
+func MigrateLegacyState(store adt.Store, old *LegacyState) (*State, error) {
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create miner rewards map: %w", err)
+	}
+	return &State{
+		CumsumBaseline:          old.CumsumBaseline,
+		CumsumRealized:          old.CumsumRealized,
+		EffectiveNetworkTime:    old.EffectiveNetworkTime,
+		EffectiveBaselinePower:  old.EffectiveBaselinePower,
+		ThisEpochReward:         old.ThisEpochReward,
+		ThisEpochRewardSmoothed: old.ThisEpochRewardSmoothed,
+		ThisEpochBaselinePower:  old.ThisEpochBaselinePower,
+		Epoch:                   old.Epoch,
+		TotalMined:              old.TotalMined,
+		MinerRewards:            emptyMap,
+	}, nil
+}
+
+// MigrateLegacyStateRoot loads a legacy reward actor state from store, migrates it, and returns the root of the
+// migrated state.
+func MigrateLegacyStateRoot(store adt.Store, legacyRoot cid.Cid) (cid.Cid, error) {
+	var old LegacyState
+	if err := store.Get(store.Context(), legacyRoot, &old); err != nil {
+		return cid.Undef, xerrors.Errorf("failed to load legacy state %v: %w", legacyRoot, err)
+	}
+	st, err := MigrateLegacyState(store, &old)
+	if err != nil {
+		return cid.Undef, err
+	}
+	root, err := store.Put(store.Context(), st)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to store migrated state: %w", err)
+	}
+	return root, nil
+}
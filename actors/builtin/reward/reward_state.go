@@ -1,8 +1,13 @@
 package reward
 
 import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	xerrors "golang.org/x/xerrors"
+
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/actors/util/smoothing"
 )
 
@@ -51,9 +56,20 @@ type State struct {
 
 	// TotalMined tracks the total FIL awared to block miners
 	TotalMined abi.TokenAmount
+
+	// Cumulative rewards and penalties for each block producer.
+	MinerRewards cid.Cid // Map, HAMT[address]MinerRewardTotals
 }
 
-func ConstructState(currRealizedPower abi.StoragePower) *State {
+// Cumulative block rewards and penalties of a single miner.
+type MinerRewardTotals struct {
+	// Total reward credited to the miner, net of penalties.
+	Reward abi.TokenAmount
+	// Total penalty deducted from the miner's rewards and burnt.
+	Penalty abi.TokenAmount
+}
+
+func ConstructState(currRealizedPower abi.StoragePower, emptyMapCid cid.Cid) *State {
 	st := &State{
 		CumsumBaseline:         big.Zero(),
 		CumsumRealized:         big.Zero(),
@@ -66,6 +82,7 @@ func ConstructState(currRealizedPower abi.StoragePower) *State {
 
 		ThisEpochRewardSmoothed: smoothing.NewEstimate(InitialRewardPositionEstimate, InitialRewardVelocityEstimate),
 		TotalMined:              big.Zero(),
+		MinerRewards:            emptyMapCid,
 	}
 
 	st.updateToNextEpochWithReward(currRealizedPower)
@@ -103,3 +120,49 @@ func (st *State) updateSmoothedEstimates(delta abi.ChainEpoch) {
 	filterReward := smoothing.LoadFilter(st.ThisEpochRewardSmoothed, smoothing.DefaultAlpha, smoothing.DefaultBeta)
 	st.ThisEpochRewardSmoothed = filterReward.NextEstimate(st.ThisEpochReward, delta)
 }
+
+// Returns the cumulative rewards and penalties for a miner, and whether any reward has been awarded to it.
+func (st *State) GetMinerRewardTotals(store adt.Store, miner addr.Address) (*MinerRewardTotals, bool, error) {
+	totals, err := adt.AsMap(store, st.MinerRewards)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load miner rewards: %w", err)
+	}
+	var out MinerRewardTotals
+	found, err := totals.Get(adt.AddrKey(miner), &out)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get reward totals for miner %v: %w", miner, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	return &out, true, nil
+}
+
+func (st *State) addMinerReward(store adt.Store, miner addr.Address, reward, penalty abi.TokenAmount) error {
+	totals, err := adt.AsMap(store, st.MinerRewards)
+	if err != nil {
+		return xerrors.Errorf("failed to load miner rewards: %w", err)
+	}
+
+	var prev MinerRewardTotals
+	found, err := totals.Get(adt.AddrKey(miner), &prev)
+	if err != nil {
+		return xerrors.Errorf("failed to get reward totals for miner %v: %w", miner, err)
+	}
+	if !found {
+		prev = MinerRewardTotals{Reward: big.Zero(), Penalty: big.Zero()}
+	}
+
+	next := MinerRewardTotals{
+		Reward:  big.Add(prev.Reward, reward),
+		Penalty: big.Add(prev.Penalty, penalty),
+	}
+	if err = totals.Put(adt.AddrKey(miner), &next); err != nil {
+		return xerrors.Errorf("failed to put reward totals for miner %v: %w", miner, err)
+	}
+
+	if st.MinerRewards, err = totals.Root(); err != nil {
+		return xerrors.Errorf("failed to flush miner rewards: %w", err)
+	}
+	return nil
+}
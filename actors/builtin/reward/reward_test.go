@@ -6,12 +6,15 @@ import (
 
 	address "github.com/filecoin-project/go-address"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/filecoin-project/specs-actors/actors/abi"
 	"github.com/filecoin-project/specs-actors/actors/abi/big"
 	"github.com/filecoin-project/specs-actors/actors/builtin"
 	"github.com/filecoin-project/specs-actors/actors/builtin/reward"
 	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
 	"github.com/filecoin-project/specs-actors/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/support/testing"
 )
@@ -185,6 +188,40 @@ func TestAwardBlockReward(t *testing.T) {
 
 	})
 
+	t.Run("tracks cumulative rewards and penalties per miner", func(t *testing.T) {
+		rt := builder.Build(t)
+		startRealizedPower := abi.NewStoragePower(1)
+		actor.constructAndVerify(rt, &startRealizedPower)
+		miner1 := tutil.NewIDAddr(t, 1000)
+		miner2 := tutil.NewIDAddr(t, 1001)
+
+		st := getState(rt)
+		st.ThisEpochReward = abi.NewTokenAmount(5000)
+		rt.ReplaceState(st)
+		rt.SetBalance(abi.NewTokenAmount(1e6))
+
+		_, found, err := getState(rt).GetMinerRewardTotals(adt.AsStore(rt), miner1)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		// award normalized by expected leaders is 1000
+		actor.awardBlockReward(rt, miner1, big.NewInt(100), big.Zero(), 1, big.NewInt(900))
+		actor.awardBlockReward(rt, miner1, big.Zero(), big.NewInt(50), 2, big.NewInt(2050))
+		actor.awardBlockReward(rt, miner2, big.NewInt(300), big.Zero(), 1, big.NewInt(700))
+
+		totals, found, err := getState(rt).GetMinerRewardTotals(adt.AsStore(rt), miner1)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.NewInt(2950), totals.Reward)
+		assert.Equal(t, big.NewInt(100), totals.Penalty)
+
+		totals, found, err = getState(rt).GetMinerRewardTotals(adt.AsStore(rt), miner2)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.NewInt(700), totals.Reward)
+		assert.Equal(t, big.NewInt(300), totals.Penalty)
+	})
+
 	t.Run("funds are sent to the burnt funds actor if sending locked funds to miner fails", func(t *testing.T) {
 		rt := builder.Build(t)
 		startRealizedPower := abi.NewStoragePower(1)
@@ -211,9 +248,47 @@ func TestAwardBlockReward(t *testing.T) {
 		})
 
 		rt.Verify()
+
+		// the reward was not received by the miner, so is not included in its totals
+		totals, found, err := getState(rt).GetMinerRewardTotals(adt.AsStore(rt), miner)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, big.Zero(), totals.Reward)
+		assert.Equal(t, big.Zero(), totals.Penalty)
 	})
 }
 
+func TestMigrateLegacyState(t *testing.T) {
+	store := ipld.NewADTStore(context.Background())
+	emptyMap := tutil.MustRoot(t, adt.MakeEmptyMap(store))
+	current := reward.ConstructState(abi.NewStoragePower(1<<50), emptyMap)
+	current.TotalMined = abi.NewTokenAmount(1000)
+
+	legacy := &reward.LegacyState{
+		CumsumBaseline:          current.CumsumBaseline,
+		CumsumRealized:          current.CumsumRealized,
+		EffectiveNetworkTime:    current.EffectiveNetworkTime,
+		EffectiveBaselinePower:  current.EffectiveBaselinePower,
+		ThisEpochReward:         current.ThisEpochReward,
+		ThisEpochRewardSmoothed: current.ThisEpochRewardSmoothed,
+		ThisEpochBaselinePower:  current.ThisEpochBaselinePower,
+		Epoch:                   current.Epoch,
+		TotalMined:              current.TotalMined,
+	}
+	legacyRoot, err := store.Put(context.Background(), legacy)
+	require.NoError(t, err)
+
+	root, err := reward.MigrateLegacyStateRoot(store, legacyRoot)
+	require.NoError(t, err)
+	var st reward.State
+	require.NoError(t, store.Get(context.Background(), root, &st))
+	assert.Equal(t, *current, st)
+
+	_, found, err := st.GetMinerRewardTotals(store, tutil.NewIDAddr(t, 1000))
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
 func TestSuccessiveKPIUpdates(t *testing.T) {
 	actor := rewardHarness{reward.Actor{}, t}
 	builder := mock.NewBuilder(context.Background(), builtin.RewardActorAddr).
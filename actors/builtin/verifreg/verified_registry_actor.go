@@ -1,11 +1,15 @@
 package verifreg
 
 import (
+	"bytes"
+
 	addr "github.com/filecoin-project/go-address"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	abi "github.com/filecoin-project/specs-actors/actors/abi"
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
 	builtin "github.com/filecoin-project/specs-actors/actors/builtin"
+	crypto "github.com/filecoin-project/specs-actors/actors/crypto"
 	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
 	exitcode "github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 	. "github.com/filecoin-project/specs-actors/actors/util"
@@ -22,6 +26,7 @@ func (a Actor) Exports() []interface{} {
 		4:                         a.AddVerifiedClient,
 		5:                         a.UseBytes,
 		6:                         a.RestoreBytes,
+		7:                         a.RemoveVerifiedClientDataCap,
 	}
 }
 
@@ -41,7 +46,10 @@ func (a Actor) Constructor(rt vmr.Runtime, rootKey *addr.Address) *adt.EmptyValu
 	emptyMap, err := adt.MakeEmptyMap(adt.AsStore(rt)).Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
 
-	st := ConstructState(emptyMap, idAddr)
+	emptyArray, err := adt.MakeEmptyArray(adt.AsStore(rt)).Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create state")
+
+	st := ConstructState(emptyMap, emptyArray, idAddr)
 	rt.State().Create(st)
 	return nil
 }
@@ -79,11 +87,20 @@ func (a Actor) AddVerifier(rt vmr.Runtime, params *AddVerifierParams) *adt.Empty
 			rt.Abortf(exitcode.ErrIllegalArgument, "verified client %v cannot become a verifier", params.Address)
 		}
 
+		var prevCap DataCap
+		found, err = verifiers.Get(AddrKey(params.Address), &prevCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", params.Address)
+		if !found {
+			prevCap = big.Zero()
+		}
+
 		err = verifiers.Put(AddrKey(params.Address), &params.Allowance)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verifier")
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		appendAllocationEvent(rt, &st, AllocationEventAddVerifier, st.RootKey, params.Address, big.Sub(params.Allowance, prevCap))
 	})
 
 	return nil
@@ -98,11 +115,20 @@ func (a Actor) RemoveVerifier(rt vmr.Runtime, verifierAddr *addr.Address) *adt.E
 		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
 
+		var prevCap DataCap
+		found, err := verifiers.Get(AddrKey(*verifierAddr), &prevCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", *verifierAddr)
+		if !found {
+			prevCap = big.Zero()
+		}
+
 		err = verifiers.Delete(AddrKey(*verifierAddr))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier")
 
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		appendAllocationEvent(rt, &st, AllocationEventRemoveVerifier, st.RootKey, *verifierAddr, prevCap.Neg())
 	})
 
 	return nil
@@ -111,14 +137,23 @@ func (a Actor) RemoveVerifier(rt vmr.Runtime, verifierAddr *addr.Address) *adt.E
 type AddVerifiedClientParams struct {
 	Address   addr.Address
 	Allowance DataCap
+	// Epoch from which the allowance can no longer be used, or NoExpiration.
+	Expiration abi.ChainEpoch
 }
 
+// Indicates a verified client allowance that does not expire.
+const NoExpiration = abi.ChainEpoch(0)
+
 func (a Actor) AddVerifiedClient(rt vmr.Runtime, params *AddVerifiedClientParams) *adt.EmptyValue {
 	if params.Allowance.LessThan(MinVerifiedDealSize) {
 		rt.Abortf(exitcode.ErrIllegalArgument, "allowance %d below MinVerifiedDealSize for add verified client %v", params.Allowance, params.Address)
 	}
 	rt.ValidateImmediateCallerAcceptAny()
 
+	if params.Expiration != NoExpiration && params.Expiration <= rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expiration %d must be after current epoch %d", params.Expiration, rt.CurrEpoch())
+	}
+
 	var st State
 	rt.State().Readonly(&st)
 	// TODO We need to resolve the client address to an ID address before making this comparison.
@@ -172,6 +207,26 @@ func (a Actor) AddVerifiedClient(rt vmr.Runtime, params *AddVerifiedClientParams
 		err = verifiedClients.Put(AddrKey(params.Address), &params.Allowance)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v with cap %d", params.Address, params.Allowance)
 
+		// Set the expiration, replacing any left from a previous allowance.
+		expirations, err := adt.AsMap(adt.AsStore(rt), st.ClientExpirations)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client expirations")
+
+		found, err = expirations.Get(AddrKey(params.Address), nil)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get expiration for %v", params.Address)
+		if params.Expiration != NoExpiration {
+			expiration := cbg.CborInt(params.Expiration)
+			err = expirations.Put(AddrKey(params.Address), &expiration)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set expiration for %v", params.Address)
+		} else if found {
+			err = expirations.Delete(AddrKey(params.Address))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete expiration for %v", params.Address)
+		}
+
+		st.ClientExpirations, err = expirations.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush client expirations")
+
+		appendAllocationEvent(rt, &st, AllocationEventAddVerifiedClient, verifierAddr, params.Address, params.Allowance)
+
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
 
@@ -189,6 +244,7 @@ type UseBytesParams struct {
 
 // Called by StorageMarketActor during PublishStorageDeals.
 // Do not allow partially verified deals (DealSize must be greater than equal to allowed cap).
+// Do not allow use of an allowance from its expiration epoch.
 // Delete VerifiedClient if remaining DataCap is smaller than minimum VerifiedDealSize.
 func (a Actor) UseBytes(rt vmr.Runtime, params *UseBytesParams) *adt.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
@@ -210,6 +266,8 @@ func (a Actor) UseBytes(rt vmr.Runtime, params *UseBytesParams) *adt.EmptyValue
 		}
 		Assert(vcCap.GreaterThanEqual(big.Zero()))
 
+		requireAllowanceNotExpired(rt, &st, params.Address)
+
 		if params.DealSize.GreaterThan(vcCap) {
 			rt.Abortf(exitcode.ErrIllegalArgument, "DealSize %d exceeds allowable cap: %d for VerifiedClient %v", params.DealSize, vcCap, params.Address)
 		}
@@ -218,8 +276,10 @@ func (a Actor) UseBytes(rt vmr.Runtime, params *UseBytesParams) *adt.EmptyValue
 		if newVcCap.LessThan(MinVerifiedDealSize) {
 			// Delete entry if remaining DataCap is less than MinVerifiedDealSize.
 			// Will be restored later if the deal did not get activated with a ProvenSector.
+			// The client's expiration is kept, so that a restored allowance still expires.
 			err = verifiedClients.Delete(AddrKey(params.Address))
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", params.Address)
+			newVcCap = big.Zero()
 		} else {
 			err = verifiedClients.Put(AddrKey(params.Address), &newVcCap)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", params.Address, newVcCap)
@@ -227,6 +287,8 @@ func (a Actor) UseBytes(rt vmr.Runtime, params *UseBytesParams) *adt.EmptyValue
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		appendAllocationEvent(rt, &st, AllocationEventUseBytes, builtin.StorageMarketActorAddr, params.Address, big.Sub(newVcCap, vcCap))
 	})
 
 	return nil
@@ -239,6 +301,8 @@ type RestoreBytesParams struct {
 
 // Called by HandleInitTimeoutDeals from StorageMarketActor when a VerifiedDeal fails to init.
 // Restore allowable cap for the client, creating new entry if the client has been deleted.
+// A deleted client's expiration is kept with its entry deleted, so a restored entry expires as the allowance did.
+// Do not restore an allowance from its expiration epoch.
 func (a Actor) RestoreBytes(rt vmr.Runtime, params *RestoreBytesParams) *adt.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
 
@@ -268,6 +332,8 @@ func (a Actor) RestoreBytes(rt vmr.Runtime, params *RestoreBytesParams) *adt.Emp
 			rt.Abortf(exitcode.ErrIllegalArgument, "cannot restore allowance for a verifier")
 		}
 
+		requireAllowanceNotExpired(rt, &st, params.Address)
+
 		var vcCap DataCap
 		found, err = verifiedClients.Get(AddrKey(params.Address), &vcCap)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", params.Address)
@@ -281,7 +347,163 @@ func (a Actor) RestoreBytes(rt vmr.Runtime, params *RestoreBytesParams) *adt.Emp
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		appendAllocationEvent(rt, &st, AllocationEventRestoreBytes, builtin.StorageMarketActorAddr, params.Address, params.DealSize)
 	})
 
 	return nil
 }
+
+const SignatureDomainSeparation_RemoveDataCap = "fil_removedatacap:"
+
+type RemoveDataCapParams struct {
+	VerifiedClientToRemove addr.Address
+	DataCapAmountToRemove  DataCap
+	VerifierRequest1       RemoveDataCapRequest
+	VerifierRequest2       RemoveDataCapRequest
+}
+
+// A verifier's signature over a RemoveDataCapProposal.
+type RemoveDataCapRequest struct {
+	Verifier          addr.Address
+	VerifierSignature crypto.Signature
+}
+
+// The payload signed by a verifier to request removal of a client's DataCap.
+// The signed bytes are the proposal's serialization prefixed with SignatureDomainSeparation_RemoveDataCap.
+type RemoveDataCapProposal struct {
+	VerifiedClient    addr.Address
+	DataCapAmount     DataCap
+	RemovalProposalID RmDcProposalID
+}
+
+type RemoveDataCapReturn struct {
+	VerifiedClient addr.Address
+	DataCapRemoved DataCap
+}
+
+// Removes up to an amount of DataCap from a verified client, with the signed agreement of two distinct verifiers.
+// Each verifier signs a proposal with the next proposal ID expected from that verifier for the client, so
+// a signature cannot be replayed.
+// The client is deleted if its remaining DataCap is smaller than minimum VerifiedDealSize.
+func (a Actor) RemoveVerifiedClientDataCap(rt vmr.Runtime, params *RemoveDataCapParams) *RemoveDataCapReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	if params.DataCapAmountToRemove.Sign() <= 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "DataCap amount to remove must be positive, was %v", params.DataCapAmountToRemove)
+	}
+	if params.VerifierRequest1.Verifier == params.VerifierRequest2.Verifier {
+		rt.Abortf(exitcode.ErrIllegalArgument, "removal requests must be from distinct verifiers, both were %v", params.VerifierRequest1.Verifier)
+	}
+
+	var removed DataCap
+	var st State
+	rt.State().Transaction(&st, func() {
+		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+		proposalIDs, err := adt.AsMap(adt.AsStore(rt), st.RemoveDataCapProposalIDs)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load datacap removal proposal IDs")
+
+		for _, req := range []RemoveDataCapRequest{params.VerifierRequest1, params.VerifierRequest2} {
+			found, err := verifiers.Get(AddrKey(req.Verifier), nil)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", req.Verifier)
+			if !found {
+				rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", req.Verifier)
+			}
+			useProposalID(rt, proposalIDs, req, params)
+		}
+
+		var vcCap DataCap
+		found, err := verifiedClients.Get(AddrKey(params.VerifiedClientToRemove), &vcCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", params.VerifiedClientToRemove)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no such verified client %v", params.VerifiedClientToRemove)
+		}
+
+		newVcCap := big.Sub(vcCap, params.DataCapAmountToRemove)
+		if newVcCap.LessThan(MinVerifiedDealSize) {
+			removed = vcCap
+			err = verifiedClients.Delete(AddrKey(params.VerifiedClientToRemove))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", params.VerifiedClientToRemove)
+		} else {
+			removed = params.DataCapAmountToRemove
+			err = verifiedClients.Put(AddrKey(params.VerifiedClientToRemove), &newVcCap)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", params.VerifiedClientToRemove, newVcCap)
+		}
+
+		st.VerifiedClients, err = verifiedClients.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		st.RemoveDataCapProposalIDs, err = proposalIDs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush datacap removal proposal IDs")
+
+		appendAllocationEvent(rt, &st, AllocationEventRemoveDataCap, params.VerifierRequest1.Verifier,
+			params.VerifiedClientToRemove, removed.Neg())
+	})
+
+	return &RemoveDataCapReturn{
+		VerifiedClient: params.VerifiedClientToRemove,
+		DataCapRemoved: removed,
+	}
+}
+
+// Verifies a verifier's removal request signature over a proposal bearing the verifier's next proposal ID for the
+// client, and increments that ID.
+func useProposalID(rt vmr.Runtime, proposalIDs *adt.Map, req RemoveDataCapRequest, params *RemoveDataCapParams) {
+	key := AddrPairKey{req.Verifier, params.VerifiedClientToRemove}
+	var id cbg.CborInt
+	_, err := proposalIDs.Get(key, &id)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get datacap removal proposal ID for verifier %v", req.Verifier)
+
+	proposal := RemoveDataCapProposal{
+		VerifiedClient:    params.VerifiedClientToRemove,
+		DataCapAmount:     params.DataCapAmountToRemove,
+		RemovalProposalID: RmDcProposalID(id),
+	}
+	buf := bytes.NewBufferString(SignatureDomainSeparation_RemoveDataCap)
+	err = proposal.MarshalCBOR(buf)
+	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to serialize datacap removal proposal")
+
+	err = rt.Syscalls().VerifySignature(req.VerifierSignature, req.Verifier, buf.Bytes())
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid signature for datacap removal request from verifier %v", req.Verifier)
+
+	next := id + 1
+	err = proposalIDs.Put(key, &next)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update datacap removal proposal ID for verifier %v", req.Verifier)
+}
+
+// Aborts if a client's allowance has reached its expiration epoch.
+// The expiration outlives the client's entry, so this applies whether or not the client is currently verified.
+func requireAllowanceNotExpired(rt vmr.Runtime, st *State, client addr.Address) {
+	expirations, err := adt.AsMap(adt.AsStore(rt), st.ClientExpirations)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load client expirations")
+
+	var expiration cbg.CborInt
+	found, err := expirations.Get(AddrKey(client), &expiration)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get expiration for %v", client)
+	if found && rt.CurrEpoch() >= abi.ChainEpoch(expiration) {
+		rt.Abortf(exitcode.ErrForbidden, "allowance for verified client %v expired at %d", client, expiration)
+	}
+}
+
+// Appends an event to the allocation event log.
+func appendAllocationEvent(rt vmr.Runtime, st *State, kind AllocationEventKind, authority, subject addr.Address, delta DataCap) {
+	events, err := adt.AsArray(adt.AsStore(rt), st.AllocationEvents)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load allocation events")
+
+	err = events.AppendContinuous(&AllocationEvent{
+		Kind:      kind,
+		Authority: authority,
+		Subject:   subject,
+		Delta:     delta,
+		Epoch:     rt.CurrEpoch(),
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to append allocation event")
+
+	st.AllocationEvents, err = events.Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush allocation events")
+}
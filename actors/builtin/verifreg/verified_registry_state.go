@@ -6,6 +6,7 @@ import (
 	big "github.com/filecoin-project/specs-actors/actors/abi/big"
 	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
 	cid "github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
 )
 
 // DataCap is an integer number of bytes.
@@ -24,15 +25,96 @@ type State struct {
 
 	// VerifiedClients can add VerifiedClientData, up to DataCap.
 	VerifiedClients cid.Cid // HAMT[addr.Address]DataCap
+
+	// Epochs at which verified client allowances expire, for clients added with an expiration.
+	// An entry is kept when its client is deleted, so that a restored allowance still expires, and is replaced
+	// when the client is next added.
+	ClientExpirations cid.Cid // HAMT[addr.Address]abi.ChainEpoch
+
+	// The next DataCap removal proposal ID expected from each verifier for each client.
+	RemoveDataCapProposalIDs cid.Cid // HAMT[AddrPairKey]RmDcProposalID
+
+	// Append-only log of changes to verifier and client allowances, in order.
+	AllocationEvents cid.Cid // AMT[AllocationEvent]
+}
+
+type AllocationEventKind uint64
+
+// The kinds of allocation event, and the meaning of an event's Authority and Subject for each.
+const (
+	// The root key set a verifier's allowance. Authority: the root key. Subject: the verifier.
+	AllocationEventAddVerifier AllocationEventKind = iota
+	// A verifier granted an allowance to a new client. Authority: the verifier. Subject: the client.
+	AllocationEventAddVerifiedClient
+	// The market used a client's allowance for a deal. Authority: the storage market actor. Subject: the client.
+	AllocationEventUseBytes
+	// The market restored a client's allowance for a deal that timed out.
+	// Authority: the storage market actor. Subject: the client.
+	AllocationEventRestoreBytes
+	// Two verifiers removed DataCap from a client. Authority: the first requesting verifier. Subject: the client.
+	AllocationEventRemoveDataCap
+	// The root key removed a verifier. Authority: the root key. Subject: the verifier.
+	AllocationEventRemoveVerifier
+)
+
+// A change to a verifier's or verified client's allowance.
+type AllocationEvent struct {
+	Kind AllocationEventKind
+	// The address on whose authority the allowance was changed.
+	Authority addr.Address
+	// The verifier or client whose allowance changed.
+	Subject addr.Address
+	// The change in the subject's allowance, which may be negative.
+	Delta DataCap
+	Epoch abi.ChainEpoch
+}
+
+// Identifies a DataCap removal proposal from a verifier, preventing replay of the verifier's signature.
+type RmDcProposalID = uint64
+
+// Adapts a (verifier, client) address pair as a mapping key.
+type AddrPairKey struct {
+	First  addr.Address
+	Second addr.Address
+}
+
+func (k AddrPairKey) Key() string {
+	// Address bytes are self-delimiting, so their concatenation is unambiguous.
+	return string(k.First.Bytes()) + string(k.Second.Bytes())
 }
 
 var MinVerifiedDealSize abi.StoragePower = big.NewInt(1 << 20) // PARAM_FINISH
 
 // rootKeyAddress comes from genesis.
-func ConstructState(emptyMapCid cid.Cid, rootKeyAddress addr.Address) *State {
+func ConstructState(emptyMapCid, emptyArrayCid cid.Cid, rootKeyAddress addr.Address) *State {
 	return &State{
 		RootKey:         rootKeyAddress,
 		Verifiers:       emptyMapCid,
 		VerifiedClients: emptyMapCid,
+
+		ClientExpirations:        emptyMapCid,
+		RemoveDataCapProposalIDs: emptyMapCid,
+		AllocationEvents:         emptyArrayCid,
+	}
+}
+
+// LoadAllocationEvents returns up to limit allocation events, in order, starting from the event with index start.
+// Fewer than limit events are returned if the log ends sooner.
+func (st *State) LoadAllocationEvents(store adt.Store, start, limit uint64) ([]*AllocationEvent, error) {
+	events, err := adt.AsArray(store, st.AllocationEvents)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load allocation events: %w", err)
+	}
+
+	var page []*AllocationEvent
+	var ev AllocationEvent
+	_, _, err = events.Page(start, limit, &ev, func(int64) error {
+		loaded := ev
+		page = append(page, &loaded)
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load allocation events from %d: %w", start, err)
 	}
+	return page, nil
 }
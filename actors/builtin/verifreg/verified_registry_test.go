@@ -1,17 +1,22 @@
 package verifreg_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/specs-actors/actors/abi"
 	"github.com/filecoin-project/specs-actors/actors/abi/big"
 	"github.com/filecoin-project/specs-actors/actors/builtin"
 	"github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/actors/crypto"
 	"github.com/filecoin-project/specs-actors/actors/runtime/exitcode"
 	"github.com/filecoin-project/specs-actors/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/support/mock"
 	tutil "github.com/filecoin-project/specs-actors/support/testing"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -277,7 +282,7 @@ func TestAddVerifiedClient(t *testing.T) {
 	t.Run("fails when allowance is less than MinVerifiedDealSize", func(t *testing.T) {
 		rt, ac := basicVerifRegSetup(t, root)
 		allowance := big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))
-		p := &verifreg.AddVerifiedClientParams{tutil.NewIDAddr(t, 501), allowance}
+		p := &verifreg.AddVerifiedClientParams{Address: tutil.NewIDAddr(t, 501), Allowance: allowance}
 
 		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
 			rt.Call(ac.AddVerifiedClient, p)
@@ -285,6 +290,20 @@ func TestAddVerifiedClient(t *testing.T) {
 		rt.Verify()
 	})
 
+	t.Run("fails when expiration is not after current epoch", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, verifierAddr, allowance)
+
+		rt.SetEpoch(10)
+		rt.SetCaller(verifierAddr, builtin.VerifiedRegistryActorCodeID)
+		rt.ExpectValidateCallerAny()
+		p := &verifreg.AddVerifiedClientParams{Address: clientAddr, Allowance: allowance, Expiration: 10}
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.AddVerifiedClient, p)
+		})
+		rt.Verify()
+	})
+
 	t.Run("fails when caller is not a verifier", func(t *testing.T) {
 		rt, ac := basicVerifRegSetup(t, root)
 		client := ac.mkClientParams(clientAddr, clientAllowance)
@@ -506,6 +525,180 @@ func TestUseBytes(t *testing.T) {
 
 		rt.Verify()
 	})
+
+	t.Run("consume deal bytes until allowance expires", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		clientAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(3))
+		ac.addNewVerifier(rt, verifierAddr, clientAllowance)
+		ac.addVerifiedClientWithExpiration(rt, verifierAddr, clientAddr, clientAllowance, 100)
+
+		dSize := verifreg.MinVerifiedDealSize
+		rt.SetEpoch(99)
+		ac.useBytes(rt, clientAddr, dSize, &capExpectation{expectedCap: big.Sub(clientAllowance, dSize)})
+
+		rt.SetEpoch(100)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			ac.useBytes(rt, clientAddr, dSize, nil)
+		})
+		rt.Verify()
+
+		// an expired allowance is not restored
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(ac.RestoreBytes, &verifreg.RestoreBytesParams{Address: clientAddr, DealSize: dSize})
+		})
+		rt.Verify()
+	})
+
+	t.Run("allowance restored after use below minimum keeps its expiration", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, verifierAddr, verifreg.MinVerifiedDealSize)
+		ac.addVerifiedClientWithExpiration(rt, verifierAddr, clientAddr, verifreg.MinVerifiedDealSize, 100)
+
+		rt.SetEpoch(50)
+		ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{removed: true})
+		ac.restoreBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{expectedCap: verifreg.MinVerifiedDealSize})
+
+		rt.SetEpoch(100)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, nil)
+		})
+		rt.Verify()
+	})
+
+	t.Run("new allowance replaces expiration of a removed client", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, verifierAddr, big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2)))
+		ac.addVerifiedClientWithExpiration(rt, verifierAddr, clientAddr, verifreg.MinVerifiedDealSize, 100)
+		ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{removed: true})
+
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, verifreg.MinVerifiedDealSize)
+		rt.SetEpoch(200)
+		ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{removed: true})
+	})
+}
+
+func TestRemoveVerifiedClientDataCap(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	verifierAddr := tutil.NewIDAddr(t, 301)
+	verifierAddr2 := tutil.NewIDAddr(t, 302)
+	verifierAddr3 := tutil.NewIDAddr(t, 303)
+	notaryAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(10))
+	clientAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(5))
+
+	setup := func(t *testing.T) (*mock.Runtime, *verifRegActorTestHarness) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, verifierAddr, notaryAllowance)
+		ac.addNewVerifier(rt, verifierAddr2, notaryAllowance)
+		ac.addNewVerifier(rt, verifierAddr3, notaryAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance)
+		return rt, ac
+	}
+
+	t.Run("removes DataCap with requests from two verifiers", func(t *testing.T) {
+		rt, ac := setup(t)
+		amount := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2))
+
+		ret := ac.removeDataCap(rt, clientAddr, amount, verifierAddr, 0, verifierAddr2, 0)
+		assert.Equal(t, clientAddr, ret.VerifiedClient)
+		assert.Equal(t, amount, ret.DataCapRemoved)
+		require.EqualValues(t, big.Sub(clientAllowance, amount), ac.getClientCap(rt, clientAddr))
+
+		// proposal IDs advance for each verifier independently
+		ret = ac.removeDataCap(rt, clientAddr, verifreg.MinVerifiedDealSize, verifierAddr, 1, verifierAddr3, 0)
+		assert.Equal(t, verifreg.MinVerifiedDealSize, ret.DataCapRemoved)
+		require.EqualValues(t, big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2)), ac.getClientCap(rt, clientAddr))
+	})
+
+	t.Run("removes the client when remaining DataCap is below minimum", func(t *testing.T) {
+		rt, ac := setup(t)
+		ret := ac.removeDataCap(rt, clientAddr, big.Sub(clientAllowance, big.NewInt(1)), verifierAddr, 0, verifierAddr2, 0)
+		assert.Equal(t, clientAllowance, ret.DataCapRemoved)
+		ac.assertClientRemoved(rt, clientAddr)
+	})
+
+	t.Run("keeps the client's expiration after removing the client", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addNewVerifier(rt, verifierAddr, notaryAllowance)
+		ac.addNewVerifier(rt, verifierAddr2, notaryAllowance)
+		ac.addVerifiedClientWithExpiration(rt, verifierAddr, clientAddr, clientAllowance, 100)
+
+		ac.removeDataCap(rt, clientAddr, clientAllowance, verifierAddr, 0, verifierAddr2, 0)
+		ac.assertClientRemoved(rt, clientAddr)
+
+		// a restored allowance inherits the expiration
+		ac.restoreBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, &capExpectation{expectedCap: verifreg.MinVerifiedDealSize})
+		rt.SetEpoch(200)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			ac.useBytes(rt, clientAddr, verifreg.MinVerifiedDealSize, nil)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails on replayed request", func(t *testing.T) {
+		rt, ac := setup(t)
+		ac.removeDataCap(rt, clientAddr, verifreg.MinVerifiedDealSize, verifierAddr, 0, verifierAddr2, 0)
+
+		params := ac.mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifierAddr, verifierAddr2)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectVerifySignature(params.VerifierRequest1.VerifierSignature, verifierAddr,
+			removeDataCapBytes(t, clientAddr, verifreg.MinVerifiedDealSize, 1), nil)
+		rt.ExpectVerifySignature(params.VerifierRequest2.VerifierSignature, verifierAddr2,
+			removeDataCapBytes(t, clientAddr, verifreg.MinVerifiedDealSize, 1), fmt.Errorf("signature over stale proposal ID"))
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails with the same verifier twice", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := ac.mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifierAddr, verifierAddr)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails when a requester is not a verifier", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := ac.mkRemoveDataCapParams(clientAddr, verifreg.MinVerifiedDealSize, verifierAddr, tutil.NewIDAddr(t, 399))
+		rt.ExpectValidateCallerAny()
+		rt.ExpectVerifySignature(params.VerifierRequest1.VerifierSignature, verifierAddr,
+			removeDataCapBytes(t, clientAddr, verifreg.MinVerifiedDealSize, 0), nil)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails when client does not exist", func(t *testing.T) {
+		rt, ac := setup(t)
+		other := tutil.NewIDAddr(t, 299)
+		params := ac.mkRemoveDataCapParams(other, verifreg.MinVerifiedDealSize, verifierAddr, verifierAddr2)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectVerifySignature(params.VerifierRequest1.VerifierSignature, verifierAddr,
+			removeDataCapBytes(t, other, verifreg.MinVerifiedDealSize, 0), nil)
+		rt.ExpectVerifySignature(params.VerifierRequest2.VerifierSignature, verifierAddr2,
+			removeDataCapBytes(t, other, verifreg.MinVerifiedDealSize, 0), nil)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails when amount is not positive", func(t *testing.T) {
+		rt, ac := setup(t)
+		params := ac.mkRemoveDataCapParams(clientAddr, big.Zero(), verifierAddr, verifierAddr2)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.RemoveVerifiedClientDataCap, params)
+		})
+		rt.Verify()
+	})
 }
 
 func TestRestoreBytes(t *testing.T) {
@@ -651,6 +844,63 @@ func TestRestoreBytes(t *testing.T) {
 	})
 }
 
+func TestAllocationEvents(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	verifierAddr := tutil.NewIDAddr(t, 301)
+	verifierAddr2 := tutil.NewIDAddr(t, 302)
+	min := verifreg.MinVerifiedDealSize
+
+	rt, ac := basicVerifRegSetup(t, root)
+	rt.SetEpoch(10)
+	ac.addNewVerifier(rt, verifierAddr, big.Mul(min, big.NewInt(4)))
+	ac.addNewVerifier(rt, verifierAddr2, min)
+	rt.SetEpoch(11)
+	ac.addVerifiedClient(rt, verifierAddr, clientAddr, big.Mul(min, big.NewInt(3)))
+	rt.SetEpoch(12)
+	ac.useBytes(rt, clientAddr, min, &capExpectation{expectedCap: big.Mul(min, big.NewInt(2))})
+	rt.SetEpoch(13)
+	ac.removeDataCap(rt, clientAddr, big.Add(min, big.NewInt(1)), verifierAddr, 0, verifierAddr2, 0)
+	rt.SetEpoch(14)
+	ac.restoreBytes(rt, clientAddr, min, &capExpectation{expectedCap: min})
+	rt.SetEpoch(15)
+	ac.removeVerifier(rt, verifierAddr2)
+
+	expected := []*verifreg.AllocationEvent{
+		{Kind: verifreg.AllocationEventAddVerifier, Authority: root, Subject: verifierAddr, Delta: big.Mul(min, big.NewInt(4)), Epoch: 10},
+		{Kind: verifreg.AllocationEventAddVerifier, Authority: root, Subject: verifierAddr2, Delta: min, Epoch: 10},
+		{Kind: verifreg.AllocationEventAddVerifiedClient, Authority: verifierAddr, Subject: clientAddr, Delta: big.Mul(min, big.NewInt(3)), Epoch: 11},
+		{Kind: verifreg.AllocationEventUseBytes, Authority: builtin.StorageMarketActorAddr, Subject: clientAddr, Delta: min.Neg(), Epoch: 12},
+		// the remaining DataCap falls below the minimum, so the client is removed entirely
+		{Kind: verifreg.AllocationEventRemoveDataCap, Authority: verifierAddr, Subject: clientAddr, Delta: big.Mul(min, big.NewInt(2)).Neg(), Epoch: 13},
+		{Kind: verifreg.AllocationEventRestoreBytes, Authority: builtin.StorageMarketActorAddr, Subject: clientAddr, Delta: min, Epoch: 14},
+		{Kind: verifreg.AllocationEventRemoveVerifier, Authority: root, Subject: verifierAddr2, Delta: min.Neg(), Epoch: 15},
+	}
+
+	var st verifreg.State
+	rt.GetState(&st)
+	store := adt.AsStore(rt)
+
+	var all []*verifreg.AllocationEvent
+	for start := uint64(0); ; start += 4 {
+		page, err := st.LoadAllocationEvents(store, start, 4)
+		require.NoError(t, err)
+		all = append(all, page...)
+		if len(page) < 4 {
+			break
+		}
+	}
+	assert.Equal(t, expected, all)
+
+	page, err := st.LoadAllocationEvents(store, 5, 10)
+	require.NoError(t, err)
+	assert.Equal(t, expected[5:], page)
+
+	page, err = st.LoadAllocationEvents(store, 7, 10)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+}
+
 type verifRegActorTestHarness struct {
 	rootkey address.Address
 	verifreg.Actor
@@ -682,7 +932,7 @@ func (h *verifRegActorTestHarness) mkVerifierParams(a address.Address, allowance
 }
 
 func (h *verifRegActorTestHarness) mkClientParams(a address.Address, cap verifreg.DataCap) *verifreg.AddVerifiedClientParams {
-	return &verifreg.AddVerifiedClientParams{a, cap}
+	return &verifreg.AddVerifiedClientParams{Address: a, Allowance: cap}
 }
 
 func (h *verifRegActorTestHarness) addNewVerifier(rt *mock.Runtime, a address.Address, allowance verifreg.DataCap) *verifreg.AddVerifierParams {
@@ -706,10 +956,14 @@ func (h *verifRegActorTestHarness) generateAndAddVerifierAndVerifiedClient(rt *m
 }
 
 func (h *verifRegActorTestHarness) addVerifiedClient(rt *mock.Runtime, verifier, client address.Address, allowance verifreg.DataCap) {
+	h.addVerifiedClientWithExpiration(rt, verifier, client, allowance, verifreg.NoExpiration)
+}
+
+func (h *verifRegActorTestHarness) addVerifiedClientWithExpiration(rt *mock.Runtime, verifier, client address.Address, allowance verifreg.DataCap, expiration abi.ChainEpoch) {
 	rt.SetCaller(verifier, builtin.VerifiedRegistryActorCodeID)
 	rt.ExpectValidateCallerAny()
 
-	params := &verifreg.AddVerifiedClientParams{client, allowance}
+	params := &verifreg.AddVerifiedClientParams{Address: client, Allowance: allowance, Expiration: expiration}
 	rt.Call(h.AddVerifiedClient, params)
 	rt.Verify()
 
@@ -777,6 +1031,38 @@ func (h *verifRegActorTestHarness) restoreBytes(rt *mock.Runtime, a address.Addr
 	require.EqualValues(h.t, expectedCap.expectedCap, h.getClientCap(rt, a))
 }
 
+func (h *verifRegActorTestHarness) mkRemoveDataCapParams(client address.Address, amount verifreg.DataCap, verifier1, verifier2 address.Address) *verifreg.RemoveDataCapParams {
+	sig := func(verifier address.Address) crypto.Signature {
+		return crypto.Signature{Type: crypto.SigTypeSecp256k1, Data: verifier.Bytes()}
+	}
+	return &verifreg.RemoveDataCapParams{
+		VerifiedClientToRemove: client,
+		DataCapAmountToRemove:  amount,
+		VerifierRequest1:       verifreg.RemoveDataCapRequest{Verifier: verifier1, VerifierSignature: sig(verifier1)},
+		VerifierRequest2:       verifreg.RemoveDataCapRequest{Verifier: verifier2, VerifierSignature: sig(verifier2)},
+	}
+}
+
+func (h *verifRegActorTestHarness) removeDataCap(rt *mock.Runtime, client address.Address, amount verifreg.DataCap,
+	verifier1 address.Address, proposalID1 verifreg.RmDcProposalID, verifier2 address.Address, proposalID2 verifreg.RmDcProposalID) *verifreg.RemoveDataCapReturn {
+	params := h.mkRemoveDataCapParams(client, amount, verifier1, verifier2)
+
+	rt.SetCaller(verifier1, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	rt.ExpectVerifySignature(params.VerifierRequest1.VerifierSignature, verifier1, removeDataCapBytes(h.t, client, amount, proposalID1), nil)
+	rt.ExpectVerifySignature(params.VerifierRequest2.VerifierSignature, verifier2, removeDataCapBytes(h.t, client, amount, proposalID2), nil)
+	ret := rt.Call(h.RemoveVerifiedClientDataCap, params).(*verifreg.RemoveDataCapReturn)
+	rt.Verify()
+	return ret
+}
+
+func removeDataCapBytes(t testing.TB, client address.Address, amount verifreg.DataCap, proposalID verifreg.RmDcProposalID) []byte {
+	proposal := verifreg.RemoveDataCapProposal{VerifiedClient: client, DataCapAmount: amount, RemovalProposalID: proposalID}
+	buf := bytes.NewBufferString(verifreg.SignatureDomainSeparation_RemoveDataCap)
+	require.NoError(t, proposal.MarshalCBOR(buf))
+	return buf.Bytes()
+}
+
 func (h *verifRegActorTestHarness) getVerifierCap(rt *mock.Runtime, a address.Address) verifreg.DataCap {
 	var st verifreg.State
 	rt.GetState(&st)
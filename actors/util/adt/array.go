@@ -12,6 +12,9 @@ import (
 	runtime "github.com/filecoin-project/specs-actors/actors/runtime"
 )
 
+// Returned from iteration callbacks to halt a bounded iteration early.
+var errStopIteration = xerrors.New("stop iteration")
+
 // Array stores a sparse sequence of values in an AMT.
 type Array struct {
 	root  *amt.Root
@@ -80,18 +83,65 @@ func (a *Array) BatchDelete(ix []uint64) error {
 // If the output parameter is nil, deserialization is skipped.
 func (a *Array) ForEach(out runtime.CBORUnmarshaler, fn func(i int64) error) error {
 	return a.root.ForEach(a.store.Context(), func(k uint64, val *cbg.Deferred) error {
-		if out != nil {
-			if deferred, ok := out.(*cbg.Deferred); ok {
-				// fast-path deferred -> deferred to avoid re-decoding.
-				*deferred = *val
-			} else if err := out.UnmarshalCBOR(bytes.NewReader(val.Raw)); err != nil {
-				return err
-			}
+		if err := decodeDeferred(val, out); err != nil {
+			return err
 		}
 		return fn(int64(k))
 	})
 }
 
+// Iterates the entries with indices in the range [from, to), as for ForEach.
+// Subtrees holding only indices below the range are not traversed, and iteration stops at the end of the range.
+func (a *Array) ForEachRange(out runtime.CBORUnmarshaler, from, to uint64, fn func(i int64) error) error {
+	if from >= to {
+		return nil
+	}
+	err := a.root.ForEachAt(a.store.Context(), from, func(k uint64, val *cbg.Deferred) error {
+		if k >= to {
+			return errStopIteration
+		}
+		if err := decodeDeferred(val, out); err != nil {
+			return err
+		}
+		return fn(int64(k))
+	})
+	if err == errStopIteration {
+		return nil
+	}
+	return err
+}
+
+// Iterates at most limit entries with indices at or after cursor, as for ForEach.
+// Returns the cursor from which to continue iteration, and whether any entries may remain after it.
+// Iteration starts from the beginning of the array with a cursor of zero.
+func (a *Array) Page(cursor uint64, limit uint64, out runtime.CBORUnmarshaler, fn func(i int64) error) (uint64, bool, error) {
+	if limit == 0 {
+		return cursor, true, nil
+	}
+	count := uint64(0)
+	next := cursor
+	err := a.root.ForEachAt(a.store.Context(), cursor, func(k uint64, val *cbg.Deferred) error {
+		if count == limit {
+			return errStopIteration
+		}
+		if err := decodeDeferred(val, out); err != nil {
+			return err
+		}
+		if err := fn(int64(k)); err != nil {
+			return err
+		}
+		count++
+		next = k + 1
+		return nil
+	})
+	if err == errStopIteration {
+		return next, true, nil
+	} else if err != nil {
+		return cursor, false, err
+	}
+	return next, false, nil
+}
+
 func (a *Array) Length() uint64 {
 	return a.root.Count
 }
@@ -108,3 +158,16 @@ func (a *Array) Get(k uint64, out runtime.CBORUnmarshaler) (bool, error) {
 		return false, err
 	}
 }
+
+// Deserializes a value into out, which may be nil to skip deserialization.
+func decodeDeferred(val *cbg.Deferred, out runtime.CBORUnmarshaler) error {
+	if out == nil {
+		return nil
+	}
+	if deferred, ok := out.(*cbg.Deferred); ok {
+		// fast-path deferred -> deferred to avoid re-decoding.
+		*deferred = *val
+		return nil
+	}
+	return out.UnmarshalCBOR(bytes.NewReader(val.Raw))
+}
@@ -0,0 +1,215 @@
+package adt
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/abi/big"
+)
+
+// A record of a change to a balance in a JournaledBalanceTable.
+type BalanceJournalEntry struct {
+	Epoch abi.ChainEpoch
+	Delta abi.TokenAmount
+	// A code chosen by the caller describing the change, such as the BalanceLockingReason recorded by
+	// market.JournaledBalances.
+	Reason int64
+}
+
+// A balance table that also records each change to a balance in a journal, from which the balance at any
+// earlier epoch can be rebuilt.
+// The journal is a HAMT mapping each address to an AMT of its entries, in the order the changes were made.
+// Balances present before the journal was started are not accounted for by it, so a journal should start
+// empty alongside an empty table.
+type JournaledBalanceTable struct {
+	table   *BalanceTable
+	journal *BalanceJournal
+	epoch   abi.ChainEpoch
+}
+
+// Interprets a store as a balance table with root `tableRoot` and journal with root `journalRoot`.
+// Changes are journaled at epoch `epoch`.
+func AsJournaledBalanceTable(s Store, tableRoot, journalRoot cid.Cid, epoch abi.ChainEpoch) (*JournaledBalanceTable, error) {
+	table, err := AsBalanceTable(s, tableRoot)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load balance table: %w", err)
+	}
+	journal, err := AsBalanceJournal(s, journalRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaledBalanceTable{table: table, journal: journal, epoch: epoch}, nil
+}
+
+// Returns the root cids of the underlying balance table and journal.
+func (t *JournaledBalanceTable) Root() (cid.Cid, cid.Cid, error) {
+	tableRoot, err := t.table.Root()
+	if err != nil {
+		return cid.Undef, cid.Undef, xerrors.Errorf("failed to flush balance table: %w", err)
+	}
+	journalRoot, err := t.journal.Root()
+	if err != nil {
+		return cid.Undef, cid.Undef, err
+	}
+	return tableRoot, journalRoot, nil
+}
+
+// Gets the current balance for a key.
+func (t *JournaledBalanceTable) Get(key addr.Address) (abi.TokenAmount, error) {
+	return t.table.Get(key)
+}
+
+// Adds an amount to a balance, requiring the resulting balance to be non-negative, and journals the change.
+func (t *JournaledBalanceTable) Add(key addr.Address, value abi.TokenAmount, reason int64) error {
+	entries, err := t.journal.entriesForAppend(key, t.epoch)
+	if err != nil {
+		return err
+	}
+	if err := t.table.Add(key, value); err != nil {
+		return err
+	}
+	return t.journal.append(key, entries, &BalanceJournalEntry{Epoch: t.epoch, Delta: value, Reason: reason})
+}
+
+// Subtracts up to the specified amount from a balance, without reducing the balance below some minimum,
+// and journals the change.
+// Returns the amount subtracted.
+func (t *JournaledBalanceTable) SubtractWithMinimum(key addr.Address, req abi.TokenAmount, floor abi.TokenAmount, reason int64) (abi.TokenAmount, error) {
+	entries, err := t.journal.entriesForAppend(key, t.epoch)
+	if err != nil {
+		return big.Zero(), err
+	}
+	sub, err := t.table.SubtractWithMinimum(key, req, floor)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if err := t.journal.append(key, entries, &BalanceJournalEntry{Epoch: t.epoch, Delta: sub.Neg(), Reason: reason}); err != nil {
+		return big.Zero(), err
+	}
+	return sub, nil
+}
+
+// MustSubtract subtracts the given amount from the account's balance, and journals the change.
+// Returns an error if the account has insufficient balance
+func (t *JournaledBalanceTable) MustSubtract(key addr.Address, req abi.TokenAmount, reason int64) error {
+	entries, err := t.journal.entriesForAppend(key, t.epoch)
+	if err != nil {
+		return err
+	}
+	if err := t.table.MustSubtract(key, req); err != nil {
+		return err
+	}
+	return t.journal.append(key, entries, &BalanceJournalEntry{Epoch: t.epoch, Delta: req.Neg(), Reason: reason})
+}
+
+// Returns the total balance held by the table.
+func (t *JournaledBalanceTable) Total() (abi.TokenAmount, error) {
+	return t.table.Total()
+}
+
+// A journal of the changes to the balances in a JournaledBalanceTable.
+type BalanceJournal Map
+
+// Interprets a store as a balance journal with root `r`.
+func AsBalanceJournal(s Store, r cid.Cid) (*BalanceJournal, error) {
+	m, err := AsMap(s, r)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to load balance journal: %w", err)
+	}
+	return (*BalanceJournal)(m), nil
+}
+
+// Returns the root cid of underlying HAMT.
+func (j *BalanceJournal) Root() (cid.Cid, error) {
+	root, err := (*Map)(j).Root()
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to flush balance journal: %w", err)
+	}
+	return root, nil
+}
+
+// Iterates the journal entries for a key in the order they were recorded.
+// Iteration halts if the function returns an error.
+func (j *BalanceJournal) ForEach(key addr.Address, fn func(entry *BalanceJournalEntry) error) error {
+	entries, found, err := j.entries(key)
+	if err != nil || !found {
+		return err
+	}
+	var entry BalanceJournalEntry
+	return entries.ForEach(&entry, func(int64) error {
+		return fn(&entry)
+	})
+}
+
+// Rebuilds the balance for a key as it was at the end of an epoch, by summing the journaled changes made up to
+// and including that epoch.
+func (j *BalanceJournal) BalanceAt(key addr.Address, epoch abi.ChainEpoch) (abi.TokenAmount, error) {
+	balance := big.Zero()
+	err := j.ForEach(key, func(entry *BalanceJournalEntry) error {
+		// Entries are journaled in epoch order.
+		if entry.Epoch > epoch {
+			return errStopIteration
+		}
+		balance = big.Add(balance, entry.Delta)
+		return nil
+	})
+	if err != nil && err != errStopIteration {
+		return big.Zero(), xerrors.Errorf("failed to iterate balance journal for %v: %w", key, err)
+	}
+	return balance, nil
+}
+
+// Loads the journal entries for a key, or an empty array if there are none, checking that an entry may be
+// appended at an epoch.
+func (j *BalanceJournal) entriesForAppend(key addr.Address, epoch abi.ChainEpoch) (*Array, error) {
+	entries, found, err := j.entries(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return MakeEmptyArray((*Map)(j).store), nil
+	}
+	var last BalanceJournalEntry
+	if _, err := entries.Get(entries.Length()-1, &last); err != nil {
+		return nil, xerrors.Errorf("failed to load last journal entry for %v: %w", key, err)
+	}
+	if epoch < last.Epoch {
+		return nil, xerrors.Errorf("journal entry for %v at epoch %d precedes last entry at %d", key, epoch, last.Epoch)
+	}
+	return entries, nil
+}
+
+// Appends an entry to the journal entries for a key. Entries for no change are not recorded.
+func (j *BalanceJournal) append(key addr.Address, entries *Array, entry *BalanceJournalEntry) error {
+	if entry.Delta.IsZero() {
+		return nil
+	}
+	if err := entries.AppendContinuous(entry); err != nil {
+		return xerrors.Errorf("failed to append journal entry for %v: %w", key, err)
+	}
+	root, err := entries.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush journal entries for %v: %w", key, err)
+	}
+	entriesRoot := cbg.CborCid(root)
+	return (*Map)(j).Put(AddrKey(key), &entriesRoot)
+}
+
+func (j *BalanceJournal) entries(key addr.Address) (*Array, bool, error) {
+	var root cbg.CborCid
+	found, err := (*Map)(j).Get(AddrKey(key), &root)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get journal entries for %v: %w", key, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	entries, err := AsArray((*Map)(j).store, cid.Cid(root))
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load journal entries for %v: %w", key, err)
+	}
+	return entries, true, nil
+}
@@ -0,0 +1,108 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/abi/big"
+	"github.com/filecoin-project/specs-actors/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/mock"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestJournaledBalanceTable(t *testing.T) {
+	rt := mock.NewBuilder(context.Background(), address.Undef).Build(t)
+	store := adt.AsStore(rt)
+	emptyRoot := tutil.MustRoot(t, adt.MakeEmptyMap(store))
+	client := tutil.NewIDAddr(t, 100)
+	provider := tutil.NewIDAddr(t, 101)
+
+	tableRoot, journalRoot := emptyRoot, emptyRoot
+	atEpoch := func(epoch abi.ChainEpoch, fn func(bt *adt.JournaledBalanceTable)) {
+		bt, err := adt.AsJournaledBalanceTable(store, tableRoot, journalRoot, epoch)
+		require.NoError(t, err)
+		fn(bt)
+		tableRoot, journalRoot, err = bt.Root()
+		require.NoError(t, err)
+	}
+
+	atEpoch(1, func(bt *adt.JournaledBalanceTable) {
+		require.NoError(t, bt.Add(client, abi.NewTokenAmount(100), int64(market.ClientCollateral)))
+		require.NoError(t, bt.Add(provider, abi.NewTokenAmount(50), int64(market.ProviderCollateral)))
+	})
+	atEpoch(5, func(bt *adt.JournaledBalanceTable) {
+		require.NoError(t, bt.Add(client, abi.NewTokenAmount(20), int64(market.ClientStorageFee)))
+		require.NoError(t, bt.MustSubtract(client, abi.NewTokenAmount(30), int64(market.ClientCollateral)))
+		// failed changes are not journaled
+		assert.Error(t, bt.MustSubtract(provider, abi.NewTokenAmount(51), int64(market.ProviderCollateral)))
+		assert.Error(t, bt.Add(provider, abi.NewTokenAmount(-51), int64(market.ProviderCollateral)))
+	})
+	atEpoch(9, func(bt *adt.JournaledBalanceTable) {
+		sub, err := bt.SubtractWithMinimum(client, abi.NewTokenAmount(100), abi.NewTokenAmount(40), int64(market.ClientStorageFee))
+		require.NoError(t, err)
+		assert.Equal(t, abi.NewTokenAmount(50), sub)
+		// subtracting nothing records no entry
+		sub, err = bt.SubtractWithMinimum(provider, abi.NewTokenAmount(10), abi.NewTokenAmount(50), int64(market.ProviderCollateral))
+		require.NoError(t, err)
+		assert.True(t, sub.IsZero())
+	})
+
+	journal, err := adt.AsBalanceJournal(store, journalRoot)
+	require.NoError(t, err)
+
+	t.Run("records each change", func(t *testing.T) {
+		var entries []adt.BalanceJournalEntry
+		require.NoError(t, journal.ForEach(client, func(entry *adt.BalanceJournalEntry) error {
+			entries = append(entries, *entry)
+			return nil
+		}))
+		assert.Equal(t, []adt.BalanceJournalEntry{
+			{Epoch: 1, Delta: abi.NewTokenAmount(100), Reason: int64(market.ClientCollateral)},
+			{Epoch: 5, Delta: abi.NewTokenAmount(20), Reason: int64(market.ClientStorageFee)},
+			{Epoch: 5, Delta: abi.NewTokenAmount(-30), Reason: int64(market.ClientCollateral)},
+			{Epoch: 9, Delta: abi.NewTokenAmount(-50), Reason: int64(market.ClientStorageFee)},
+		}, entries)
+
+		count := 0
+		require.NoError(t, journal.ForEach(provider, func(*adt.BalanceJournalEntry) error {
+			count++
+			return nil
+		}))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("rebuilds balances at any epoch", func(t *testing.T) {
+		for epoch, expected := range map[abi.ChainEpoch]int64{0: 0, 1: 100, 4: 100, 5: 90, 8: 90, 9: 40, 100: 40} { //nolint:nomaprange
+			balance, err := journal.BalanceAt(client, epoch)
+			require.NoError(t, err)
+			assert.Equal(t, abi.NewTokenAmount(expected), balance, "epoch %d", epoch)
+		}
+
+		bt, err := adt.AsBalanceTable(store, tableRoot)
+		require.NoError(t, err)
+		current, err := bt.Get(client)
+		require.NoError(t, err)
+		balance, err := journal.BalanceAt(client, 9)
+		require.NoError(t, err)
+		assert.Equal(t, current, balance)
+
+		balance, err = journal.BalanceAt(tutil.NewIDAddr(t, 102), 9)
+		require.NoError(t, err)
+		assert.Equal(t, big.Zero(), balance)
+	})
+
+	t.Run("rejects changes before the last journaled epoch", func(t *testing.T) {
+		bt, err := adt.AsJournaledBalanceTable(store, tableRoot, journalRoot, 8)
+		require.NoError(t, err)
+		assert.Error(t, bt.Add(client, abi.NewTokenAmount(1), int64(market.ClientCollateral)))
+		balance, err := bt.Get(client)
+		require.NoError(t, err)
+		assert.Equal(t, abi.NewTokenAmount(40), balance)
+	})
+}
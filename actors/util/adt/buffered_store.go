@@ -0,0 +1,206 @@
+package adt
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+
+	block "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// Counters describing the effectiveness of a BufferedStore.
+type BufferedStoreMetrics struct {
+	// Reads served from buffered writes or the object cache.
+	Hits uint64
+	// Reads served from the underlying blockstore.
+	Misses uint64
+	// Buffered blocks written to the underlying blockstore by Flush.
+	Flushed uint64
+	// Buffered blocks discarded by Flush because they were not reachable from the root.
+	Discarded uint64
+	// Objects dropped from the object cache, either by a Put or to keep the cache within its size.
+	Evicted uint64
+}
+
+// BufferedStore is a Store that holds written blocks in memory until they are flushed, and caches the objects
+// decoded from the blocks it reads.
+// Much of the state written while executing a message, such as the intermediate nodes of a HAMT modified
+// several times, is superseded before the message completes. Flush writes through only the blocks reachable
+// from the final state root, discarding the rest.
+// Decoded objects are cached by CID and type, so a read of a cached object saves both the blockstore access and
+// the decoding. An object returned by Get is a shallow copy of the cached one, sharing any slices, maps and
+// pointers with it. Since callers modify the objects they read before putting the result, each Put empties the
+// cache; a caller that modifies an object must not read the same block again before its next Put.
+// The cache holds at most a fixed number of objects, and is emptied when full to admit another.
+// Buffered writes are not bounded, but are released on each Flush.
+// A BufferedStore is not safe for concurrent use.
+type BufferedStore struct {
+	ctx       context.Context
+	base      cbor.IpldBlockstore
+	writes    map[cid.Cid][]byte
+	cache     map[cachedObjectKey]reflect.Value
+	cacheSize int
+	metrics   BufferedStoreMetrics
+}
+
+// Identifies an object decoded from a block, since the same block may be decoded into different types.
+type cachedObjectKey struct {
+	c   cid.Cid
+	typ reflect.Type
+}
+
+var _ Store = &BufferedStore{}
+
+// The default number of objects held in a BufferedStore's object cache.
+const DefaultBufferedStoreCacheSize = 4096
+
+// Creates a store buffering writes to a blockstore, with an object cache of at most cacheSize objects.
+func NewBufferedStore(ctx context.Context, base cbor.IpldBlockstore, cacheSize int) *BufferedStore {
+	return &BufferedStore{
+		ctx:       ctx,
+		base:      base,
+		writes:    make(map[cid.Cid][]byte),
+		cache:     make(map[cachedObjectKey]reflect.Value),
+		cacheSize: cacheSize,
+	}
+}
+
+func (s *BufferedStore) Context() context.Context {
+	return s.ctx
+}
+
+func (s *BufferedStore) Get(_ context.Context, c cid.Cid, out interface{}) error {
+	outv := reflect.ValueOf(out)
+	if outv.Kind() != reflect.Ptr || outv.IsNil() {
+		return xerrors.Errorf("cannot decode %v into non-pointer %T", c, out)
+	}
+	key := cachedObjectKey{c, outv.Type()}
+	if cached, ok := s.cache[key]; ok {
+		s.metrics.Hits++
+		outv.Elem().Set(cached)
+		return nil
+	}
+
+	raw, err := s.getRaw(c)
+	if err != nil {
+		return err
+	}
+	if cu, ok := out.(cbg.CBORUnmarshaler); ok {
+		if err := cu.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return cbor.NewSerializationError(err)
+		}
+	} else if err := cbor.DecodeInto(raw, out); err != nil {
+		return err
+	}
+	s.cacheObject(key, outv.Elem())
+	return nil
+}
+
+// Puts an object in the buffer, returning its CID, and empties the object cache. The object is not written to
+// the underlying blockstore until a flush of a root from which it is reachable.
+func (s *BufferedStore) Put(_ context.Context, v interface{}) (cid.Cid, error) {
+	var raw []byte
+	var c cid.Cid
+	if cm, ok := v.(cbg.CBORMarshaler); ok {
+		buf := new(bytes.Buffer)
+		if err := cm.MarshalCBOR(buf); err != nil {
+			return cid.Undef, err
+		}
+		raw = buf.Bytes()
+
+		var err error
+		// Matches the CIDs computed by cbor.BasicIpldStore.
+		prefix := cid.Prefix{Version: 1, Codec: cid.DagCBOR, MhType: mh.BLAKE2B_MIN + 31, MhLength: -1}
+		if c, err = prefix.Sum(raw); err != nil {
+			return cid.Undef, err
+		}
+	} else {
+		nd, err := cbor.WrapObject(v, mh.BLAKE2B_MIN+31, -1)
+		if err != nil {
+			return cid.Undef, err
+		}
+		raw, c = nd.RawData(), nd.Cid()
+	}
+
+	s.writes[c] = raw
+	s.metrics.Evicted += uint64(len(s.cache))
+	s.cache = make(map[cachedObjectKey]reflect.Value)
+	return c, nil
+}
+
+// Flush writes the buffered blocks reachable from root to the underlying blockstore and discards the others.
+func (s *BufferedStore) Flush(root cid.Cid) error {
+	pending := []cid.Cid{root}
+	for len(pending) > 0 {
+		c := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		// Blocks not in the buffer were previously flushed or read from the underlying store, as must be any
+		// blocks they link to.
+		raw, ok := s.writes[c]
+		if !ok {
+			continue
+		}
+		blk, err := block.NewBlockWithCid(raw, c)
+		if err != nil {
+			return xerrors.Errorf("failed to create block %v: %w", c, err)
+		}
+		if err := s.base.Put(blk); err != nil {
+			return xerrors.Errorf("failed to write block %v: %w", c, err)
+		}
+		delete(s.writes, c)
+		s.metrics.Flushed++
+
+		if c.Prefix().Codec != cid.DagCBOR {
+			continue
+		}
+		err = cbg.ScanForLinks(bytes.NewReader(raw), func(link cid.Cid) {
+			pending = append(pending, link)
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to scan block %v for links: %w", c, err)
+		}
+	}
+
+	s.metrics.Discarded += uint64(len(s.writes))
+	s.writes = make(map[cid.Cid][]byte)
+	return nil
+}
+
+// Returns the store's metrics.
+func (s *BufferedStore) Metrics() BufferedStoreMetrics {
+	return s.metrics
+}
+
+func (s *BufferedStore) getRaw(c cid.Cid) ([]byte, error) {
+	if raw, ok := s.writes[c]; ok {
+		s.metrics.Hits++
+		return raw, nil
+	}
+
+	s.metrics.Misses++
+	blk, err := s.base.Get(c)
+	if err != nil {
+		return nil, err
+	}
+	return blk.RawData(), nil
+}
+
+// Caches a shallow copy of a decoded object, first emptying the cache if it is full.
+func (s *BufferedStore) cacheObject(key cachedObjectKey, obj reflect.Value) {
+	if s.cacheSize <= 0 {
+		return
+	}
+	if len(s.cache) >= s.cacheSize {
+		s.metrics.Evicted += uint64(len(s.cache))
+		s.cache = make(map[cachedObjectKey]reflect.Value)
+	}
+	cached := reflect.New(obj.Type()).Elem()
+	cached.Set(obj)
+	s.cache[key] = cached
+}
@@ -0,0 +1,169 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	block "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+)
+
+func TestBufferedStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flushes only reachable blocks", func(t *testing.T) {
+		base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+		store := adt.NewBufferedStore(ctx, base, adt.DefaultBufferedStoreCacheSize)
+
+		// Flushing the map after every put produces many intermediate nodes.
+		m := adt.MakeEmptyMap(store)
+		var root cid.Cid
+		var err error
+		for i := int64(0); i < 200; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, m.Put(adt.IntKey(i), &v))
+			root, err = m.Root()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 0, base.puts)
+
+		require.NoError(t, store.Flush(root))
+		metrics := store.Metrics()
+		assert.Equal(t, uint64(base.puts), metrics.Flushed)
+		assert.Greater(t, metrics.Discarded, metrics.Flushed)
+
+		// The flushed blocks are exactly those a fresh store needs to read the map.
+		fresh := &countingBlockstore{BlockStoreInMemory: base.BlockStoreInMemory}
+		freshStore := adt.WrapStore(ctx, cbor.NewCborStore(fresh))
+		loaded, err := adt.AsMap(freshStore, root)
+		require.NoError(t, err)
+		var v cbg.CborInt
+		require.NoError(t, loaded.ForEach(&v, func(string) error { return nil }))
+		assert.Equal(t, base.puts, len(fresh.read))
+
+		// Later flushes do not rewrite blocks.
+		require.NoError(t, store.Flush(root))
+		assert.Equal(t, metrics.Flushed, store.Metrics().Flushed)
+	})
+
+	t.Run("caches reads", func(t *testing.T) {
+		base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+		v := cbg.CborInt(42)
+		c, err := cbor.NewCborStore(base).Put(ctx, &v)
+		require.NoError(t, err)
+
+		store := adt.NewBufferedStore(ctx, base, adt.DefaultBufferedStoreCacheSize)
+		for i := 0; i < 3; i++ {
+			var out cbg.CborInt
+			require.NoError(t, store.Get(ctx, c, &out))
+			assert.Equal(t, v, out)
+		}
+		assert.Equal(t, adt.BufferedStoreMetrics{Hits: 2, Misses: 1}, store.Metrics())
+
+		// buffered writes are readable before flushing, and computed with the same CIDs as the underlying store
+		w := cbg.CborInt(43)
+		wc, err := store.Put(ctx, &w)
+		require.NoError(t, err)
+		expected, err := cbor.NewCborStore(ipld.NewBlockStoreInMemory()).Put(ctx, &w)
+		require.NoError(t, err)
+		assert.Equal(t, expected, wc)
+
+		var out cbg.CborInt
+		require.NoError(t, store.Get(ctx, wc, &out))
+		assert.Equal(t, w, out)
+		_, err = base.Get(wc)
+		assert.Error(t, err)
+	})
+
+	t.Run("caches objects by type", func(t *testing.T) {
+		base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+		v := cbg.CborInt(42)
+		c, err := cbor.NewCborStore(base).Put(ctx, &v)
+		require.NoError(t, err)
+
+		store := adt.NewBufferedStore(ctx, base, adt.DefaultBufferedStoreCacheSize)
+		var asInt cbg.CborInt
+		require.NoError(t, store.Get(ctx, c, &asInt))
+		var asIface interface{}
+		require.NoError(t, store.Get(ctx, c, &asIface))
+		assert.Equal(t, adt.BufferedStoreMetrics{Misses: 2}, store.Metrics())
+
+		require.NoError(t, store.Get(ctx, c, &asInt))
+		require.NoError(t, store.Get(ctx, c, &asIface))
+		assert.Equal(t, adt.BufferedStoreMetrics{Hits: 2, Misses: 2}, store.Metrics())
+		assert.Equal(t, v, asInt)
+		assert.EqualValues(t, 42, asIface)
+	})
+
+	t.Run("empties the object cache on put", func(t *testing.T) {
+		base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+		v := cbg.CborInt(42)
+		c, err := cbor.NewCborStore(base).Put(ctx, &v)
+		require.NoError(t, err)
+
+		store := adt.NewBufferedStore(ctx, base, adt.DefaultBufferedStoreCacheSize)
+		var out cbg.CborInt
+		require.NoError(t, store.Get(ctx, c, &out))
+
+		w := cbg.CborInt(43)
+		_, err = store.Put(ctx, &w)
+		require.NoError(t, err)
+		assert.Equal(t, adt.BufferedStoreMetrics{Misses: 1, Evicted: 1}, store.Metrics())
+
+		require.NoError(t, store.Get(ctx, c, &out))
+		assert.Equal(t, v, out)
+		assert.Equal(t, adt.BufferedStoreMetrics{Misses: 2, Evicted: 1}, store.Metrics())
+	})
+
+	t.Run("empties the object cache when full", func(t *testing.T) {
+		base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+		cids := make([]cid.Cid, 3)
+		for i := range cids {
+			v := cbg.CborInt(i)
+			var err error
+			cids[i], err = cbor.NewCborStore(base).Put(ctx, &v)
+			require.NoError(t, err)
+		}
+
+		store := adt.NewBufferedStore(ctx, base, 2)
+		var out cbg.CborInt
+		for _, c := range cids {
+			require.NoError(t, store.Get(ctx, c, &out))
+		}
+		assert.Equal(t, adt.BufferedStoreMetrics{Misses: 3, Evicted: 2}, store.Metrics())
+
+		// only the last object remains cached
+		require.NoError(t, store.Get(ctx, cids[2], &out))
+		assert.Equal(t, cbg.CborInt(2), out)
+		require.NoError(t, store.Get(ctx, cids[0], &out))
+		assert.Equal(t, cbg.CborInt(0), out)
+		assert.Equal(t, adt.BufferedStoreMetrics{Hits: 1, Misses: 4, Evicted: 2}, store.Metrics())
+	})
+}
+
+// A blockstore counting the blocks written and read.
+type countingBlockstore struct {
+	*ipld.BlockStoreInMemory
+	puts int
+	read map[cid.Cid]struct{}
+}
+
+func (bs *countingBlockstore) Get(c cid.Cid) (block.Block, error) {
+	if bs.read == nil {
+		bs.read = make(map[cid.Cid]struct{})
+	}
+	bs.read[c] = struct{}{}
+	return bs.BlockStoreInMemory.Get(c)
+}
+
+func (bs *countingBlockstore) Put(b block.Block) error {
+	bs.puts++
+	return bs.BlockStoreInMemory.Put(b)
+}
@@ -0,0 +1,376 @@
+package adt
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	amt "github.com/filecoin-project/go-amt-ipld/v2"
+	cid "github.com/ipfs/go-cid"
+	hamt "github.com/ipfs/go-hamt-ipld"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+)
+
+// The number of index bits consumed by each level of an AMT, matching go-amt-ipld.
+const amtWidthBits = 3
+const amtWidth = 1 << amtWidthBits
+
+// The keys that differ between two versions of a map.
+type MapChanges struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// The indices that differ between two versions of an array.
+type ArrayChanges struct {
+	Added    []uint64
+	Removed  []uint64
+	Modified []uint64
+}
+
+// Returns all changed keys.
+func (c *MapChanges) Keys() []string {
+	keys := make([]string, 0, len(c.Added)+len(c.Removed)+len(c.Modified))
+	keys = append(keys, c.Added...)
+	keys = append(keys, c.Modified...)
+	return append(keys, c.Removed...)
+}
+
+// Returns all changed indices, in increasing order.
+func (c *ArrayChanges) Indices() []uint64 {
+	indices := make([]uint64, 0, len(c.Added)+len(c.Removed)+len(c.Modified))
+	indices = append(indices, c.Added...)
+	indices = append(indices, c.Modified...)
+	indices = append(indices, c.Removed...)
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	return indices
+}
+
+// DiffMap returns the keys added, removed and modified between the HAMT-based maps with roots oldRoot and newRoot.
+// Subtrees with equal CIDs are not traversed, so the cost is proportional to the size of the change rather than
+// the size of the maps.
+func DiffMap(store Store, oldRoot, newRoot cid.Cid) (*MapChanges, error) {
+	changes := new(MapChanges)
+	if oldRoot.Equals(newRoot) {
+		return changes, nil
+	}
+	var oldNode, newNode hamt.Node
+	if err := store.Get(store.Context(), oldRoot, &oldNode); err != nil {
+		return nil, xerrors.Errorf("failed to load map root %v: %w", oldRoot, err)
+	}
+	if err := store.Get(store.Context(), newRoot, &newNode); err != nil {
+		return nil, xerrors.Errorf("failed to load map root %v: %w", newRoot, err)
+	}
+	if err := diffHamtNodes(store, &oldNode, &newNode, changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// DiffArray returns the indices added, removed and modified between the AMT-based arrays with roots oldRoot and
+// newRoot, in increasing order.
+// Subtrees with equal CIDs are not traversed, so the cost is proportional to the size of the change rather than
+// the size of the arrays.
+func DiffArray(store Store, oldRoot, newRoot cid.Cid) (*ArrayChanges, error) {
+	changes := new(ArrayChanges)
+	if oldRoot.Equals(newRoot) {
+		return changes, nil
+	}
+	var oldArr, newArr amt.Root
+	if err := store.Get(store.Context(), oldRoot, &oldArr); err != nil {
+		return nil, xerrors.Errorf("failed to load array root %v: %w", oldRoot, err)
+	}
+	if err := store.Get(store.Context(), newRoot, &newArr); err != nil {
+		return nil, xerrors.Errorf("failed to load array root %v: %w", newRoot, err)
+	}
+	d := arrayDiffer{store: store, changes: changes}
+	if err := d.diffNodes(&oldArr.Node, int(oldArr.Height), &newArr.Node, int(newArr.Height), 0); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+//
+// HAMT
+//
+
+func diffHamtNodes(store Store, oldNode, newNode *hamt.Node, changes *MapChanges) error {
+	width := oldNode.Bitfield.BitLen()
+	if newNode.Bitfield.BitLen() > width {
+		width = newNode.Bitfield.BitLen()
+	}
+	for i := 0; i < width; i++ {
+		oldPtr := hamtPointerAt(oldNode, i)
+		newPtr := hamtPointerAt(newNode, i)
+		if oldPtr == nil && newPtr == nil {
+			continue
+		}
+		if oldPtr != nil && newPtr != nil && oldPtr.Link.Defined() && newPtr.Link.Defined() {
+			if oldPtr.Link.Equals(newPtr.Link) {
+				continue
+			}
+			var oldChild, newChild hamt.Node
+			if err := store.Get(store.Context(), oldPtr.Link, &oldChild); err != nil {
+				return xerrors.Errorf("failed to load map node %v: %w", oldPtr.Link, err)
+			}
+			if err := store.Get(store.Context(), newPtr.Link, &newChild); err != nil {
+				return xerrors.Errorf("failed to load map node %v: %w", newPtr.Link, err)
+			}
+			if err := diffHamtNodes(store, &oldChild, &newChild, changes); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// At most one side is a shard, so the entries below this slot are few: compare them directly.
+		oldKVs, err := hamtPointerKVs(store, oldPtr)
+		if err != nil {
+			return err
+		}
+		newKVs, err := hamtPointerKVs(store, newPtr)
+		if err != nil {
+			return err
+		}
+		for _, kv := range oldKVs {
+			newValue, found := findKV(newKVs, kv.Key)
+			if !found {
+				changes.Removed = append(changes.Removed, string(kv.Key))
+			} else if !bytes.Equal(kv.Value.Raw, newValue.Raw) {
+				changes.Modified = append(changes.Modified, string(kv.Key))
+			}
+		}
+		for _, kv := range newKVs {
+			if _, found := findKV(oldKVs, kv.Key); !found {
+				changes.Added = append(changes.Added, string(kv.Key))
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the pointer for the i'th slot of a HAMT node, or nil if the slot is empty.
+func hamtPointerAt(n *hamt.Node, i int) *hamt.Pointer {
+	if n.Bitfield.Bit(i) == 0 {
+		return nil
+	}
+	idx := 0
+	for j := 0; j < i; j++ {
+		idx += int(n.Bitfield.Bit(j))
+	}
+	return n.Pointers[idx]
+}
+
+// Returns all key-value pairs below a pointer, which may be nil.
+func hamtPointerKVs(store Store, p *hamt.Pointer) ([]*hamt.KV, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if !p.Link.Defined() {
+		return p.KVs, nil
+	}
+	var child hamt.Node
+	if err := store.Get(store.Context(), p.Link, &child); err != nil {
+		return nil, xerrors.Errorf("failed to load map node %v: %w", p.Link, err)
+	}
+	var kvs []*hamt.KV
+	width := child.Bitfield.BitLen()
+	for i := 0; i < width; i++ {
+		childKVs, err := hamtPointerKVs(store, hamtPointerAt(&child, i))
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, childKVs...)
+	}
+	return kvs, nil
+}
+
+func findKV(kvs []*hamt.KV, key []byte) (*cbg.Deferred, bool) {
+	for _, kv := range kvs {
+		if bytes.Equal(kv.Key, key) {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+//
+// AMT
+//
+
+type arrayDiffer struct {
+	store   Store
+	changes *ArrayChanges
+}
+
+// Diffs AMT nodes covering indices from offset, at possibly different heights.
+func (d *arrayDiffer) diffNodes(oldNode *amt.Node, oldHeight int, newNode *amt.Node, newHeight int, offset uint64) error {
+	// A shorter tree covers the indices of the first child of a taller one.
+	if oldHeight > newHeight {
+		return d.diffTaller(oldNode, oldHeight, newNode, newHeight, offset, true)
+	} else if newHeight > oldHeight {
+		return d.diffTaller(newNode, newHeight, oldNode, oldHeight, offset, false)
+	}
+
+	if oldHeight == 0 {
+		for i := uint64(0); i < amtWidth; i++ {
+			oldValue := amtValueAt(oldNode, i)
+			newValue := amtValueAt(newNode, i)
+			switch {
+			case oldValue == nil && newValue == nil:
+			case oldValue == nil:
+				d.changes.Added = append(d.changes.Added, offset+i)
+			case newValue == nil:
+				d.changes.Removed = append(d.changes.Removed, offset+i)
+			case !bytes.Equal(oldValue.Raw, newValue.Raw):
+				d.changes.Modified = append(d.changes.Modified, offset+i)
+			}
+		}
+		return nil
+	}
+
+	childSpan := amtNodesForHeight(oldHeight)
+	for i := uint64(0); i < amtWidth; i++ {
+		oldLink, oldOk := amtLinkAt(oldNode, i)
+		newLink, newOk := amtLinkAt(newNode, i)
+		childOffset := offset + i*childSpan
+		switch {
+		case !oldOk && !newOk:
+		case !oldOk:
+			if err := d.walkLink(newLink, oldHeight-1, childOffset, &d.changes.Added); err != nil {
+				return err
+			}
+		case !newOk:
+			if err := d.walkLink(oldLink, oldHeight-1, childOffset, &d.changes.Removed); err != nil {
+				return err
+			}
+		case !oldLink.Equals(newLink):
+			oldChild, err := d.loadNode(oldLink)
+			if err != nil {
+				return err
+			}
+			newChild, err := d.loadNode(newLink)
+			if err != nil {
+				return err
+			}
+			if err := d.diffNodes(oldChild, oldHeight-1, newChild, oldHeight-1, childOffset); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Diffs a taller node against a shorter one, which covers the indices of the taller node's first child.
+// Indices only in the taller node are added if it is the new node, else removed.
+func (d *arrayDiffer) diffTaller(taller *amt.Node, tallerHeight int, shorter *amt.Node, shorterHeight int, offset uint64, tallerIsOld bool) error {
+	onlyTaller, onlyShorter := &d.changes.Added, &d.changes.Removed
+	if tallerIsOld {
+		onlyTaller, onlyShorter = &d.changes.Removed, &d.changes.Added
+	}
+
+	childSpan := amtNodesForHeight(tallerHeight)
+	for i := uint64(0); i < amtWidth; i++ {
+		link, ok := amtLinkAt(taller, i)
+		if i > 0 {
+			if ok {
+				if err := d.walkLink(link, tallerHeight-1, offset+i*childSpan, onlyTaller); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !ok {
+			if err := d.walkNode(shorter, shorterHeight, offset, onlyShorter); err != nil {
+				return err
+			}
+			continue
+		}
+		child, err := d.loadNode(link)
+		if err != nil {
+			return err
+		}
+		if tallerIsOld {
+			err = d.diffNodes(child, tallerHeight-1, shorter, shorterHeight, offset)
+		} else {
+			err = d.diffNodes(shorter, shorterHeight, child, tallerHeight-1, offset)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Appends the indices of all values below a link to out.
+func (d *arrayDiffer) walkLink(link cid.Cid, height int, offset uint64, out *[]uint64) error {
+	n, err := d.loadNode(link)
+	if err != nil {
+		return err
+	}
+	return d.walkNode(n, height, offset, out)
+}
+
+// Appends the indices of all values below a node to out.
+func (d *arrayDiffer) walkNode(n *amt.Node, height int, offset uint64, out *[]uint64) error {
+	if height == 0 {
+		for i := uint64(0); i < amtWidth; i++ {
+			if amtValueAt(n, i) != nil {
+				*out = append(*out, offset+i)
+			}
+		}
+		return nil
+	}
+	childSpan := amtNodesForHeight(height)
+	for i := uint64(0); i < amtWidth; i++ {
+		if link, ok := amtLinkAt(n, i); ok {
+			if err := d.walkLink(link, height-1, offset+i*childSpan, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *arrayDiffer) loadNode(link cid.Cid) (*amt.Node, error) {
+	var n amt.Node
+	if err := d.store.Get(d.store.Context(), link, &n); err != nil {
+		return nil, xerrors.Errorf("failed to load array node %v: %w", link, err)
+	}
+	return &n, nil
+}
+
+// Returns the position among a node's links or values of the i'th slot, and whether the slot is set.
+func amtSlot(n *amt.Node, i uint64) (int, bool) {
+	bmap := new(big.Int).SetBytes(n.Bmap[:])
+	if bmap.Bit(int(i)) == 0 {
+		return 0, false
+	}
+	idx := 0
+	for j := 0; j < int(i); j++ {
+		idx += int(bmap.Bit(j))
+	}
+	return idx, true
+}
+
+func amtValueAt(n *amt.Node, i uint64) *cbg.Deferred {
+	idx, ok := amtSlot(n, i)
+	if !ok || idx >= len(n.Values) {
+		return nil
+	}
+	return n.Values[idx]
+}
+
+func amtLinkAt(n *amt.Node, i uint64) (cid.Cid, bool) {
+	idx, ok := amtSlot(n, i)
+	if !ok || idx >= len(n.Links) {
+		return cid.Undef, false
+	}
+	return n.Links[idx], true
+}
+
+// The number of indices covered by each child of a node at a height.
+func amtNodesForHeight(height int) uint64 {
+	return 1 << (amtWidthBits * uint(height))
+}
@@ -0,0 +1,172 @@
+package adt_test
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+)
+
+func TestDiffMap(t *testing.T) {
+	store := &countingStore{Store: ipld.NewADTStore(context.Background())}
+	rnd := rand.New(rand.NewSource(1))
+
+	oldValues := make(map[uint64]int64)
+	for i := uint64(0); i < 1000; i++ {
+		oldValues[i] = rnd.Int63()
+	}
+	newValues := make(map[uint64]int64)
+	for k, v := range oldValues { //nolint:nomaprange
+		newValues[k] = v
+	}
+	delete(newValues, 3)
+	delete(newValues, 500)
+	newValues[7]++
+	newValues[999]++
+	newValues[1000] = 1
+	newValues[2000] = 2
+
+	oldRoot := buildMap(t, store, oldValues)
+	newRoot := buildMap(t, store, newValues)
+
+	store.gets = 0
+	changes, err := adt.DiffMap(store, oldRoot, newRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1000, 2000}, sortedKeys(t, changes.Added))
+	assert.Equal(t, []uint64{3, 500}, sortedKeys(t, changes.Removed))
+	assert.Equal(t, []uint64{7, 999}, sortedKeys(t, changes.Modified))
+	// unchanged subtrees are not loaded
+	assert.Less(t, store.gets, 25)
+
+	changes, err = adt.DiffMap(store, newRoot, oldRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{3, 500}, sortedKeys(t, changes.Added))
+	assert.Equal(t, []uint64{1000, 2000}, sortedKeys(t, changes.Removed))
+
+	changes, err = adt.DiffMap(store, oldRoot, oldRoot)
+	require.NoError(t, err)
+	assert.Empty(t, changes.Added)
+	assert.Empty(t, changes.Removed)
+	assert.Empty(t, changes.Modified)
+
+	emptyRoot := buildMap(t, store, nil)
+	changes, err = adt.DiffMap(store, emptyRoot, newRoot)
+	require.NoError(t, err)
+	assert.Len(t, changes.Added, len(newValues))
+}
+
+func TestDiffArray(t *testing.T) {
+	store := &countingStore{Store: ipld.NewADTStore(context.Background())}
+
+	oldValues := make(map[uint64]int64)
+	for i := uint64(0); i < 600; i++ {
+		oldValues[i] = int64(i)
+	}
+
+	t.Run("changes at same height", func(t *testing.T) {
+		newValues := copyValues(oldValues)
+		delete(newValues, 0)
+		delete(newValues, 300)
+		newValues[17] = -1
+		newValues[599] = -1
+		newValues[600] = 600
+
+		oldRoot := buildArray(t, store, oldValues)
+		newRoot := buildArray(t, store, newValues)
+
+		store.gets = 0
+		changes, err := adt.DiffArray(store, oldRoot, newRoot)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{600}, changes.Added)
+		assert.Equal(t, []uint64{0, 300}, changes.Removed)
+		assert.Equal(t, []uint64{17, 599}, changes.Modified)
+		assert.Less(t, store.gets, 30)
+	})
+
+	t.Run("changes in height", func(t *testing.T) {
+		newValues := copyValues(oldValues)
+		newValues[5] = -1
+		newValues[100000] = 1
+		newValues[100001] = 2
+
+		oldRoot := buildArray(t, store, oldValues)
+		newRoot := buildArray(t, store, newValues)
+
+		changes, err := adt.DiffArray(store, oldRoot, newRoot)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{100000, 100001}, changes.Added)
+		assert.Empty(t, changes.Removed)
+		assert.Equal(t, []uint64{5}, changes.Modified)
+
+		changes, err = adt.DiffArray(store, newRoot, oldRoot)
+		require.NoError(t, err)
+		assert.Empty(t, changes.Added)
+		assert.Equal(t, []uint64{100000, 100001}, changes.Removed)
+		assert.Equal(t, []uint64{5}, changes.Modified)
+
+		emptyRoot := buildArray(t, store, nil)
+		changes, err = adt.DiffArray(store, newRoot, emptyRoot)
+		require.NoError(t, err)
+		assert.Len(t, changes.Removed, len(newValues))
+	})
+}
+
+// A store that counts the blocks read.
+type countingStore struct {
+	adt.Store
+	gets int
+}
+
+func (s *countingStore) Get(ctx context.Context, c cid.Cid, out interface{}) error {
+	s.gets++
+	return s.Store.Get(ctx, c, out)
+}
+
+func buildMap(t *testing.T, store adt.Store, values map[uint64]int64) cid.Cid {
+	m := adt.MakeEmptyMap(store)
+	for k, v := range values { //nolint:nomaprange
+		v := cbg.CborInt(v)
+		require.NoError(t, m.Put(adt.UIntKey(k), &v))
+	}
+	root, err := m.Root()
+	require.NoError(t, err)
+	return root
+}
+
+func buildArray(t *testing.T, store adt.Store, values map[uint64]int64) cid.Cid {
+	a := adt.MakeEmptyArray(store)
+	for k, v := range values { //nolint:nomaprange
+		v := cbg.CborInt(v)
+		require.NoError(t, a.Set(k, &v))
+	}
+	root, err := a.Root()
+	require.NoError(t, err)
+	return root
+}
+
+func copyValues(values map[uint64]int64) map[uint64]int64 {
+	c := make(map[uint64]int64, len(values))
+	for k, v := range values { //nolint:nomaprange
+		c[k] = v
+	}
+	return c
+}
+
+func sortedKeys(t *testing.T, keys []string) []uint64 {
+	var ks []uint64
+	for _, k := range keys {
+		i, err := adt.ParseUIntKey(k)
+		require.NoError(t, err)
+		ks = append(ks, i)
+	}
+	sort.Slice(ks, func(i, j int) bool { return ks[i] < ks[j] })
+	return ks
+}
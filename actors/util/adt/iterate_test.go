@@ -0,0 +1,183 @@
+package adt_test
+
+import (
+	"context"
+	"testing"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+)
+
+func TestArrayRangeIteration(t *testing.T) {
+	ctx := context.Background()
+	base := &countingBlockstore{BlockStoreInMemory: ipld.NewBlockStoreInMemory()}
+	store := adt.WrapStore(ctx, cbor.NewCborStore(base))
+
+	arr := adt.MakeEmptyArray(store)
+	for i := uint64(0); i < 1000; i += 2 {
+		v := cbg.CborInt(i)
+		require.NoError(t, arr.Set(i, &v))
+	}
+	root, err := arr.Root()
+	require.NoError(t, err)
+
+	t.Run("for each range", func(t *testing.T) {
+		arr, err := adt.AsArray(store, root)
+		require.NoError(t, err)
+		base.read = nil
+
+		var v cbg.CborInt
+		var visited []int64
+		require.NoError(t, arr.ForEachRange(&v, 901, 910, func(i int64) error {
+			assert.Equal(t, cbg.CborInt(i), v)
+			visited = append(visited, i)
+			return nil
+		}))
+		assert.Equal(t, []int64{902, 904, 906, 908}, visited)
+		// only the nodes on the path to the range are loaded, not the whole array
+		assert.Less(t, len(base.read), 20)
+
+		require.NoError(t, arr.ForEachRange(&v, 10, 10, func(i int64) error {
+			t.Fatal("visited empty range")
+			return nil
+		}))
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		arr, err := adt.AsArray(store, root)
+		require.NoError(t, err)
+
+		var visited []int64
+		cursor, more := uint64(0), true
+		pages := 0
+		for more {
+			cursor, more, err = arr.Page(cursor, 64, nil, func(i int64) error {
+				visited = append(visited, i)
+				return nil
+			})
+			require.NoError(t, err)
+			pages++
+		}
+		assert.Equal(t, 8, pages)
+		require.Len(t, visited, 500)
+		for i, idx := range visited {
+			assert.Equal(t, int64(2*i), idx)
+		}
+
+		// a page ending exactly at the last entry reports none remaining
+		cursor, more, err = arr.Page(990, 5, nil, func(int64) error { return nil })
+		require.NoError(t, err)
+		assert.False(t, more)
+		assert.Equal(t, uint64(999), cursor)
+	})
+}
+
+func TestMapPage(t *testing.T) {
+	ctx := context.Background()
+	store := adt.WrapStore(ctx, cbor.NewCborStore(ipld.NewBlockStoreInMemory()))
+
+	m := adt.MakeEmptyMap(store)
+	for i := int64(0); i < 300; i++ {
+		v := cbg.CborInt(i)
+		require.NoError(t, m.Put(adt.IntKey(i), &v))
+	}
+	_, err := m.Root()
+	require.NoError(t, err)
+	var expected []string
+	require.NoError(t, m.ForEach(nil, func(k string) error {
+		expected = append(expected, k)
+		return nil
+	}))
+
+	collect := func(m *adt.Map, cursor adt.MapCursor, limit uint64) ([]string, adt.MapCursor, bool) {
+		var keys []string
+		var v cbg.CborInt
+		next, more, err := m.Page(cursor, limit, &v, func(k string) error {
+			i, err := adt.ParseIntKey(k)
+			require.NoError(t, err)
+			assert.Equal(t, cbg.CborInt(i), v)
+			keys = append(keys, k)
+			return nil
+		})
+		require.NoError(t, err)
+		return keys, next, more
+	}
+
+	t.Run("pages through all entries", func(t *testing.T) {
+		var visited []string
+		var cursor adt.MapCursor
+		more := true
+		for more {
+			var keys []string
+			keys, cursor, more = collect(m, cursor, 7)
+			assert.LessOrEqual(t, len(keys), 7)
+			visited = append(visited, keys...)
+		}
+		assert.ElementsMatch(t, expected, visited)
+	})
+
+	t.Run("resumes after modification", func(t *testing.T) {
+		first, cursor, more := collect(m, nil, 100)
+		require.True(t, more)
+		require.Len(t, first, 100)
+
+		root, err := m.Root()
+		require.NoError(t, err)
+		mod, err := adt.AsMap(store, root)
+		require.NoError(t, err)
+
+		// remove an entry already visited and one not yet visited, and add many more
+		rest, _, _ := collect(m, cursor, 1000)
+		for _, k := range []string{first[0], rest[0]} {
+			i, err := adt.ParseIntKey(k)
+			require.NoError(t, err)
+			require.NoError(t, mod.Delete(adt.IntKey(i)))
+		}
+		for i := int64(300); i < 600; i++ {
+			v := cbg.CborInt(i)
+			require.NoError(t, mod.Put(adt.IntKey(i), &v))
+		}
+		_, err = mod.Root()
+		require.NoError(t, err)
+
+		resumed, _, more := collect(mod, cursor, 1000)
+		assert.False(t, more)
+		// no entry is visited twice, and every entry present in both versions is visited
+		for _, k := range resumed {
+			assert.NotContains(t, first, k)
+		}
+		for _, k := range rest[1:] {
+			assert.Contains(t, resumed, k)
+		}
+		assert.NotContains(t, resumed, rest[0])
+		assert.Greater(t, len(resumed), len(rest))
+	})
+
+	t.Run("rejects invalid cursor", func(t *testing.T) {
+		_, _, err := m.Page(adt.MapCursor{}, 1, nil, func(string) error { return nil })
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects unflushed changes", func(t *testing.T) {
+		root, err := m.Root()
+		require.NoError(t, err)
+		mod, err := adt.AsMap(store, root)
+		require.NoError(t, err)
+		v := cbg.CborInt(1000)
+		require.NoError(t, mod.Put(adt.IntKey(1000), &v))
+
+		_, _, err = mod.Page(nil, 1, nil, func(string) error { return nil })
+		assert.Error(t, err)
+
+		flushed, err := mod.Root()
+		require.NoError(t, err)
+		_, _, err = mod.Page(nil, 1, nil, func(string) error { return nil })
+		assert.NoError(t, err)
+		assert.NotEqual(t, root, flushed)
+	})
+}
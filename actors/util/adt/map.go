@@ -2,6 +2,7 @@ package adt
 
 import (
 	"bytes"
+	"sort"
 
 	cid "github.com/ipfs/go-cid"
 	hamt "github.com/ipfs/go-hamt-ipld"
@@ -32,6 +33,7 @@ type Map struct {
 	lastCid cid.Cid
 	root    *hamt.Node
 	store   Store
+	dirty   bool // Whether the map has been modified since it was loaded or last flushed by Root.
 }
 
 // AsMap interprets a store as a HAMT-based map with root `r`.
@@ -69,12 +71,14 @@ func (m *Map) Root() (cid.Cid, error) {
 		return cid.Undef, xerrors.Errorf("writing map root object: %w", err)
 	}
 	m.lastCid = c
+	m.dirty = false
 
 	return c, nil
 }
 
 // Put adds value `v` with key `k` to the hamt store.
 func (m *Map) Put(k Keyer, v runtime.CBORMarshaler) error {
+	m.dirty = true
 	if err := m.root.Set(m.store.Context(), k.Key(), v); err != nil {
 		return errors.Wrapf(err, "map put failed set in node %v with key %v value %v", m.lastCid, k.Key(), v)
 	}
@@ -94,6 +98,7 @@ func (m *Map) Get(k Keyer, out runtime.CBORUnmarshaler) (bool, error) {
 
 // Delete removes the value at `k` from the hamt store.
 func (m *Map) Delete(k Keyer) error {
+	m.dirty = true
 	if err := m.root.Delete(m.store.Context(), k.Key()); err != nil {
 		return errors.Wrapf(err, "map delete failed in node %v key %v", m.root, k.Key())
 	}
@@ -126,3 +131,151 @@ func (m *Map) CollectKeys() (out []string, err error) {
 	})
 	return
 }
+
+// An opaque position in the iteration order of a map, from which iteration may be resumed.
+// The nil cursor is the start of the map.
+type MapCursor []byte
+
+// The version prefix of an encoded cursor, followed by the last key visited.
+const mapCursorVersion = 1
+
+// Iterates at most limit entries after cursor, as for ForEach.
+// Returns the cursor from which to continue iteration, and whether any entries may remain after it.
+//
+// Entries are visited in the order of their key hashes, and a cursor records only the last key visited, so a
+// cursor remains valid after the map is modified: resumed iteration visits every entry then present whose key
+// hashes after the cursor's. Subtrees before the cursor are not traversed.
+//
+// The pager reads nodes by link from the store, and never writes to it, so it cannot see changes to the map that
+// have not been flushed by Root. Page returns an error if the map has been modified since it was loaded or flushed.
+func (m *Map) Page(cursor MapCursor, limit uint64, out runtime.CBORUnmarshaler, fn func(key string) error) (MapCursor, bool, error) {
+	var after []byte
+	if cursor != nil {
+		if len(cursor) == 0 || cursor[0] != mapCursorVersion {
+			return nil, false, xerrors.Errorf("invalid map cursor %x", []byte(cursor))
+		}
+		after = cursor[1:]
+	}
+	if limit == 0 {
+		return cursor, true, nil
+	}
+	if m.dirty {
+		return nil, false, xerrors.Errorf("cannot page map %v with unflushed changes", m.lastCid)
+	}
+
+	p := mapPager{store: m.store, limit: limit, out: out, fn: fn, last: cursor}
+	var resume []byte
+	if after != nil {
+		resume = hashKey(after)
+	}
+	err := p.walk(m.root, 0, resume, after)
+	if err == errStopIteration {
+		return p.last, true, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return p.last, false, nil
+}
+
+type mapPager struct {
+	store Store
+	limit uint64
+	out   runtime.CBORUnmarshaler
+	fn    func(key string) error
+	count uint64
+	last  MapCursor
+}
+
+// Visits the entries below a node. If resume is non-nil, it is the hash of the key after, and only entries
+// ordered after that key are visited.
+func (p *mapPager) walk(nd *hamt.Node, depth int, resume []byte, after []byte) error {
+	start := 0
+	if resume != nil {
+		start = hashIndexAt(resume, depth)
+	}
+	for i := start; i < nd.Bitfield.BitLen(); i++ {
+		ptr := hamtPointerAt(nd, i)
+		if ptr == nil {
+			continue
+		}
+		// Only the pointer on the path to the resumed key needs to be filtered; those after it are visited in full.
+		var childResume, childAfter []byte
+		if resume != nil && i == start {
+			childResume, childAfter = resume, after
+		}
+		if ptr.Link.Defined() {
+			var child hamt.Node
+			if err := p.store.Get(p.store.Context(), ptr.Link, &child); err != nil {
+				return xerrors.Errorf("failed to load map node %v: %w", ptr.Link, err)
+			}
+			if err := p.walk(&child, depth+1, childResume, childAfter); err != nil {
+				return err
+			}
+			continue
+		}
+		// Entries in a bucket are visited in hash order, matching the order in which they would be visited if the
+		// bucket were split.
+		kvs := make([]hashedKV, len(ptr.KVs))
+		for j, kv := range ptr.KVs {
+			kvs[j] = hashedKV{kv, hashKey(kv.Key)}
+		}
+		sort.Slice(kvs, func(a, b int) bool { return kvs[a].less(kvs[b].hash, kvs[b].Key) })
+		for _, kv := range kvs {
+			if childResume != nil && !kv.after(childResume, childAfter) {
+				continue
+			}
+			if err := p.visit(kv.KV); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type hashedKV struct {
+	*hamt.KV
+	hash []byte
+}
+
+func (kv *hashedKV) less(hash, key []byte) bool {
+	if c := bytes.Compare(kv.hash, hash); c != 0 {
+		return c < 0
+	}
+	return bytes.Compare(kv.Key, key) < 0
+}
+
+func (kv *hashedKV) after(hash, key []byte) bool {
+	if c := bytes.Compare(kv.hash, hash); c != 0 {
+		return c > 0
+	}
+	return bytes.Compare(kv.Key, key) > 0
+}
+
+func (p *mapPager) visit(kv *hamt.KV) error {
+	if p.count == p.limit {
+		return errStopIteration
+	}
+	if err := decodeDeferred(kv.Value, p.out); err != nil {
+		return err
+	}
+	if err := p.fn(string(kv.Key)); err != nil {
+		return err
+	}
+	p.count++
+	p.last = append(MapCursor{mapCursorVersion}, kv.Key...)
+	return nil
+}
+
+func hashKey(key []byte) []byte {
+	hash := sha256.Sum256(key)
+	return hash[:]
+}
+
+// Returns the index into a HAMT node of the slot holding a key with the given hash, at some depth.
+func hashIndexAt(hash []byte, depth int) int {
+	idx := 0
+	for b := depth * hamtBitwidth; b < (depth+1)*hamtBitwidth; b++ {
+		idx = idx<<1 | int(hash[b/8]>>(7-uint(b%8))&1)
+	}
+	return idx
+}
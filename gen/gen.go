@@ -18,7 +18,9 @@ import (
 	verifreg "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 	puppet "github.com/filecoin-project/specs-actors/actors/puppet"
 
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
 	smoothing "github.com/filecoin-project/specs-actors/actors/util/smoothing"
+	powerhistory "github.com/filecoin-project/specs-actors/support/powerhistory"
 )
 
 func main() {
@@ -53,6 +55,17 @@ func main() {
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/account/cbor_gen.go", "account",
 		// actor state
 		account.State{},
+		account.MultiKeyState{},
+		// method params
+		account.MultiKeyConstructorParams{},
+		account.KeySignature{},
+		account.ThresholdSignature{},
+		account.KeyRotation{},
+		account.RotateKeysParams{},
+		account.VerifyThresholdSignatureParams{},
+		account.KeySend{},
+		account.SendParams{},
+		account.SendReturn{},
 	); err != nil {
 		panic(err)
 	}
@@ -60,20 +73,27 @@ func main() {
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/init/cbor_gen.go", "init",
 		// actor state
 		init_.State{},
+		init_.LegacyState{},
+		init_.ExecPermission{},
 		// method params
 		init_.ConstructorParams{},
 		init_.ExecParams{},
+		init_.Exec2Params{},
+		init_.SetExecCallerParams{},
+		init_.SetExecAnyCallerParams{},
 		init_.ExecReturn{},
 	); err != nil {
 		panic(err)
 	}
 
+	// cron.Entry is encoded by hand in cbor_compat.go.
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/cron/cbor_gen.go", "cron",
 		// actor state
 		cron.State{},
-		cron.Entry{},
 		// method params
 		cron.ConstructorParams{},
+		cron.AddEntryParams{},
+		cron.RemoveEntryParams{},
 	); err != nil {
 		panic(err)
 	}
@@ -81,6 +101,8 @@ func main() {
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/reward/cbor_gen.go", "reward",
 		// actor state
 		reward.State{},
+		reward.MinerRewardTotals{},
+		reward.LegacyState{},
 		// method params
 		reward.AwardBlockRewardParams{},
 		// method returns
@@ -89,22 +111,23 @@ func main() {
 		panic(err)
 	}
 
+	// multisig.State, Transaction, ProposalHashData and ProposeParams are encoded by hand in cbor_compat.go.
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/multisig/cbor_gen.go", "multisig",
 		// actor state
-		multisig.State{},
-		multisig.Transaction{},
-		multisig.ProposalHashData{},
+		multisig.VestingTranche{},
 		// method params
 		multisig.ConstructorParams{},
-		multisig.ProposeParams{},
 		multisig.AddSignerParams{},
 		multisig.RemoveSignerParams{},
 		multisig.TxnIDParams{},
 		multisig.ChangeNumApprovalsThresholdParams{},
 		multisig.SwapSignerParams{},
+		multisig.LockBalanceParams{},
+		multisig.ApproveManyParams{},
 		// method returns
 		multisig.ApproveReturn{},
 		multisig.ProposeReturn{},
+		multisig.ApproveManyReturn{},
 	); err != nil {
 		panic(err)
 	}
@@ -114,9 +137,12 @@ func main() {
 		paych.State{},
 		paych.LaneState{},
 		paych.Merge{},
+		paych.LegacyState{},
+		paych.LegacyLaneState{},
 		// method params
 		paych.ConstructorParams{},
 		paych.UpdateChannelStateParams{},
+		paych.RegisterHTLCParams{},
 		paych.SignedVoucher{},
 		paych.ModVerifyParams{},
 		paych.PaymentVerifyParams{},
@@ -212,12 +238,18 @@ func main() {
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/verifreg/cbor_gen.go", "verifreg",
 		// actor state
 		verifreg.State{},
+		verifreg.AllocationEvent{},
 		// method params
 		verifreg.AddVerifierParams{},
 		verifreg.AddVerifiedClientParams{},
 		verifreg.UseBytesParams{},
 		verifreg.RestoreBytesParams{},
+		verifreg.RemoveDataCapParams{},
+		// method returns
+		verifreg.RemoveDataCapReturn{},
 		// other types
+		verifreg.RemoveDataCapRequest{},
+		verifreg.RemoveDataCapProposal{},
 	); err != nil {
 		panic(err)
 	}
@@ -232,10 +264,24 @@ func main() {
 		panic(err)
 	}
 
+	if err := gen.WriteTupleEncodersToFile("./actors/util/adt/cbor_gen.go", "adt",
+		adt.BalanceJournalEntry{},
+	); err != nil {
+		panic(err)
+	}
+
 	if err := gen.WriteTupleEncodersToFile("./actors/util/smoothing/cbor_gen.go", "smoothing",
 		smoothing.FilterEstimate{},
 	); err != nil {
 		panic(err)
 	}
 
+	if err := gen.WriteTupleEncodersToFile("./support/powerhistory/cbor_gen.go", "powerhistory",
+		powerhistory.State{},
+		powerhistory.MinerHistory{},
+		powerhistory.ClaimChange{},
+	); err != nil {
+		panic(err)
+	}
+
 }
@@ -0,0 +1,266 @@
+// Package car exports and imports self-contained snapshots of state in the CARv1 format.
+//
+// An export contains every block reachable from its roots, including the HAMT and AMT nodes of the adt
+// collections and the roots nested in each builtin actor's state. Links are discovered by scanning each block
+// for CBOR-encoded CIDs, so the walk needs no knowledge of the state schema. Only DAG-CBOR links are followed:
+// other CIDs embedded in state, such as actor code CIDs and sector or piece commitments, do not reference blocks.
+//
+// Blocks are written in depth-first order of first reference, so exporting the same state always produces
+// the same file.
+package car
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+
+	block "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+)
+
+// The CAR format version written and accepted.
+const Version = 1
+
+// The maximum length of a header or block section accepted on import.
+const MaxSectionLength = 1 << 24
+
+// Export writes the blocks reachable from roots to w as a CARv1 file.
+func Export(store adt.Store, roots []cid.Cid, w io.Writer) error {
+	if len(roots) == 0 {
+		return xerrors.Errorf("no roots to export")
+	}
+	bw := bufio.NewWriter(w)
+	if err := writeHeader(bw, roots); err != nil {
+		return xerrors.Errorf("failed to write header: %w", err)
+	}
+
+	err := Walk(store, roots, func(c cid.Cid, raw []byte) error {
+		return writeSection(bw, append(c.Bytes(), raw...))
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Walk calls cb with the CID and data of each distinct block reachable from roots, depth-first.
+func Walk(store adt.Store, roots []cid.Cid, cb func(c cid.Cid, raw []byte) error) error {
+	visited := make(map[cid.Cid]struct{})
+	var visit func(c cid.Cid) error
+	visit = func(c cid.Cid) error {
+		if _, ok := visited[c]; ok || c.Prefix().Codec != cid.DagCBOR {
+			return nil
+		}
+		visited[c] = struct{}{}
+
+		var raw cbg.Deferred
+		if err := store.Get(store.Context(), c, &raw); err != nil {
+			return xerrors.Errorf("failed to load block %v: %w", c, err)
+		}
+		if err := cb(c, raw.Raw); err != nil {
+			return err
+		}
+
+		var links []cid.Cid
+		if err := cbg.ScanForLinks(bytes.NewReader(raw.Raw), func(link cid.Cid) {
+			links = append(links, link)
+		}); err != nil {
+			return xerrors.Errorf("failed to scan block %v for links: %w", c, err)
+		}
+		for _, link := range links {
+			if err := visit(link); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if err := visit(root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import reads a CARv1 file into a blockstore, verifying that each block's data matches its CID.
+// It returns the roots named in the file's header.
+func Import(r io.Reader, bs cbor.IpldBlockstore) ([]cid.Cid, error) {
+	br := bufio.NewReader(r)
+	headerBytes, err := readSection(br)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read header: %w", err)
+	}
+	if headerBytes == nil {
+		return nil, xerrors.Errorf("missing header")
+	}
+	roots, err := readHeader(bytes.NewReader(headerBytes))
+	if err != nil {
+		return nil, xerrors.Errorf("invalid header: %w", err)
+	}
+
+	for {
+		section, err := readSection(br)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read block: %w", err)
+		}
+		if section == nil {
+			return roots, nil
+		}
+
+		n, c, err := cid.CidFromBytes(section)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to read block CID: %w", err)
+		}
+		data := section[n:]
+		actual, err := c.Prefix().Sum(data)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to hash block %v: %w", c, err)
+		}
+		if !actual.Equals(c) {
+			return nil, xerrors.Errorf("block data does not match CID %v, hashes to %v", c, actual)
+		}
+		blk, err := block.NewBlockWithCid(data, c)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to create block %v: %w", c, err)
+		}
+		if err := bs.Put(blk); err != nil {
+			return nil, xerrors.Errorf("failed to store block %v: %w", c, err)
+		}
+	}
+}
+
+// ImportToStore reads a CARv1 file into a new in-memory store, returning the store and the file's roots.
+func ImportToStore(ctx context.Context, r io.Reader) (adt.Store, []cid.Cid, error) {
+	bs := ipld.NewBlockStoreInMemory()
+	roots, err := Import(r, bs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return adt.WrapStore(ctx, cbor.NewCborStore(bs)), roots, nil
+}
+
+// The header is the DAG-CBOR map {"roots": [CID...], "version": 1}, with keys in canonical order.
+func writeHeader(w io.Writer, roots []cid.Cid) error {
+	buf := new(bytes.Buffer)
+	if err := cbg.CborWriteHeader(buf, cbg.MajMap, 2); err != nil {
+		return err
+	}
+	if err := writeString(buf, "roots"); err != nil {
+		return err
+	}
+	if err := cbg.CborWriteHeader(buf, cbg.MajArray, uint64(len(roots))); err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if err := cbg.WriteCid(buf, root); err != nil {
+			return err
+		}
+	}
+	if err := writeString(buf, "version"); err != nil {
+		return err
+	}
+	if err := cbg.CborWriteHeader(buf, cbg.MajUnsignedInt, Version); err != nil {
+		return err
+	}
+	return writeSection(w, buf.Bytes())
+}
+
+func readHeader(r io.Reader) ([]cid.Cid, error) {
+	maj, n, err := cbg.CborReadHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if maj != cbg.MajMap {
+		return nil, xerrors.Errorf("expected map, got major type %d", maj)
+	}
+
+	var roots []cid.Cid
+	version := uint64(0)
+	for i := uint64(0); i < n; i++ {
+		key, err := cbg.ReadString(r)
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "roots":
+			maj, count, err := cbg.CborReadHeader(r)
+			if err != nil {
+				return nil, err
+			}
+			if maj != cbg.MajArray {
+				return nil, xerrors.Errorf("expected roots array, got major type %d", maj)
+			}
+			for j := uint64(0); j < count; j++ {
+				root, err := cbg.ReadCid(r)
+				if err != nil {
+					return nil, xerrors.Errorf("failed to read root: %w", err)
+				}
+				roots = append(roots, root)
+			}
+		case "version":
+			maj, v, err := cbg.CborReadHeader(r)
+			if err != nil {
+				return nil, err
+			}
+			if maj != cbg.MajUnsignedInt {
+				return nil, xerrors.Errorf("expected version integer, got major type %d", maj)
+			}
+			version = v
+		default:
+			return nil, xerrors.Errorf("unexpected header key %q", key)
+		}
+	}
+	if version != Version {
+		return nil, xerrors.Errorf("unsupported version %d", version)
+	}
+	if len(roots) == 0 {
+		return nil, xerrors.Errorf("no roots")
+	}
+	return roots, nil
+}
+
+func writeString(w io.Writer, s string) error {
+	if err := cbg.CborWriteHeader(w, cbg.MajTextString, uint64(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, s)
+	return err
+}
+
+// Writes data prefixed with its length as an unsigned varint.
+func writeSection(w io.Writer, data []byte) error {
+	prefix := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(prefix, uint64(len(data)))
+	if _, err := w.Write(prefix[:n]); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// Reads a length-prefixed section, returning nil at the end of the input.
+func readSection(r *bufio.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if length == 0 || length > MaxSectionLength {
+		return nil, xerrors.Errorf("invalid section length %d", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
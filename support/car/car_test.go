@@ -0,0 +1,122 @@
+package car_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/support/car"
+	"github.com/filecoin-project/specs-actors/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := context.Background()
+	store := ipld.NewADTStore(ctx)
+	powerRoot, marketRoot := buildState(t, store)
+	roots := []cid.Cid{powerRoot, marketRoot}
+
+	var exported bytes.Buffer
+	require.NoError(t, car.Export(store, roots, &exported))
+
+	t.Run("round trip", func(t *testing.T) {
+		imported, importedRoots, err := car.ImportToStore(ctx, bytes.NewReader(exported.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, roots, importedRoots)
+
+		// every block reachable from the roots was exported, including nested collections
+		var expected, actual []cid.Cid
+		require.NoError(t, car.Walk(store, roots, func(c cid.Cid, _ []byte) error {
+			expected = append(expected, c)
+			return nil
+		}))
+		require.NoError(t, car.Walk(imported, importedRoots, func(c cid.Cid, _ []byte) error {
+			actual = append(actual, c)
+			return nil
+		}))
+		assert.Equal(t, expected, actual)
+
+		var st power.State
+		require.NoError(t, imported.Get(ctx, powerRoot, &st))
+		events, err := adt.AsMultimap(imported, st.CronEventQueue)
+		require.NoError(t, err)
+		var event power.CronEvent
+		count := 0
+		require.NoError(t, events.ForEach(adt.IntKey(10), &event, func(int64) error {
+			count++
+			return nil
+		}))
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("export is reproducible", func(t *testing.T) {
+		var again bytes.Buffer
+		require.NoError(t, car.Export(store, roots, &again))
+		assert.Equal(t, exported.Bytes(), again.Bytes())
+	})
+
+	t.Run("rejects corrupt blocks", func(t *testing.T) {
+		corrupt := append([]byte{}, exported.Bytes()...)
+		corrupt[len(corrupt)-1] ^= 0xff
+		_, _, err := car.ImportToStore(ctx, bytes.NewReader(corrupt))
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects missing blocks on export", func(t *testing.T) {
+		missing := tutil.MakeCID("missing", nil)
+		assert.Error(t, car.Export(store, []cid.Cid{missing}, &bytes.Buffer{}))
+	})
+}
+
+// Builds power state with claims and cron events, and market state with a deal proposal whose piece CID
+// is not a block.
+func buildState(t *testing.T, store adt.Store) (cid.Cid, cid.Cid) {
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	emptyArray, err := adt.MakeEmptyArray(store).Root()
+	require.NoError(t, err)
+
+	powerState := power.ConstructState(emptyMap, emptyMap)
+	claims, err := adt.AsMap(store, powerState.Claims)
+	require.NoError(t, err)
+	miner := tutil.NewIDAddr(t, 100)
+	require.NoError(t, claims.Put(adt.AddrKey(miner), &power.Claim{RawBytePower: abi.NewStoragePower(1), QualityAdjPower: abi.NewStoragePower(1)}))
+	powerState.Claims, err = claims.Root()
+	require.NoError(t, err)
+
+	events := adt.MakeEmptyMultimap(store)
+	require.NoError(t, events.Add(adt.IntKey(10), &power.CronEvent{MinerAddr: miner, CallbackPayload: []byte{1}}))
+	require.NoError(t, events.Add(adt.IntKey(10), &power.CronEvent{MinerAddr: miner, CallbackPayload: []byte{2}}))
+	powerState.CronEventQueue, err = events.Root()
+	require.NoError(t, err)
+	powerRoot, err := store.Put(store.Context(), powerState)
+	require.NoError(t, err)
+
+	marketState := market.ConstructState(emptyArray, emptyMap, emptyMap)
+	proposals, err := market.AsDealProposalArray(store, marketState.Proposals)
+	require.NoError(t, err)
+	require.NoError(t, proposals.Set(0, &market.DealProposal{
+		PieceCID:             tutil.MakeCID("piece", &market.PieceCIDPrefix),
+		PieceSize:            128,
+		Client:               tutil.NewIDAddr(t, 101),
+		Provider:             miner,
+		StartEpoch:           1,
+		EndEpoch:             2,
+		StoragePricePerEpoch: abi.NewTokenAmount(0),
+		ProviderCollateral:   abi.NewTokenAmount(0),
+		ClientCollateral:     abi.NewTokenAmount(0),
+	}))
+	marketState.Proposals, err = proposals.Root()
+	require.NoError(t, err)
+	marketRoot, err := store.Put(store.Context(), marketState)
+	require.NoError(t, err)
+	return powerRoot, marketRoot
+}
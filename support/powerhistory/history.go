@@ -0,0 +1,342 @@
+// Package powerhistory records the history of miner power claims by observing successive power actor states.
+//
+// This is an off-chain component: it is never invoked by actors. It is intended for tools that replay chain state
+// epoch by epoch and need to answer queries about a miner's power at some past epoch without retaining every
+// intermediate state tree.
+package powerhistory
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	xerrors "golang.org/x/xerrors"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	power "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+)
+
+// State is the persistent root of a power history.
+type State struct {
+	// Epoch of the most recently recorded power state, or -1 if none has been recorded.
+	LastEpoch abi.ChainEpoch
+	// Claims table of the most recently recorded power state.
+	LastClaims cid.Cid // Map, HAMT[address]power.Claim
+	// History of claim changes for each miner that has ever held a claim.
+	Miners cid.Cid // Map, HAMT[address]MinerHistory
+}
+
+// MinerHistory holds the sequence of changes to a single miner's claim.
+type MinerHistory struct {
+	// Claim changes, keyed by the epoch at which they were observed.
+	Changes cid.Cid // Array, AMT[ChainEpoch]ClaimChange
+	// Epochs at which changes were observed, in ascending order. Indexes the keys of Changes.
+	Epochs cid.Cid // Array, AMT[i]ChainEpoch
+}
+
+// ClaimChange records a change to a miner's claim observed at some epoch.
+type ClaimChange struct {
+	// Change in the claim since the previous record.
+	RawByteDelta    abi.StoragePower
+	QualityAdjDelta abi.StoragePower
+	// Claim after applying the deltas.
+	RawBytePower    abi.StoragePower
+	QualityAdjPower abi.StoragePower
+}
+
+// Recorder accumulates a power history from successive power actor state roots.
+type Recorder struct {
+	store adt.Store
+	st    State
+}
+
+// NewRecorder creates a recorder with an empty history.
+func NewRecorder(store adt.Store) (*Recorder, error) {
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty map: %w", err)
+	}
+	return &Recorder{
+		store: store,
+		st: State{
+			LastEpoch:  abi.ChainEpoch(-1),
+			LastClaims: emptyMap,
+			Miners:     emptyMap,
+		},
+	}, nil
+}
+
+// LoadRecorder loads a recorder from a history root previously returned by Root.
+func LoadRecorder(store adt.Store, root cid.Cid) (*Recorder, error) {
+	r := &Recorder{store: store}
+	if err := store.Get(store.Context(), root, &r.st); err != nil {
+		return nil, xerrors.Errorf("failed to load power history %v: %w", root, err)
+	}
+	return r, nil
+}
+
+// Root writes the history state to the store and returns its CID.
+func (r *Recorder) Root() (cid.Cid, error) {
+	c, err := r.store.Put(r.store.Context(), &r.st)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to write power history: %w", err)
+	}
+	return c, nil
+}
+
+// LastEpoch returns the epoch of the most recently recorded power state, or -1 if none has been recorded.
+func (r *Recorder) LastEpoch() abi.ChainEpoch {
+	return r.st.LastEpoch
+}
+
+// Record observes the power actor state with root `powerStateRoot` as of `epoch`, recording a change for
+// each miner whose claim differs from the previously recorded state.
+// Epochs must be recorded in strictly increasing order, but need not be contiguous.
+func (r *Recorder) Record(epoch abi.ChainEpoch, powerStateRoot cid.Cid) error {
+	if epoch <= r.st.LastEpoch {
+		return xerrors.Errorf("epoch %d not after last recorded epoch %d", epoch, r.st.LastEpoch)
+	}
+
+	var st power.State
+	if err := r.store.Get(r.store.Context(), powerStateRoot, &st); err != nil {
+		return xerrors.Errorf("failed to load power state %v: %w", powerStateRoot, err)
+	}
+
+	// Claims are untouched more often than not, in which case there is nothing to compare.
+	if !st.Claims.Equals(r.st.LastClaims) {
+		if err := r.recordClaims(epoch, st.Claims); err != nil {
+			return err
+		}
+	}
+	r.st.LastEpoch = epoch
+	r.st.LastClaims = st.Claims
+	return nil
+}
+
+// ClaimAt returns a miner's claim as of `epoch`.
+// Returns false if no claim had been observed for the miner by that epoch. A claim that was later removed
+// is reported as a zero claim.
+func (r *Recorder) ClaimAt(miner addr.Address, epoch abi.ChainEpoch) (*power.Claim, bool, error) {
+	if epoch > r.st.LastEpoch {
+		return nil, false, xerrors.Errorf("epoch %d after last recorded epoch %d", epoch, r.st.LastEpoch)
+	}
+
+	miners, err := adt.AsMap(r.store, r.st.Miners)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load miner histories: %w", err)
+	}
+	var hist MinerHistory
+	found, err := miners.Get(adt.AddrKey(miner), &hist)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get history for miner %v: %w", miner, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	changeEpoch, found, err := latestChangeEpoch(r.store, &hist, epoch)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to search history for miner %v: %w", miner, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+
+	changes, err := adt.AsArray(r.store, hist.Changes)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load changes for miner %v: %w", miner, err)
+	}
+	var change ClaimChange
+	found, err = changes.Get(uint64(changeEpoch), &change)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to get change at epoch %d for miner %v: %w", changeEpoch, miner, err)
+	}
+	if !found {
+		return nil, false, xerrors.Errorf("inconsistent history for miner %v: no change at indexed epoch %d", miner, changeEpoch)
+	}
+	return &power.Claim{
+		RawBytePower:    change.RawBytePower,
+		QualityAdjPower: change.QualityAdjPower,
+	}, true, nil
+}
+
+// QualityAdjPowerAt returns a miner's quality adjusted power as of `epoch`, which is zero if the miner
+// held no claim at that epoch.
+func (r *Recorder) QualityAdjPowerAt(miner addr.Address, epoch abi.ChainEpoch) (abi.StoragePower, error) {
+	claim, found, err := r.ClaimAt(miner, epoch)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if !found {
+		return big.Zero(), nil
+	}
+	return claim.QualityAdjPower, nil
+}
+
+// ForEachChange iterates the recorded claim changes for a miner in epoch order.
+func (r *Recorder) ForEachChange(miner addr.Address, fn func(epoch abi.ChainEpoch, change *ClaimChange) error) error {
+	miners, err := adt.AsMap(r.store, r.st.Miners)
+	if err != nil {
+		return xerrors.Errorf("failed to load miner histories: %w", err)
+	}
+	var hist MinerHistory
+	found, err := miners.Get(adt.AddrKey(miner), &hist)
+	if err != nil {
+		return xerrors.Errorf("failed to get history for miner %v: %w", miner, err)
+	}
+	if !found {
+		return nil
+	}
+	changes, err := adt.AsArray(r.store, hist.Changes)
+	if err != nil {
+		return xerrors.Errorf("failed to load changes for miner %v: %w", miner, err)
+	}
+	var change ClaimChange
+	return changes.ForEach(&change, func(i int64) error {
+		return fn(abi.ChainEpoch(i), &change)
+	})
+}
+
+func (r *Recorder) recordClaims(epoch abi.ChainEpoch, claimsRoot cid.Cid) error {
+	prevClaims, err := adt.AsMap(r.store, r.st.LastClaims)
+	if err != nil {
+		return xerrors.Errorf("failed to load previous claims: %w", err)
+	}
+	claims, err := adt.AsMap(r.store, claimsRoot)
+	if err != nil {
+		return xerrors.Errorf("failed to load claims: %w", err)
+	}
+	miners, err := adt.AsMap(r.store, r.st.Miners)
+	if err != nil {
+		return xerrors.Errorf("failed to load miner histories: %w", err)
+	}
+
+	// Record new and changed claims.
+	var claim power.Claim
+	err = claims.ForEach(&claim, func(k string) error {
+		miner, err := addr.NewFromBytes([]byte(k))
+		if err != nil {
+			return err
+		}
+		var prev power.Claim
+		found, err := prevClaims.Get(adt.AddrKey(miner), &prev)
+		if err != nil {
+			return err
+		}
+		if !found {
+			prev = power.Claim{RawBytePower: big.Zero(), QualityAdjPower: big.Zero()}
+		}
+		if !found || !claim.RawBytePower.Equals(prev.RawBytePower) || !claim.QualityAdjPower.Equals(prev.QualityAdjPower) {
+			return appendChange(r.store, miners, miner, epoch, &prev, &claim)
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to record claims at epoch %d: %w", epoch, err)
+	}
+
+	// Record removed claims as a change to zero.
+	var prev power.Claim
+	err = prevClaims.ForEach(&prev, func(k string) error {
+		miner, err := addr.NewFromBytes([]byte(k))
+		if err != nil {
+			return err
+		}
+		found, err := claims.Get(adt.AddrKey(miner), nil)
+		if err != nil {
+			return err
+		}
+		if found {
+			return nil
+		}
+		return appendChange(r.store, miners, miner, epoch, &prev, &power.Claim{RawBytePower: big.Zero(), QualityAdjPower: big.Zero()})
+	})
+	if err != nil {
+		return xerrors.Errorf("failed to record removed claims at epoch %d: %w", epoch, err)
+	}
+
+	if r.st.Miners, err = miners.Root(); err != nil {
+		return xerrors.Errorf("failed to flush miner histories: %w", err)
+	}
+	return nil
+}
+
+func appendChange(store adt.Store, miners *adt.Map, miner addr.Address, epoch abi.ChainEpoch, prev, claim *power.Claim) error {
+	var hist MinerHistory
+	found, err := miners.Get(adt.AddrKey(miner), &hist)
+	if err != nil {
+		return xerrors.Errorf("failed to get history for miner %v: %w", miner, err)
+	}
+
+	var changes, epochs *adt.Array
+	if found {
+		if changes, err = adt.AsArray(store, hist.Changes); err != nil {
+			return xerrors.Errorf("failed to load changes for miner %v: %w", miner, err)
+		}
+		if epochs, err = adt.AsArray(store, hist.Epochs); err != nil {
+			return xerrors.Errorf("failed to load change epochs for miner %v: %w", miner, err)
+		}
+	} else {
+		changes = adt.MakeEmptyArray(store)
+		epochs = adt.MakeEmptyArray(store)
+	}
+
+	change := ClaimChange{
+		RawByteDelta:    big.Sub(claim.RawBytePower, prev.RawBytePower),
+		QualityAdjDelta: big.Sub(claim.QualityAdjPower, prev.QualityAdjPower),
+		RawBytePower:    claim.RawBytePower,
+		QualityAdjPower: claim.QualityAdjPower,
+	}
+	if err = changes.Set(uint64(epoch), &change); err != nil {
+		return xerrors.Errorf("failed to set change at epoch %d for miner %v: %w", epoch, miner, err)
+	}
+	changeEpoch := cbg.CborInt(epoch)
+	if err = epochs.AppendContinuous(&changeEpoch); err != nil {
+		return xerrors.Errorf("failed to append change epoch %d for miner %v: %w", epoch, miner, err)
+	}
+
+	if hist.Changes, err = changes.Root(); err != nil {
+		return xerrors.Errorf("failed to flush changes for miner %v: %w", miner, err)
+	}
+	if hist.Epochs, err = epochs.Root(); err != nil {
+		return xerrors.Errorf("failed to flush change epochs for miner %v: %w", miner, err)
+	}
+	return miners.Put(adt.AddrKey(miner), &hist)
+}
+
+// Finds the latest epoch not after `epoch` at which a miner's claim changed, by binary search of the epoch index.
+func latestChangeEpoch(store adt.Store, hist *MinerHistory, epoch abi.ChainEpoch) (abi.ChainEpoch, bool, error) {
+	epochs, err := adt.AsArray(store, hist.Epochs)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Invariant: entries [0, lo) are <= epoch, entries [hi, len) are > epoch.
+	lo, hi := uint64(0), epochs.Length()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		var e cbg.CborInt
+		found, err := epochs.Get(mid, &e)
+		if err != nil {
+			return 0, false, err
+		}
+		if !found {
+			return 0, false, xerrors.Errorf("missing change epoch index %d", mid)
+		}
+		if abi.ChainEpoch(e) <= epoch {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == 0 {
+		return 0, false, nil
+	}
+
+	var e cbg.CborInt
+	if _, err = epochs.Get(lo-1, &e); err != nil {
+		return 0, false, err
+	}
+	return abi.ChainEpoch(e), true, nil
+}
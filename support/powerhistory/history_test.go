@@ -0,0 +1,175 @@
+package powerhistory_test
+
+import (
+	"context"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	cid "github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	big "github.com/filecoin-project/specs-actors/actors/abi/big"
+	power "github.com/filecoin-project/specs-actors/actors/builtin/power"
+	adt "github.com/filecoin-project/specs-actors/actors/util/adt"
+	ipld "github.com/filecoin-project/specs-actors/support/ipld"
+	"github.com/filecoin-project/specs-actors/support/powerhistory"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+func TestRecorder(t *testing.T) {
+	miner1 := tutil.NewIDAddr(t, 101)
+	miner2 := tutil.NewIDAddr(t, 102)
+	miner3 := tutil.NewIDAddr(t, 103)
+
+	t.Run("empty history", func(t *testing.T) {
+		h := newHarness(t)
+		assert.Equal(t, abi.ChainEpoch(-1), h.rec.LastEpoch())
+
+		_, err := h.rec.QualityAdjPowerAt(miner1, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("records claim changes and answers historic queries", func(t *testing.T) {
+		h := newHarness(t)
+
+		h.setClaim(miner1, 10)
+		h.record(5)
+		h.setClaim(miner2, 20)
+		h.record(8)
+		h.setClaim(miner1, 15)
+		h.record(12)
+		// no change
+		h.record(13)
+		h.removeClaim(miner2)
+		h.record(20)
+
+		h.expectQAPower(miner1, 4, 0)
+		h.expectQAPower(miner1, 5, 10)
+		h.expectQAPower(miner1, 11, 10)
+		h.expectQAPower(miner1, 12, 15)
+		h.expectQAPower(miner1, 20, 15)
+
+		h.expectQAPower(miner2, 7, 0)
+		h.expectQAPower(miner2, 8, 20)
+		h.expectQAPower(miner2, 19, 20)
+		h.expectQAPower(miner2, 20, 0)
+
+		_, found, err := h.rec.ClaimAt(miner3, 20)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		claim, found, err := h.rec.ClaimAt(miner2, 20)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.True(t, claim.QualityAdjPower.IsZero())
+
+		_, err = h.rec.QualityAdjPowerAt(miner1, 21)
+		assert.Error(t, err)
+	})
+
+	t.Run("records deltas between observed claims", func(t *testing.T) {
+		h := newHarness(t)
+
+		h.setClaim(miner1, 10)
+		h.record(1)
+		h.setClaim(miner1, 4)
+		h.record(2)
+		h.removeClaim(miner1)
+		h.record(3)
+
+		var epochs []abi.ChainEpoch
+		var deltas []int64
+		err := h.rec.ForEachChange(miner1, func(epoch abi.ChainEpoch, change *powerhistory.ClaimChange) error {
+			epochs = append(epochs, epoch)
+			deltas = append(deltas, change.QualityAdjDelta.Int64())
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []abi.ChainEpoch{1, 2, 3}, epochs)
+		assert.Equal(t, []int64{10, -6, -4}, deltas)
+	})
+
+	t.Run("rejects non-increasing epochs", func(t *testing.T) {
+		h := newHarness(t)
+		h.record(5)
+
+		root := h.flushPowerState()
+		assert.Error(t, h.rec.Record(5, root))
+		assert.Error(t, h.rec.Record(4, root))
+	})
+
+	t.Run("resumes from a persisted root", func(t *testing.T) {
+		h := newHarness(t)
+		h.setClaim(miner1, 10)
+		h.record(5)
+
+		root, err := h.rec.Root()
+		require.NoError(t, err)
+		h.rec, err = powerhistory.LoadRecorder(h.store, root)
+		require.NoError(t, err)
+		assert.Equal(t, abi.ChainEpoch(5), h.rec.LastEpoch())
+
+		h.setClaim(miner1, 30)
+		h.record(9)
+		h.expectQAPower(miner1, 8, 10)
+		h.expectQAPower(miner1, 9, 30)
+	})
+}
+
+type harness struct {
+	t      *testing.T
+	store  adt.Store
+	rec    *powerhistory.Recorder
+	st     *power.State
+	claims *adt.Map
+}
+
+func newHarness(t *testing.T) *harness {
+	store := ipld.NewADTStore(context.Background())
+	emptyMap, err := adt.MakeEmptyMap(store).Root()
+	require.NoError(t, err)
+	emptyMMap, err := adt.MakeEmptyMultimap(store).Root()
+	require.NoError(t, err)
+
+	rec, err := powerhistory.NewRecorder(store)
+	require.NoError(t, err)
+
+	return &harness{
+		t:      t,
+		store:  store,
+		rec:    rec,
+		st:     power.ConstructState(emptyMap, emptyMMap),
+		claims: adt.MakeEmptyMap(store),
+	}
+}
+
+func (h *harness) setClaim(miner addr.Address, qaPower int64) {
+	claim := power.Claim{RawBytePower: big.NewInt(qaPower), QualityAdjPower: big.NewInt(qaPower)}
+	require.NoError(h.t, h.claims.Put(power.AddrKey(miner), &claim))
+}
+
+func (h *harness) removeClaim(miner addr.Address) {
+	require.NoError(h.t, h.claims.Delete(power.AddrKey(miner)))
+}
+
+func (h *harness) flushPowerState() cid.Cid {
+	var err error
+	h.st.Claims, err = h.claims.Root()
+	require.NoError(h.t, err)
+	root, err := h.store.Put(h.store.Context(), h.st)
+	require.NoError(h.t, err)
+	return root
+}
+
+func (h *harness) record(epoch abi.ChainEpoch) {
+	require.NoError(h.t, h.rec.Record(epoch, h.flushPowerState()))
+	assert.Equal(h.t, epoch, h.rec.LastEpoch())
+}
+
+func (h *harness) expectQAPower(miner addr.Address, epoch abi.ChainEpoch, expected int64) {
+	qaPower, err := h.rec.QualityAdjPowerAt(miner, epoch)
+	require.NoError(h.t, err)
+	assert.Equal(h.t, big.NewInt(expected), qaPower, "miner %v at epoch %d", miner, epoch)
+}
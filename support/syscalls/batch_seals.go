@@ -0,0 +1,128 @@
+// Package syscalls provides reusable implementations of parts of the runtime syscall interface.
+package syscalls
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+
+	addr "github.com/filecoin-project/go-address"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	vmr "github.com/filecoin-project/specs-actors/actors/runtime"
+)
+
+// Verifies a single sector seal proof, returning nil if the proof is valid.
+type SealVerifyFunc func(vi abi.SealVerifyInfo) error
+
+// BatchSealVerifier implements BatchVerifySeals by verifying each proof individually with a single-proof
+// verifier, fanning out over a bounded pool of workers.
+// All other syscalls are delegated to the wrapped implementation.
+type BatchSealVerifier struct {
+	vmr.Syscalls
+	ctx     context.Context
+	verify  SealVerifyFunc
+	workers int
+}
+
+var _ vmr.Syscalls = (*BatchSealVerifier)(nil)
+
+// NewBatchSealVerifier wraps syscalls with a parallel batch seal verifier.
+// Verification is abandoned if ctx is cancelled.
+// If workers is not positive, the number of workers is the number of available CPUs.
+func NewBatchSealVerifier(ctx context.Context, inner vmr.Syscalls, verify SealVerifyFunc, workers int) *BatchSealVerifier {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &BatchSealVerifier{
+		Syscalls: inner,
+		ctx:      ctx,
+		verify:   verify,
+		workers:  workers,
+	}
+}
+
+// BatchVerifySeals verifies all proofs, returning for each miner a slice indicating the validity of each of
+// its proofs, in the order in which they were given.
+// A proof that fails verification is reported as invalid rather than as an error.
+// An error is returned only if verification was cancelled.
+func (s *BatchSealVerifier) BatchVerifySeals(vis map[addr.Address][]abi.SealVerifyInfo) (map[addr.Address][]bool, error) {
+	type job struct {
+		info *abi.SealVerifyInfo
+		out  *bool
+	}
+
+	// Dispatch in a fixed order so that verification is scheduled identically for identical inputs.
+	miners := make([]addr.Address, 0, len(vis))
+	for a := range vis { //nolint:nomaprange
+		miners = append(miners, a)
+	}
+	sort.Slice(miners, func(i, j int) bool {
+		return bytes.Compare(miners[i].Bytes(), miners[j].Bytes()) < 0
+	})
+
+	results := make(map[addr.Address][]bool, len(vis))
+	var jobs []job
+	for _, a := range miners {
+		infos := vis[a]
+		res := make([]bool, len(infos))
+		for i := range infos {
+			jobs = append(jobs, job{&infos[i], &res[i]})
+		}
+		results[a] = res
+	}
+
+	// Each job writes a distinct result slot, so no locking is needed beyond the wait group.
+	queue := make(chan job)
+	var wg sync.WaitGroup
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				*j.out = s.verify(*j.info) == nil
+			}
+		}()
+	}
+
+dispatch:
+	for _, j := range jobs {
+		if s.ctx.Err() != nil {
+			break
+		}
+		select {
+		case queue <- j:
+		case <-s.ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// WithBatchSealVerifier wraps a runtime such that its syscalls verify seal batches in parallel with `verify`,
+// cancelled by the runtime's context.
+func WithBatchSealVerifier(rt vmr.Runtime, verify SealVerifyFunc, workers int) vmr.Runtime {
+	return &batchSealRuntime{
+		Runtime: rt,
+		verify:  verify,
+		workers: workers,
+	}
+}
+
+type batchSealRuntime struct {
+	vmr.Runtime
+	verify  SealVerifyFunc
+	workers int
+}
+
+func (rt *batchSealRuntime) Syscalls() vmr.Syscalls {
+	return NewBatchSealVerifier(rt.Runtime.Context(), rt.Runtime.Syscalls(), rt.verify, rt.workers)
+}
@@ -0,0 +1,131 @@
+package syscalls_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abi "github.com/filecoin-project/specs-actors/actors/abi"
+	"github.com/filecoin-project/specs-actors/support/syscalls"
+	tutil "github.com/filecoin-project/specs-actors/support/testing"
+)
+
+// Accepts proofs for even sector numbers.
+func verifyEven(vi abi.SealVerifyInfo) error {
+	if vi.SectorID.Number%2 != 0 {
+		return errors.New("invalid proof")
+	}
+	return nil
+}
+
+func TestBatchVerifySeals(t *testing.T) {
+	miner1 := tutil.NewIDAddr(t, 101)
+	miner2 := tutil.NewIDAddr(t, 102)
+	miner3 := tutil.NewIDAddr(t, 103)
+
+	t.Run("results are in input order for each miner", func(t *testing.T) {
+		vis := map[addr.Address][]abi.SealVerifyInfo{
+			miner1: sealInfos(miner1, 3, 4, 5, 6),
+			miner2: sealInfos(miner2, 2),
+			miner3: {},
+		}
+		for _, workers := range []int{1, 2, 8} {
+			s := syscalls.NewBatchSealVerifier(context.Background(), nil, verifyEven, workers)
+			res, err := s.BatchVerifySeals(vis)
+			require.NoError(t, err)
+			assert.Equal(t, []bool{false, true, false, true}, res[miner1])
+			assert.Equal(t, []bool{true}, res[miner2])
+			assert.Equal(t, []bool{}, res[miner3])
+			assert.Len(t, res, 3)
+		}
+	})
+
+	t.Run("bounds concurrency by worker count", func(t *testing.T) {
+		var active, maxActive int64
+		verify := func(vi abi.SealVerifyInfo) error {
+			n := atomic.AddInt64(&active, 1)
+			for {
+				m := atomic.LoadInt64(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt64(&active, -1)
+			return nil
+		}
+
+		s := syscalls.NewBatchSealVerifier(context.Background(), nil, verify, 3)
+		_, err := s.BatchVerifySeals(map[addr.Address][]abi.SealVerifyInfo{
+			miner1: sealInfos(miner1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, atomic.LoadInt64(&maxActive), int64(3))
+	})
+
+	t.Run("cancellation aborts verification", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var verified int64
+		verify := func(vi abi.SealVerifyInfo) error {
+			if atomic.AddInt64(&verified, 1) == 2 {
+				cancel()
+			}
+			return nil
+		}
+
+		s := syscalls.NewBatchSealVerifier(ctx, nil, verify, 1)
+		res, err := s.BatchVerifySeals(map[addr.Address][]abi.SealVerifyInfo{
+			miner1: sealInfos(miner1, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9),
+		})
+		assert.Equal(t, context.Canceled, err)
+		assert.Nil(t, res)
+		assert.Less(t, atomic.LoadInt64(&verified), int64(10))
+	})
+}
+
+func BenchmarkBatchVerifySeals(b *testing.B) {
+	// Simulates a proof that takes a fixed time to verify.
+	verify := func(vi abi.SealVerifyInfo) error {
+		time.Sleep(200 * time.Microsecond)
+		return nil
+	}
+
+	vis := map[addr.Address][]abi.SealVerifyInfo{}
+	for m := uint64(0); m < 10; m++ {
+		miner := tutil.NewIDAddr(b, 100+m)
+		vis[miner] = sealInfos(miner, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	}
+
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			s := syscalls.NewBatchSealVerifier(context.Background(), nil, verify, workers)
+			for i := 0; i < b.N; i++ {
+				if _, err := s.BatchVerifySeals(vis); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func sealInfos(miner addr.Address, sectors ...abi.SectorNumber) []abi.SealVerifyInfo {
+	id, err := addr.IDFromAddress(miner)
+	if err != nil {
+		panic(err)
+	}
+	infos := make([]abi.SealVerifyInfo, len(sectors))
+	for i, n := range sectors {
+		infos[i] = abi.SealVerifyInfo{
+			SealProof: abi.RegisteredSealProof_StackedDrg32GiBV1,
+			SectorID:  abi.SectorID{Miner: abi.ActorID(id), Number: n},
+		}
+	}
+	return infos
+}